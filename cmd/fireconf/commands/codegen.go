@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewCodegenCommand creates the codegen command
+func NewCodegenCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "codegen",
+		Usage: "Generate constants for collection names and field paths, and query helpers for each index",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Configuration file path",
+				Value:   "fireconf.yaml",
+			},
+			&cli.StringFlag{
+				Name:  "lang",
+				Usage: "Target language (go)",
+				Value: "go",
+			},
+			&cli.StringFlag{
+				Name:  "package",
+				Usage: "Package name of the generated code (defaults to the name of the output directory)",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file path (prints to stdout if not given)",
+			},
+		},
+		Action: runCodegen,
+	}
+}
+
+func runCodegen(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+
+	if c.String("lang") != "go" {
+		return goerr.New("unsupported language", goerr.V("lang", c.String("lang")))
+	}
+
+	configPath := c.String("config")
+	config, err := fireconf.LoadConfigFromYAML(configPath)
+	if err != nil {
+		return goerr.Wrap(err, "failed to load configuration")
+	}
+	if err := config.Validate(); err != nil {
+		return goerr.Wrap(err, "invalid configuration")
+	}
+
+	outputPath := c.String("output")
+	pkg := c.String("package")
+	if pkg == "" {
+		if outputPath == "" {
+			return goerr.New("package flag is required when writing to stdout")
+		}
+		abs, err := filepath.Abs(outputPath)
+		if err != nil {
+			return goerr.Wrap(err, "failed to resolve output path")
+		}
+		pkg = filepath.Base(filepath.Dir(abs))
+	}
+
+	src, err := config.GenerateGo(pkg)
+	if err != nil {
+		return goerr.Wrap(err, "failed to generate code")
+	}
+
+	if outputPath == "" {
+		fmt.Print(string(src))
+		return nil
+	}
+
+	// #nosec G306 - generated source files should be readable by others
+	if err := os.WriteFile(outputPath, src, 0644); err != nil {
+		return goerr.Wrap(err, "failed to write output file")
+	}
+	logger.Info("Code generated", "output", outputPath, "package", pkg)
+
+	return nil
+}
@@ -2,11 +2,14 @@ package commands
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"os"
 
 	"github.com/m-mizutani/clog"
 	"github.com/m-mizutani/ctxlog"
+	"github.com/m-mizutani/fireconf"
+	"github.com/urfave/cli/v3"
 )
 
 // getLogger gets or creates a logger from context
@@ -19,3 +22,41 @@ func getLogger(ctx context.Context) *slog.Logger {
 		clog.WithLevel(slog.LevelInfo),
 	))
 }
+
+// newLogger creates a logger writing to w at the level selected by the
+// global verbose and debug flags
+func newLogger(c *cli.Command, w io.Writer) *slog.Logger {
+	level := slog.LevelInfo
+	if c.Bool("verbose") {
+		level = slog.LevelDebug
+	}
+	if c.Bool("debug") {
+		level = slog.LevelDebug - 1
+	}
+	return slog.New(clog.New(
+		clog.WithWriter(w),
+		clog.WithLevel(level),
+	))
+}
+
+// apiLimitOptions returns the client options limiting Firestore API calls as
+// selected by the global concurrency and rate-limit flags
+func apiLimitOptions(c *cli.Command) []fireconf.Option {
+	return []fireconf.Option{
+		fireconf.WithConcurrency(c.Int("concurrency")),
+		fireconf.WithRateLimit(c.Float("rate-limit")),
+	}
+}
+
+// configIndexes lists the indexes of every collection of config, e.g. to
+// insert those missing from a configuration file with
+// fireconf.AppendIndexesToYAML
+func configIndexes(config *fireconf.Config) []fireconf.MissingIndex {
+	var indexes []fireconf.MissingIndex
+	for _, col := range config.Collections {
+		for _, idx := range col.Indexes {
+			indexes = append(indexes, fireconf.MissingIndex{Collection: col.Name, Index: idx})
+		}
+	}
+	return indexes
+}
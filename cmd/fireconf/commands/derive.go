@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-yaml"
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewDeriveCommand creates the derive command
+func NewDeriveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "derive",
+		Usage: "Derive required composite indexes from a query description file",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "queries",
+				Aliases: []string{"q"},
+				Usage:   "Query description file path",
+				Value:   "queries.yaml",
+			},
+			&cli.StringFlag{
+				Name:    "merge",
+				Aliases: []string{"m"},
+				Usage:   "Existing configuration file to merge derived indexes into",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file path (defaults to inserting the derived indexes into the --merge file, keeping its comments)",
+			},
+			&cli.BoolFlag{
+				Name:  "stdout",
+				Usage: "Output to stdout instead of file",
+			},
+		},
+		Action: runDerive,
+	}
+}
+
+func runDerive(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+
+	queriesPath := c.String("queries")
+	logger.Info("Reading query description file", "path", queriesPath)
+
+	spec, err := fireconf.LoadQueriesFromYAML(queriesPath)
+	if err != nil {
+		return goerr.Wrap(err, "failed to load queries")
+	}
+
+	var base *fireconf.Config
+	mergePath := c.String("merge")
+	if mergePath != "" {
+		base, err = fireconf.LoadConfigFromYAML(mergePath)
+		if err != nil {
+			return goerr.Wrap(err, "failed to load configuration")
+		}
+	}
+
+	config, err := fireconf.MergeDerivedIndexes(base, spec.Queries)
+	if err != nil {
+		return goerr.Wrap(err, "failed to derive indexes")
+	}
+
+	outputPath := c.String("output")
+	if !c.Bool("stdout") && mergePath != "" && (outputPath == "" || outputPath == mergePath) {
+		// Only the derived indexes are inserted into the merged file, so
+		// that its comments and formatting are kept
+		added, err := fireconf.AppendIndexesToYAML(mergePath, configIndexes(config))
+		if err != nil {
+			return goerr.Wrap(err, "failed to save configuration")
+		}
+		logger.Info("Derived indexes inserted", "output", mergePath, "queries", len(spec.Queries), "added", len(added))
+		return nil
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal configuration")
+	}
+
+	if c.Bool("stdout") || outputPath == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	// #nosec G306 - YAML config files should be readable by others
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return goerr.Wrap(err, "failed to write output file")
+	}
+	logger.Info("Derived indexes written", "output", outputPath, "queries", len(spec.Queries))
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+)
+
+func TestDeriveCommand(t *testing.T) {
+	t.Run("Normal: merge inserts derived indexes keeping comments", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "fireconf.yaml")
+		queriesPath := filepath.Join(dir, "queries.yaml")
+		gt.NoError(t, os.WriteFile(configPath, []byte(`# Production indexes
+collections:
+  - name: orders # customer orders
+    indexes: []
+`), 0644))
+		gt.NoError(t, os.WriteFile(queriesPath, []byte(`queries:
+  - collection: orders
+    equality: [customerId]
+    orderBy:
+      - path: createdAt
+        order: DESCENDING
+`), 0644))
+
+		cmd := NewDeriveCommand()
+		gt.NoError(t, cmd.Run(context.Background(), []string{"derive", "--queries", queriesPath, "--merge", configPath}))
+
+		data, err := os.ReadFile(configPath)
+		gt.NoError(t, err)
+		gt.True(t, strings.HasPrefix(string(data), "# Production indexes\n"))
+		gt.True(t, strings.Contains(string(data), "# customer orders"))
+		gt.True(t, strings.Contains(string(data), "path: customerId"))
+	})
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/m-mizutani/fireconf"
+)
+
+// jsonEvent is a progress event as a JSON line. Fields not relevant to an
+// event type are omitted.
+type jsonEvent struct {
+	Type               string  `json:"type"`
+	Collection         string  `json:"collection"`
+	Name               string  `json:"name,omitempty"`
+	Index              string  `json:"index,omitempty"`
+	Previous           string  `json:"previous,omitempty"`
+	State              string  `json:"state,omitempty"`
+	Kind               string  `json:"kind,omitempty"`
+	Target             string  `json:"target,omitempty"`
+	ElapsedSec         float64 `json:"elapsed_sec,omitempty"`
+	Progress           string  `json:"progress,omitempty"`
+	DocumentsCompleted int64   `json:"documents_completed,omitempty"`
+	DocumentsEstimated int64   `json:"documents_estimated,omitempty"`
+	Field              string  `json:"field,omitempty"`
+	Action             string  `json:"action,omitempty"`
+	Error              string  `json:"error,omitempty"`
+}
+
+// newJSONEventObserver returns an observer writing each event as a JSON line
+// to w
+func newJSONEventObserver(w io.Writer) fireconf.Observer {
+	enc := json.NewEncoder(w)
+	return fireconf.ObserverFunc(func(event fireconf.Event) {
+		var out jsonEvent
+		switch e := event.(type) {
+		case fireconf.CollectionStarted:
+			out = jsonEvent{Type: "collection_started", Collection: e.Collection}
+		case fireconf.CollectionFinished:
+			out = jsonEvent{Type: "collection_finished", Collection: e.Collection}
+			if e.Err != nil {
+				out.Error = e.Err.Error()
+			}
+		case fireconf.IndexCreateSubmitted:
+			out = jsonEvent{Type: "index_create_submitted", Collection: e.Collection, Name: e.Name, Index: e.Index.String()}
+		case fireconf.IndexDeleteSubmitted:
+			out = jsonEvent{Type: "index_delete_submitted", Collection: e.Collection, Name: e.Name, Index: e.Index.String()}
+		case fireconf.IndexStateChanged:
+			out = jsonEvent{Type: "index_state_changed", Collection: e.Collection, Name: e.Name, Previous: e.Previous, State: e.State}
+		case fireconf.WaitingTick:
+			out = jsonEvent{Type: "waiting", Collection: e.Collection, Kind: string(e.Kind), Target: e.Target, ElapsedSec: e.Elapsed.Seconds()}
+			if e.Progress != nil {
+				out.Progress = e.Progress.String()
+				out.DocumentsCompleted = e.Progress.DocumentsCompleted
+				out.DocumentsEstimated = e.Progress.DocumentsEstimated
+			}
+		case fireconf.TTLChanged:
+			out = jsonEvent{Type: "ttl_changed", Collection: e.Collection, Field: e.Field, Action: e.Action}
+		case fireconf.ErrorOccurred:
+			out = jsonEvent{Type: "error", Collection: e.Collection, Error: e.Err.Error()}
+		default:
+			return
+		}
+		// Progress output is best effort and must not fail the migration
+		_ = enc.Encode(out)
+	})
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewExplainQueryCommand creates the explain-query command
+func NewExplainQueryCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "explain-query",
+		Usage: "Check which configured index serves each query",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Configuration file path",
+				Value:   "fireconf.yaml",
+			},
+			&cli.StringFlag{
+				Name:    "queries",
+				Aliases: []string{"q"},
+				Usage:   "Query description file path",
+				Value:   "queries.yaml",
+			},
+		},
+		Action: runExplainQuery,
+	}
+}
+
+func runExplainQuery(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+
+	configPath := c.String("config")
+	logger.Info("Reading configuration file", "path", configPath)
+
+	config, err := fireconf.LoadConfigFromYAML(configPath)
+	if err != nil {
+		return goerr.Wrap(err, "failed to load configuration")
+	}
+
+	spec, err := fireconf.LoadQueriesFromYAML(c.String("queries"))
+	if err != nil {
+		return goerr.Wrap(err, "failed to load queries")
+	}
+
+	report, err := config.CheckQueries(spec.Queries)
+	if err != nil {
+		return goerr.Wrap(err, "failed to check queries")
+	}
+
+	printCoverageReport(report)
+
+	if uncovered := report.Uncovered(); len(uncovered) > 0 {
+		return goerr.New("queries are not served by the configuration", goerr.V("count", len(uncovered)))
+	}
+
+	return nil
+}
+
+// printCoverageReport prints one line per query with the serving index or
+// the reason why the query is not served
+func printCoverageReport(report *fireconf.CoverageReport) {
+	for i, q := range report.Queries {
+		name := q.Query.Name
+		if name == "" {
+			name = fmt.Sprintf("query[%d]", i)
+		}
+
+		switch {
+		case q.SingleField:
+			fmt.Printf("✓ %s (%s): served by single-field indexes\n", name, q.Query.Collection)
+		case q.Served:
+			fmt.Printf("✓ %s (%s): served by %s\n", name, q.Query.Collection, q.Index)
+		default:
+			fmt.Printf("✗ %s (%s): not served: %s\n", name, q.Query.Collection, q.Reason)
+			if q.Required != nil {
+				fmt.Printf("    required index: %s\n", q.Required)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewFromErrorCommand creates the from-error command
+func NewFromErrorCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "from-error",
+		Usage:     "Add indexes from Firestore \"query requires an index\" errors to the configuration",
+		ArgsUsage: "[message or URL...]",
+		Description: "Reads error messages or console URLs from the arguments, the --input file " +
+			"or stdin, decodes the embedded index definitions and appends them to the configuration file.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Configuration file path",
+				Value:   "fireconf.yaml",
+			},
+			&cli.StringFlag{
+				Name:    "input",
+				Aliases: []string{"i"},
+				Usage:   "Log file containing error messages",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show the indexes that would be added without writing the configuration file",
+			},
+		},
+		Action: runFromError,
+	}
+}
+
+func runFromError(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+
+	var text string
+	switch {
+	case c.NArg() > 0:
+		text = strings.Join(c.Args().Slice(), "\n")
+	case c.String("input") != "":
+		data, err := os.ReadFile(c.String("input")) // #nosec G304 - path is provided by user as CLI argument
+		if err != nil {
+			return goerr.Wrap(err, "failed to read input file")
+		}
+		text = string(data)
+	default:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return goerr.Wrap(err, "failed to read stdin")
+		}
+		text = string(data)
+	}
+
+	missing, err := fireconf.ParseIndexErrors(text)
+	if err != nil {
+		return goerr.Wrap(err, "failed to parse index errors")
+	}
+	if len(missing) == 0 {
+		return goerr.New("no index definition found in input")
+	}
+
+	configPath := c.String("config")
+	config, err := fireconf.LoadConfigFromYAML(configPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return goerr.Wrap(err, "failed to load configuration")
+		}
+		logger.Info("Configuration file does not exist, creating it", "path", configPath)
+		config = &fireconf.Config{}
+	}
+
+	added := 0
+	for _, m := range missing {
+		if config.AddIndex(m.Collection, m.Index) {
+			added++
+			fmt.Printf("+ %s: %s\n", m.Collection, m.Index)
+		} else {
+			fmt.Printf("= %s: %s (already configured)\n", m.Collection, m.Index)
+		}
+	}
+
+	if c.Bool("dry-run") || added == 0 {
+		return nil
+	}
+
+	if err := config.Validate(); err != nil {
+		return goerr.Wrap(err, "invalid configuration")
+	}
+	// Only the new indexes are inserted so that comments and formatting of
+	// the file are kept
+	if _, err := fireconf.AppendIndexesToYAML(configPath, missing); err != nil {
+		return goerr.Wrap(err, "failed to save configuration")
+	}
+	logger.Info("Configuration updated", "path", configPath, "added", added)
+
+	return nil
+}
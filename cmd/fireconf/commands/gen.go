@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-yaml"
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewGenCommand creates the gen command
+func NewGenCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "gen",
+		Usage:     "Generate configuration from fireconf struct tags in Go source code",
+		ArgsUsage: "[packages...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "dir",
+				Usage: "Directory the package patterns are relative to",
+				Value: ".",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file path (prints to stdout if not given)",
+			},
+			&cli.BoolFlag{
+				Name:  "stdout",
+				Usage: "Output to stdout instead of file",
+			},
+		},
+		Action: runGen,
+	}
+}
+
+func runGen(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+
+	patterns := c.Args().Slice()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	logger.Info("Reading struct tags", "dir", c.String("dir"), "patterns", patterns)
+	config, err := fireconf.ConfigFromSource(c.String("dir"), patterns...)
+	if err != nil {
+		return goerr.Wrap(err, "failed to generate configuration")
+	}
+	if err := config.Validate(); err != nil {
+		return goerr.Wrap(err, "generated configuration is invalid")
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal configuration")
+	}
+
+	outputPath := c.String("output")
+	if c.Bool("stdout") || outputPath == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	// #nosec G306 - YAML config files should be readable by others
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return goerr.Wrap(err, "failed to write output file")
+	}
+	logger.Info("Configuration generated", "output", outputPath, "collections", len(config.Collections))
+
+	return nil
+}
@@ -60,6 +60,7 @@ func runImport(ctx context.Context, c *cli.Command) error {
 	if credentials := c.String("credentials"); credentials != "" {
 		opts = append(opts, fireconf.WithCredentialsFile(credentials))
 	}
+	opts = append(opts, apiLimitOptions(c)...)
 
 	client, err := fireconf.New(ctx, projectID, databaseID, nil, opts...)
 	if err != nil {
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"text/tabwriter"
+	"time"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewOperationsCommand creates the operations command
+func NewOperationsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "operations",
+		Usage: "List or cancel long-running index and field operations",
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List in-progress index builds and field (single-field index and TTL) changes",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Include finished operations",
+					},
+				},
+				Action: runOperationsList,
+			},
+			{
+				Name:      "cancel",
+				Usage:     "Cancel an in-progress index or field operation",
+				ArgsUsage: "<operation ID>",
+				Description: "Requests cancellation of an operation listed by the list subcommand, " +
+					"e.g. an index build started by mistake. Firestore stops the operation on a " +
+					"best-effort basis.",
+				Action: runOperationsCancel,
+			},
+		},
+	}
+}
+
+func runOperationsList(ctx context.Context, c *cli.Command) error {
+	client, err := newOperationsClient(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	ops, err := client.ListOperations(ctx, c.Bool("all"))
+	if err != nil {
+		return goerr.Wrap(err, "failed to list operations")
+	}
+
+	if len(ops) == 0 {
+		getLogger(ctx).Info("No index or field operations in progress")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "ID\tKIND\tCOLLECTION\tTARGET\tSTATE\tPROGRESS\tSTARTED\n")
+	for _, op := range ops {
+		progress := "-"
+		if op.Progress.DocumentsEstimated > 0 || op.Progress.BytesEstimated > 0 || op.Progress.DocumentsCompleted > 0 {
+			progress = op.Progress.String()
+		}
+		started := "-"
+		if !op.StartTime.IsZero() {
+			started = op.StartTime.Local().Format(time.DateTime)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			op.ID, op.Kind, op.Collection, path.Base(op.Target), op.State, progress, started)
+	}
+	if err := w.Flush(); err != nil {
+		return goerr.Wrap(err, "failed to write operations")
+	}
+
+	return nil
+}
+
+func runOperationsCancel(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return goerr.New("exactly one operation ID is required")
+	}
+
+	client, err := newOperationsClient(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	op, err := client.CancelOperation(ctx, c.Args().First())
+	if err != nil {
+		return goerr.Wrap(err, "failed to cancel operation")
+	}
+
+	getLogger(ctx).Info("Cancellation requested",
+		"id", op.ID,
+		"kind", op.Kind,
+		"collection", op.Collection,
+		"target", op.Target)
+
+	return nil
+}
+
+// newOperationsClient creates a client without configuration for the
+// operations subcommands
+func newOperationsClient(ctx context.Context, c *cli.Command) (*fireconf.Client, error) {
+	projectID := c.String("project")
+	if projectID == "" {
+		return nil, goerr.New("project flag is required for operations command")
+	}
+
+	databaseID := c.String("database")
+	if databaseID == "" {
+		return nil, goerr.New("database flag is required for operations command")
+	}
+
+	opts := []fireconf.Option{
+		fireconf.WithLogger(getLogger(ctx)),
+	}
+	if credentials := c.String("credentials"); credentials != "" {
+		opts = append(opts, fireconf.WithCredentialsFile(credentials))
+	}
+	opts = append(opts, apiLimitOptions(c)...)
+
+	client, err := fireconf.New(ctx, projectID, databaseID, nil, opts...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create client")
+	}
+	return client, nil
+}
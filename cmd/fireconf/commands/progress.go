@@ -0,0 +1,267 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/mattn/go-isatty"
+)
+
+// Progress output modes of the sync command
+const (
+	progressAuto  = "auto"
+	progressTTY   = "tty"
+	progressPlain = "plain"
+)
+
+// useProgressUI tells whether the live progress view should be shown for
+// the given mode. In auto mode, it is shown only when stderr is an
+// interactive terminal and not in CI.
+func useProgressUI(mode string) (bool, error) {
+	switch mode {
+	case progressTTY:
+		return true, nil
+	case progressPlain:
+		return false, nil
+	case progressAuto, "":
+		fd := os.Stderr.Fd()
+		if !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd) {
+			return false, nil
+		}
+		if os.Getenv("CI") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		return true, nil
+	default:
+		return false, goerr.New("invalid progress mode, must be one of auto, tty or plain", goerr.V("mode", mode))
+	}
+}
+
+// progressItem is a line of the progress view below a collection
+type progressItem struct {
+	label    string
+	state    string
+	progress string
+	since    time.Time
+	done     bool
+}
+
+// collectionProgress is a collection of the progress view
+type collectionProgress struct {
+	name     string
+	started  time.Time
+	finished time.Time
+	err      error
+	items    []*progressItem
+	byKey    map[string]*progressItem
+}
+
+// item returns the item for key, adding it with label if missing
+func (c *collectionProgress) item(key, label string, now time.Time) *progressItem {
+	if it, ok := c.byKey[key]; ok {
+		return it
+	}
+	it := &progressItem{label: label, since: now}
+	c.items = append(c.items, it)
+	c.byKey[key] = it
+	return it
+}
+
+// progressUI renders a live view of the collections, indexes and TTL
+// policies being synchronized. It is an observer of Migrate and also an
+// io.Writer for log output, which is printed above the view.
+type progressUI struct {
+	mu          sync.Mutex
+	w           io.Writer
+	collections []*collectionProgress
+	byName      map[string]*collectionProgress
+	lines       int
+	stop        chan struct{}
+	stopped     chan struct{}
+}
+
+// newProgressUI creates a progress view writing to w. Call Start to redraw
+// the view periodically and Stop to render the final state.
+func newProgressUI(w io.Writer) *progressUI {
+	return &progressUI{
+		w:       w,
+		byName:  make(map[string]*collectionProgress),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Start redraws the view every second so that elapsed times are updated
+func (p *progressUI) Start() {
+	go func() {
+		defer close(p.stopped)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				p.redraw()
+				p.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop stops redrawing and leaves the final state of the view on screen
+func (p *progressUI) Stop() {
+	close(p.stop)
+	<-p.stopped
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.redraw()
+	p.lines = 0
+}
+
+// Write prints log output above the view
+func (p *progressUI) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	n, err := p.w.Write(b)
+	p.draw()
+	return n, err
+}
+
+// OnEvent updates the view with a progress event of Migrate
+func (p *progressUI) OnEvent(event fireconf.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	switch e := event.(type) {
+	case fireconf.CollectionStarted:
+		p.collection(e.Collection, now)
+	case fireconf.CollectionFinished:
+		c := p.collection(e.Collection, now)
+		c.finished = now
+		c.err = e.Err
+		for _, it := range c.items {
+			if !it.done && e.Err == nil {
+				it.done = true
+				it.state = doneState(it.state)
+			}
+		}
+	case fireconf.IndexCreateSubmitted:
+		it := p.collection(e.Collection, now).item(e.Name, "+ index "+e.Index.String(), now)
+		it.state = "SUBMITTED"
+	case fireconf.IndexDeleteSubmitted:
+		it := p.collection(e.Collection, now).item(e.Name, "- index "+e.Index.String(), now)
+		it.state = "DELETING"
+	case fireconf.IndexStateChanged:
+		// Indexes built by an earlier run have no create event
+		it := p.collection(e.Collection, now).item(e.Name, "  index "+path.Base(e.Name), now)
+		it.state = e.State
+		it.done = e.State == "READY"
+		if it.done {
+			it.progress = ""
+		}
+	case fireconf.TTLChanged:
+		label := "+ ttl " + e.Field
+		if e.Action == "disable" {
+			label = "- ttl " + e.Field
+		}
+		it := p.collection(e.Collection, now).item("ttl:"+e.Field, label, now)
+		it.state = "SUBMITTED"
+	case fireconf.WaitingTick:
+		// Elapsed times are computed on redraw
+		if e.Progress != nil {
+			if c, ok := p.byName[e.Collection]; ok {
+				if it, ok := c.byKey[e.Target]; ok {
+					it.progress = e.Progress.String()
+				}
+			}
+		}
+	case fireconf.ErrorOccurred:
+		// Reported with CollectionFinished
+	}
+	p.redraw()
+}
+
+// collection returns the collection named name, adding it if missing
+func (p *progressUI) collection(name string, now time.Time) *collectionProgress {
+	if c, ok := p.byName[name]; ok {
+		return c
+	}
+	c := &collectionProgress{name: name, started: now, byKey: make(map[string]*progressItem)}
+	p.collections = append(p.collections, c)
+	p.byName[name] = c
+	return c
+}
+
+// doneState is the state shown for an item of a collection that finished
+// successfully
+func doneState(state string) string {
+	switch state {
+	case "DELETING":
+		return "DELETED"
+	case "SUBMITTED":
+		return "DONE"
+	default:
+		return state
+	}
+}
+
+// redraw replaces the view on screen. Must be called with mu held.
+func (p *progressUI) redraw() {
+	p.clear()
+	p.draw()
+}
+
+// clear erases the view drawn last. Must be called with mu held.
+func (p *progressUI) clear() {
+	if p.lines > 0 {
+		// Move to the first line of the view and erase to the end of screen
+		_, _ = fmt.Fprintf(p.w, "\x1b[%dA\r\x1b[J", p.lines)
+		p.lines = 0
+	}
+}
+
+// draw writes the view below the cursor. Must be called with mu held.
+func (p *progressUI) draw() {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	now := time.Now()
+	for _, c := range p.collections {
+		state, elapsed := "RUNNING", now.Sub(c.started)
+		if !c.finished.IsZero() {
+			state, elapsed = "DONE", c.finished.Sub(c.started)
+			if c.err != nil {
+				state = "FAILED"
+			}
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", c.name, state, formatElapsed(elapsed))
+		for _, it := range c.items {
+			elapsed := ""
+			if !it.done && c.finished.IsZero() {
+				elapsed = formatElapsed(now.Sub(it.since))
+			}
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", it.label, it.state, elapsed, it.progress)
+		}
+	}
+	_ = tw.Flush()
+
+	p.lines = strings.Count(buf.String(), "\n")
+	_, _ = p.w.Write(buf.Bytes())
+}
+
+// formatElapsed formats a duration rounded to seconds
+func formatElapsed(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewScanCommand creates the scan command
+func NewScanCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "scan",
+		Usage:     "Find Firestore queries in source code and check them against the configuration",
+		ArgsUsage: "[packages or paths...]",
+		Description: "With --lang go, the arguments are Go package patterns (default ./...). " +
+			"With --lang js, they are JavaScript/TypeScript files or directories (default .).",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Configuration file path",
+				Value:   "fireconf.yaml",
+			},
+			&cli.StringFlag{
+				Name:    "lang",
+				Aliases: []string{"l"},
+				Usage:   "Source language: go or js",
+				Value:   "go",
+			},
+			&cli.StringFlag{
+				Name:  "dir",
+				Usage: "Directory the Go package patterns are relative to",
+				Value: ".",
+			},
+			&cli.BoolFlag{
+				Name:  "write",
+				Usage: "Add the missing indexes to the configuration file",
+			},
+		},
+		Action: runScan,
+	}
+}
+
+func runScan(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+	args := c.Args().Slice()
+
+	var found []fireconf.SourceQuery
+	var err error
+	switch c.String("lang") {
+	case "go":
+		if len(args) == 0 {
+			args = []string{"./..."}
+		}
+		logger.Info("Scanning Go packages", "dir", c.String("dir"), "patterns", args)
+		found, err = fireconf.ScanGoQueries(c.String("dir"), args...)
+	case "js":
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+		logger.Info("Scanning JavaScript/TypeScript files", "paths", args)
+		found, err = fireconf.ScanJSQueries(args...)
+	default:
+		return goerr.New("unsupported language", goerr.V("lang", c.String("lang")))
+	}
+	if err != nil {
+		return goerr.Wrap(err, "failed to scan source")
+	}
+
+	return checkSourceQueries(ctx, c.String("config"), found, c.Bool("write"))
+}
+
+// checkSourceQueries checks the queries found in source code against the
+// configuration file. With write, indexes for unserved queries are merged
+// into the configuration file; otherwise unserved queries are an error.
+func checkSourceQueries(ctx context.Context, configPath string, found []fireconf.SourceQuery, write bool) error {
+	logger := getLogger(ctx)
+
+	config, err := fireconf.LoadConfigFromYAML(configPath)
+	if err != nil {
+		return goerr.Wrap(err, "failed to load configuration")
+	}
+
+	var queries []fireconf.Query
+	for _, f := range found {
+		if f.Unresolved != "" {
+			fmt.Printf("? %s (%s): skipped: %s\n", f.Position, f.Query.Collection, f.Unresolved)
+			continue
+		}
+		q := f.Query
+		q.Name = f.Position
+		queries = append(queries, q)
+	}
+	logger.Info("Found Firestore queries", "total", len(found), "resolved", len(queries))
+
+	report, err := config.CheckQueries(queries)
+	if err != nil {
+		return goerr.Wrap(err, "failed to check queries")
+	}
+	printCoverageReport(report)
+
+	uncovered := report.Uncovered()
+	if len(uncovered) == 0 {
+		return nil
+	}
+	if !write {
+		return goerr.New("queries are not served by the configuration", goerr.V("count", len(uncovered)))
+	}
+
+	var missing []fireconf.Query
+	for _, u := range uncovered {
+		if u.Query.FindNearest != nil && u.Query.FindNearest.Dimension == 0 {
+			logger.Warn("Skipping vector query with unknown dimension, add the index manually",
+				"position", u.Query.Name, "field", u.Query.FindNearest.Path)
+			continue
+		}
+		missing = append(missing, u.Query)
+	}
+
+	merged, err := fireconf.MergeDerivedIndexes(config, missing)
+	if err != nil {
+		return goerr.Wrap(err, "failed to derive indexes")
+	}
+	// Only the new indexes are inserted, keeping comments and formatting
+	added, err := fireconf.AppendIndexesToYAML(configPath, configIndexes(merged))
+	if err != nil {
+		return goerr.Wrap(err, "failed to save configuration")
+	}
+	logger.Info("Configuration updated", "path", configPath, "queries", len(missing), "added", len(added))
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/gt"
+)
+
+func TestCheckSourceQueries(t *testing.T) {
+	t.Run("Normal: write inserts missing indexes keeping comments", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fireconf.yaml")
+		original := `# Production indexes
+collections:
+  - name: orders # customer orders
+    indexes:
+      - fields:
+          - path: status
+            order: ASCENDING
+          - path: createdAt
+            order: DESCENDING
+`
+		gt.NoError(t, os.WriteFile(path, []byte(original), 0644))
+
+		found := []fireconf.SourceQuery{
+			{
+				Position: "orders.go:10",
+				Query: fireconf.Query{
+					Collection: "orders",
+					Equality:   []string{"customerId"},
+					OrderBy:    []fireconf.QueryOrder{{Path: "total", Order: fireconf.OrderDescending}},
+				},
+			},
+		}
+		gt.NoError(t, checkSourceQueries(context.Background(), path, found, true))
+
+		data, err := os.ReadFile(path)
+		gt.NoError(t, err)
+		gt.True(t, strings.HasPrefix(string(data), "# Production indexes\n"))
+		gt.True(t, strings.Contains(string(data), "- name: orders # customer orders"))
+
+		config, err := fireconf.LoadConfigFromYAML(path)
+		gt.NoError(t, err)
+		gt.Equal(t, len(config.Collections[0].Indexes), 2)
+		gt.Equal(t, config.Collections[0].Indexes[1].Fields[0].Path, "customerId")
+	})
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewStatusCommand creates the status command
+func NewStatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "status",
+		Usage: "Show live index and TTL states compared with the configuration",
+		Description: "Lists the composite indexes and TTL policies of each collection with their state, " +
+			"annotated with whether they are in the configuration file (yaml), only in the " +
+			"configuration (missing), or only in Firestore (unmanaged).",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Configuration file path",
+				Value:   "fireconf.yaml",
+			},
+			&cli.StringSliceFlag{
+				Name:    "collections",
+				Aliases: []string{"col"},
+				Usage:   "Collections to show (configured and discovered collections if not specified)",
+			},
+		},
+		Action: runStatus,
+	}
+}
+
+func runStatus(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+
+	// Check required project flag
+	projectID := c.String("project")
+	if projectID == "" {
+		return goerr.New("project flag is required for status command")
+	}
+
+	// Get database ID
+	databaseID := c.String("database")
+	if databaseID == "" {
+		return goerr.New("database flag is required for status command")
+	}
+
+	configPath := c.String("config")
+	config, err := fireconf.LoadConfigFromYAML(configPath)
+	if err != nil {
+		// Without an explicit configuration file, show everything as unmanaged
+		if c.IsSet("config") || !errors.Is(err, fs.ErrNotExist) {
+			return goerr.Wrap(err, "failed to load configuration")
+		}
+		logger.Warn("Configuration file not found, all indexes are shown as unmanaged", "path", configPath)
+		config = nil
+	}
+
+	// Create fireconf client
+	opts := []fireconf.Option{
+		fireconf.WithLogger(logger),
+	}
+
+	if credentials := c.String("credentials"); credentials != "" {
+		opts = append(opts, fireconf.WithCredentialsFile(credentials))
+	}
+	opts = append(opts, apiLimitOptions(c)...)
+
+	client, err := fireconf.New(ctx, projectID, databaseID, config, opts...)
+	if err != nil {
+		return goerr.Wrap(err, "failed to create client")
+	}
+	defer func() { _ = client.Close() }()
+
+	collections, err := client.Status(ctx, c.StringSlice("collections")...)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get status")
+	}
+
+	unhealthy := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, col := range collections {
+		_, _ = fmt.Fprintf(w, "%s\n", col.Name)
+		if len(col.Indexes) == 0 && len(col.TTL) == 0 {
+			_, _ = fmt.Fprintf(w, "  (no composite indexes or TTL policy)\n")
+		}
+		for _, idx := range col.Indexes {
+			_, _ = fmt.Fprintf(w, "  index\t%s\t%s\t%s\n", idx.Index, stateLabel(idx.State), presenceLabel(idx.Presence))
+			if idx.State == "ERROR" || idx.State == "NEEDS_REPAIR" {
+				unhealthy++
+			}
+		}
+		for _, ttl := range col.TTL {
+			_, _ = fmt.Fprintf(w, "  ttl\t%s\t%s\t%s\n", ttl.Field, stateLabel(ttl.State), presenceLabel(ttl.Presence))
+			if ttl.State == "NEEDS_REPAIR" {
+				unhealthy++
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return goerr.Wrap(err, "failed to write status")
+	}
+
+	if unhealthy > 0 {
+		logger.Warn("Some indexes or TTL policies need repair; run sync with --repair for indexes", "count", unhealthy)
+	}
+
+	return nil
+}
+
+// stateLabel returns the state to show, "-" for missing entries
+func stateLabel(state string) string {
+	if state == "" {
+		return "-"
+	}
+	return state
+}
+
+// presenceLabel returns the annotation of an entry
+func presenceLabel(p fireconf.Presence) string {
+	switch p {
+	case fireconf.PresenceManaged:
+		return "yaml"
+	case fireconf.PresenceMissing:
+		return "missing"
+	default:
+		return "unmanaged"
+	}
+}
@@ -2,6 +2,13 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/m-mizutani/fireconf"
 	"github.com/m-mizutani/goerr/v2"
@@ -24,6 +31,67 @@ func NewSyncCommand() *cli.Command {
 				Name:  "dry-run",
 				Usage: "Show what would be changed without making actual changes",
 			},
+			&cli.BoolFlag{
+				Name:  "create-before-delete",
+				Usage: "Create new indexes and wait for them to be READY before deleting stale ones",
+			},
+			&cli.BoolFlag{
+				Name:  "no-wait",
+				Usage: "Return once changes are submitted without waiting for indexes to be built (see the wait command)",
+			},
+			&cli.BoolFlag{
+				Name:  "wait-ttl",
+				Usage: "Wait for TTL policies to become ACTIVE or cleared instead of only submitting the change",
+			},
+			&cli.BoolFlag{
+				Name:  "repair",
+				Usage: "Recreate indexes in ERROR or NEEDS_REPAIR state",
+			},
+			&cli.IntFlag{
+				Name:  "repair-attempts",
+				Usage: "Maximum number of times an unhealthy index is recreated with --repair",
+				Value: 3,
+			},
+			&cli.BoolFlag{
+				Name:  "continue-on-error",
+				Usage: "Keep processing other collections when one fails and report all failures",
+			},
+			&cli.DurationFlag{
+				Name:  "index-timeout",
+				Usage: "Maximum time to wait for a single index build or deletion or TTL policy change (e.g. 30m, waits indefinitely if not specified)",
+			},
+			&cli.DurationFlag{
+				Name:  "deadline",
+				Usage: "Maximum time of the whole sync, checked while waiting (e.g. 2h, waits indefinitely if not specified)",
+			},
+			&cli.DurationFlag{
+				Name:  "max-poll-interval",
+				Usage: "Maximum delay between polls while waiting (e.g. 1m for indexes that take hours to build)",
+				Value: 10 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:  "journal",
+				Usage: "Journal file recording submitted operations, written when sync is interrupted and read by --resume",
+				Value: ".fireconf-journal.json",
+			},
+			&cli.StringFlag{
+				Name:  "field-index-state",
+				Usage: "File recording the single-field index configuration of TTL fields before their exemption, restored when the TTL policy is removed",
+				Value: ".fireconf-field-indexes.json",
+			},
+			&cli.BoolFlag{
+				Name:  "resume",
+				Usage: "Wait for the indexes still building when a previous sync was interrupted, then sync",
+			},
+			&cli.BoolFlag{
+				Name:  "events",
+				Usage: "Write progress events as JSON lines to stdout",
+			},
+			&cli.StringFlag{
+				Name:  "progress",
+				Usage: "Progress output: auto (live view when stderr is a terminal), tty or plain (logs only)",
+				Value: progressAuto,
+			},
 		},
 		Action: runSync,
 	}
@@ -59,15 +127,81 @@ func runSync(ctx context.Context, c *cli.Command) error {
 		return goerr.New("database flag is required for sync command")
 	}
 
+	if c.Bool("no-wait") && c.Bool("wait-ttl") {
+		return goerr.New("--no-wait and --wait-ttl cannot be used together")
+	}
+
+	// The first SIGINT or SIGTERM stops submitting operations and saves the
+	// journal; a second one terminates immediately
+	sigCtx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	go func() {
+		// Restore the default handling so that the next signal terminates
+		// the process while the journal is being saved
+		<-sigCtx.Done()
+		stopSignals()
+	}()
+
+	showProgress, err := useProgressUI(c.String("progress"))
+	if err != nil {
+		return err
+	}
+
+	journalPath := c.String("journal")
+	journal := fireconf.NewJournal(projectID, databaseID)
+
+	statePath := c.String("field-index-state")
+	state, err := loadFieldIndexState(statePath, projectID, databaseID)
+	if err != nil {
+		return err
+	}
+
+	observers := []fireconf.Observer{journal}
+	if c.Bool("events") {
+		observers = append(observers, newJSONEventObserver(os.Stdout))
+	}
+	// Nothing is waited for in dry-run and no-wait modes, so plain logs are enough
+	if showProgress && !c.Bool("dry-run") && !c.Bool("no-wait") {
+		ui := newProgressUI(os.Stderr)
+		logger = newLogger(c, ui)
+		observers = append(observers, ui)
+		ui.Start()
+		defer ui.Stop()
+	}
+
 	// Create fireconf client
 	opts := []fireconf.Option{
 		fireconf.WithLogger(logger),
 		fireconf.WithDryRun(c.Bool("dry-run")),
+		fireconf.WithCreateBeforeDelete(c.Bool("create-before-delete")),
+		fireconf.WithNoWait(c.Bool("no-wait")),
+		fireconf.WithWaitTTL(c.Bool("wait-ttl")),
+		fireconf.WithContinueOnError(c.Bool("continue-on-error")),
+		fireconf.WithFieldIndexState(state),
+		fireconf.WithWaitStrategy(fireconf.WaitStrategy{
+			MaxBackoff:   c.Duration("max-poll-interval"),
+			IndexTimeout: c.Duration("index-timeout"),
+			Deadline:     c.Duration("deadline"),
+		}),
+	}
+
+	if c.Bool("repair") {
+		if c.Int("repair-attempts") < 1 {
+			return goerr.New("repair-attempts must be at least 1", goerr.V("value", c.Int("repair-attempts")))
+		}
+		opts = append(opts, fireconf.WithRepair(c.Int("repair-attempts")))
 	}
 
+	opts = append(opts, fireconf.WithObserver(fireconf.ObserverFunc(func(e fireconf.Event) {
+		for _, o := range observers {
+			o.OnEvent(e)
+		}
+	})))
+
 	if credentials := c.String("credentials"); credentials != "" {
 		opts = append(opts, fireconf.WithCredentialsFile(credentials))
 	}
+	opts = append(opts, apiLimitOptions(c)...)
 
 	client, err := fireconf.New(ctx, projectID, databaseID, config, opts...)
 	if err != nil {
@@ -79,12 +213,184 @@ func runSync(ctx context.Context, c *cli.Command) error {
 	if c.Bool("dry-run") {
 		logger.Info("Running in dry-run mode")
 	}
-	if err := client.Migrate(ctx); err != nil {
-		return goerr.Wrap(err, "migration failed")
-	}
+	result, err := executeSync(ctx, sigCtx, logger, client, journal, journalPath, c.Bool("resume"))
+	// Exemptions submitted before a failure are recorded as well
 	if !c.Bool("dry-run") {
-		logger.Info("Configuration applied successfully")
+		if saveErr := saveFieldIndexState(state, statePath); saveErr != nil {
+			if err != nil {
+				logger.Error("Failed to save field index state", "path", statePath, "error", saveErr)
+				return err
+			}
+			return saveErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	summary := summarizeSyncResult(result)
+	switch {
+	case c.Bool("dry-run"):
+		logger.Info("Dry run completed", summary...)
+	case c.Bool("no-wait"):
+		logger.Info("Configuration submitted; run the wait command to wait for indexes to be built", summary...)
+	default:
+		logger.Info("Configuration applied successfully", summary...)
+	}
+
+	return nil
+}
+
+// syncClient is the part of fireconf.Client used by the sync command
+type syncClient interface {
+	Resume(ctx context.Context, journal *fireconf.Journal) error
+	MigrateWithResult(ctx context.Context) (*fireconf.SyncResult, error)
+}
+
+// executeSync resumes the interrupted sync recorded at journalPath if
+// requested and migrates. sigCtx is done once the sync is interrupted, in
+// which case journal is saved to journalPath. The journal of an earlier
+// interrupted sync is only removed by a successful resume: a plain sync does
+// not wait for indexes that are already building, so it is their only record.
+func executeSync(ctx, sigCtx context.Context, logger *slog.Logger, client syncClient, journal *fireconf.Journal, journalPath string, resume bool) (*fireconf.SyncResult, error) {
+	if resume {
+		if err := resumeSync(sigCtx, logger, client, journalPath, journal.Project, journal.Database); err != nil {
+			return nil, err
+		}
+	} else if _, err := os.Stat(journalPath); err == nil {
+		logger.Warn("Found the journal of an interrupted sync; use --resume to wait for its indexes", "journal", journalPath)
+	}
+
+	result, err := client.MigrateWithResult(sigCtx)
+	if err != nil {
+		if sigCtx.Err() != nil && ctx.Err() == nil {
+			return nil, saveInterruptedSync(logger, journal, journalPath)
+		}
+		return nil, goerr.Wrap(err, "migration failed")
+	}
+	return result, nil
+}
+
+// resumeSync waits for the indexes that were still building when the sync
+// recorded in the journal was interrupted
+func resumeSync(ctx context.Context, logger *slog.Logger, client syncClient, path, projectID, databaseID string) error {
+	journal, err := fireconf.LoadJournal(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		logger.Info("No journal of an interrupted sync found, nothing to resume", "journal", path)
+		return nil
+	}
+	if err != nil {
+		return goerr.Wrap(err, "failed to load journal")
+	}
+	if journal.Project != projectID || journal.Database != databaseID {
+		return goerr.New("journal was written for another database",
+			goerr.V("journal", path),
+			goerr.V("project", journal.Project),
+			goerr.V("database", journal.Database))
+	}
+
+	building := journal.Building()
+	logger.Info("Resuming interrupted sync",
+		"journal", path,
+		"interruptedAt", journal.SavedAt.Local().Format(time.DateTime),
+		"building", len(building))
+
+	if err := client.Resume(ctx, journal); err != nil {
+		return goerr.Wrap(err, "failed to wait for indexes of the interrupted sync", goerr.V("journal", path))
+	}
+
+	// Its indexes are READY, so the journal is not merged when this sync is
+	// interrupted too
+	if err := os.Remove(path); err != nil {
+		return goerr.Wrap(err, "failed to remove journal", goerr.V("journal", path))
 	}
+	return nil
+}
+
+// saveInterruptedSync saves the journal of an interrupted sync and reports
+// the indexes still building. The journal of an earlier interrupted sync that
+// was not resumed is merged, so that its indexes are still waited for.
+func saveInterruptedSync(logger *slog.Logger, journal *fireconf.Journal, path string) error {
+	previous, err := fireconf.LoadJournal(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return goerr.Wrap(err, "sync interrupted; refusing to overwrite unreadable journal", goerr.V("journal", path))
+	default:
+		if err := journal.Merge(previous); err != nil {
+			return goerr.Wrap(err, "sync interrupted; refusing to overwrite journal", goerr.V("journal", path))
+		}
+	}
+
+	if err := journal.Save(path); err != nil {
+		return goerr.Wrap(err, "sync interrupted; failed to save journal")
+	}
+
+	building := journal.Building()
+	for _, op := range building {
+		logger.Warn("Index still building",
+			"collection", op.Collection,
+			"index", op.Index,
+			"name", op.Name)
+	}
+
+	return goerr.New("sync interrupted; run sync --resume to wait for the indexes still building",
+		goerr.V("journal", path),
+		goerr.V("building", len(building)))
+}
+
+// loadFieldIndexState loads the field index state at path, or returns an
+// empty state if the file does not exist
+func loadFieldIndexState(path, projectID, databaseID string) (*fireconf.FieldIndexState, error) {
+	state, err := fireconf.LoadFieldIndexState(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fireconf.NewFieldIndexState(projectID, databaseID), nil
+	}
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to load field index state")
+	}
+	if state.Project != projectID || state.Database != databaseID {
+		return nil, goerr.New("field index state was written for another database",
+			goerr.V("path", path),
+			goerr.V("project", state.Project),
+			goerr.V("database", state.Database))
+	}
+	return state, nil
+}
 
+// saveFieldIndexState saves the field index state to path, or removes the
+// file if no field is recorded
+func saveFieldIndexState(state *fireconf.FieldIndexState, path string) error {
+	if state.Len() == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return goerr.Wrap(err, "failed to remove field index state", goerr.V("path", path))
+		}
+		return nil
+	}
+	if err := state.Save(path); err != nil {
+		return goerr.Wrap(err, "failed to save field index state", goerr.V("path", path))
+	}
 	return nil
 }
+
+// summarizeSyncResult returns log attributes counting the changes of a sync
+func summarizeSyncResult(result *fireconf.SyncResult) []any {
+	var created, deleted, repaired, unchanged, ttl int
+	for _, col := range result.Collections {
+		created += len(col.Created)
+		deleted += len(col.Deleted)
+		repaired += len(col.Repaired)
+		unchanged += len(col.Unchanged)
+		if col.TTLAction != "" {
+			ttl++
+		}
+	}
+	return []any{
+		"created", created,
+		"deleted", deleted,
+		"repaired", repaired,
+		"unchanged", unchanged,
+		"ttlChanges", ttl,
+		"duration", result.Duration.Round(time.Second),
+	}
+}
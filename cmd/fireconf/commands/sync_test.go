@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/gt"
+)
+
+// fakeSyncClient records the calls of the sync command
+type fakeSyncClient struct {
+	resumed  []*fireconf.Journal
+	migrated int
+}
+
+func (c *fakeSyncClient) Resume(ctx context.Context, journal *fireconf.Journal) error {
+	c.resumed = append(c.resumed, journal)
+	return nil
+}
+
+func (c *fakeSyncClient) MigrateWithResult(ctx context.Context) (*fireconf.SyncResult, error) {
+	c.migrated++
+	return &fireconf.SyncResult{}, nil
+}
+
+func TestExecuteSync(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	// writeInterruptedJournal saves the journal of an interrupted sync that
+	// left an index building
+	writeInterruptedJournal := func(t *testing.T) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "journal.json")
+		journal := fireconf.NewJournal("my-project", "(default)")
+		journal.OnEvent(fireconf.IndexCreateSubmitted{Collection: "users", Name: "projects/my-project/databases/(default)/collectionGroups/users/indexes/abc"})
+		gt.NoError(t, journal.Save(path))
+		return path
+	}
+
+	t.Run("Normal: plain sync keeps the journal of an interrupted sync", func(t *testing.T) {
+		path := writeInterruptedJournal(t)
+		client := &fakeSyncClient{}
+
+		ctx := context.Background()
+		_, err := executeSync(ctx, ctx, logger, client, fireconf.NewJournal("my-project", "(default)"), path, false)
+		gt.NoError(t, err)
+		gt.Equal(t, client.migrated, 1)
+		gt.Equal(t, len(client.resumed), 0)
+
+		journal, err := fireconf.LoadJournal(path)
+		gt.NoError(t, err)
+		gt.Equal(t, len(journal.Building()), 1)
+	})
+
+	t.Run("Normal: resumed sync removes the journal", func(t *testing.T) {
+		path := writeInterruptedJournal(t)
+		client := &fakeSyncClient{}
+
+		ctx := context.Background()
+		_, err := executeSync(ctx, ctx, logger, client, fireconf.NewJournal("my-project", "(default)"), path, true)
+		gt.NoError(t, err)
+		gt.Equal(t, client.migrated, 1)
+		gt.Equal(t, len(client.resumed), 1)
+		gt.Equal(t, len(client.resumed[0].Building()), 1)
+
+		_, err = os.Stat(path)
+		gt.True(t, os.IsNotExist(err))
+	})
+}
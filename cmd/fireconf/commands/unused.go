@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewUnusedCommand creates the unused command
+func NewUnusedCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "unused",
+		Usage: "Report composite indexes that no query in the query logs used",
+		Description: "Reads JSON lines of query explain output or query shapes and maps each query " +
+			"to the index that served it, using the YAML configuration or, with --live, the indexes " +
+			"currently deployed to Firestore.",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     "logs",
+				Aliases:  []string{"l"},
+				Usage:    "Query log file in JSON lines format (- for stdin)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Configuration file path",
+				Value:   "fireconf.yaml",
+			},
+			&cli.BoolFlag{
+				Name:  "live",
+				Usage: "Match against the indexes deployed to Firestore instead of the configuration file",
+			},
+			&cli.StringSliceFlag{
+				Name:    "collections",
+				Aliases: []string{"col"},
+				Usage:   "Collections to read with --live (reads all if not specified)",
+			},
+		},
+		Action: runUnused,
+	}
+}
+
+func runUnused(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+
+	config, err := loadUnusedTarget(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	var readers []io.Reader
+	for _, path := range c.StringSlice("logs") {
+		if path == "-" {
+			readers = append(readers, os.Stdin)
+		} else {
+			f, err := os.Open(path) // #nosec G304 - path is provided by user as CLI argument
+			if err != nil {
+				return goerr.Wrap(err, "failed to open query log", goerr.V("path", path))
+			}
+			defer func() { _ = f.Close() }()
+			readers = append(readers, f)
+		}
+		// Keep the last line of a file from running into the next file
+		readers = append(readers, strings.NewReader("\n"))
+	}
+
+	report, err := config.AnalyzeQueryLog(io.MultiReader(readers...))
+	if err != nil {
+		return goerr.Wrap(err, "failed to analyze query logs")
+	}
+
+	for _, u := range report.Indexes {
+		if u.Count > 0 {
+			fmt.Printf("✓ %s: %s used by %d queries\n", u.Collection, u.Index, u.Count)
+		} else {
+			fmt.Printf("✗ %s: %s never used\n", u.Collection, u.Index)
+		}
+	}
+
+	logger.Info("Analyzed query logs",
+		"indexes", len(report.Indexes),
+		"unused", len(report.Unused()),
+		"singleFieldQueries", report.SingleField,
+		"unmatchedQueries", report.Unmatched,
+		"skippedLines", report.Skipped)
+	if report.Unmatched > 0 {
+		logger.Warn("Some queries were served by indexes that are not in the configuration", "count", report.Unmatched)
+	}
+
+	return nil
+}
+
+// loadUnusedTarget loads the configuration to match the query logs against
+func loadUnusedTarget(ctx context.Context, c *cli.Command) (*fireconf.Config, error) {
+	logger := getLogger(ctx)
+
+	if !c.Bool("live") {
+		config, err := fireconf.LoadConfigFromYAML(c.String("config"))
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to load configuration")
+		}
+		return config, nil
+	}
+
+	// Check required project flag
+	projectID := c.String("project")
+	if projectID == "" {
+		return nil, goerr.New("project flag is required for unused command with --live")
+	}
+
+	// Get database ID
+	databaseID := c.String("database")
+	if databaseID == "" {
+		return nil, goerr.New("database flag is required for unused command with --live")
+	}
+
+	// Create fireconf client
+	opts := []fireconf.Option{
+		fireconf.WithLogger(logger),
+	}
+
+	if credentials := c.String("credentials"); credentials != "" {
+		opts = append(opts, fireconf.WithCredentialsFile(credentials))
+	}
+	opts = append(opts, apiLimitOptions(c)...)
+
+	client, err := fireconf.New(ctx, projectID, databaseID, nil, opts...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create client")
+	}
+	defer func() { _ = client.Close() }()
+
+	logger.Info("Reading deployed indexes", "project", projectID, "database", databaseID)
+	config, err := client.Import(ctx, c.StringSlice("collections")...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read deployed indexes")
+	}
+	return config, nil
+}
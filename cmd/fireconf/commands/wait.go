@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// NewWaitCommand creates the wait command
+func NewWaitCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "wait",
+		Usage: "Wait for configured indexes to be READY and TTL policies to be ACTIVE",
+		Description: "Polls every index and TTL policy in the configuration file, e.g. after " +
+			"sync --no-wait in an earlier pipeline stage. Fails if one of them is missing or " +
+			"enters an ERROR or NEEDS_REPAIR state.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Configuration file path",
+				Value:   "fireconf.yaml",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait (e.g. 2h, waits indefinitely if not specified)",
+			},
+		},
+		Action: runWait,
+	}
+}
+
+func runWait(ctx context.Context, c *cli.Command) error {
+	logger := getLogger(ctx)
+
+	// Check required project flag
+	projectID := c.String("project")
+	if projectID == "" {
+		return goerr.New("project flag is required for wait command")
+	}
+
+	// Get database ID
+	databaseID := c.String("database")
+	if databaseID == "" {
+		return goerr.New("database flag is required for wait command")
+	}
+
+	configPath := c.String("config")
+	logger.Info("Reading configuration file", "path", configPath)
+
+	config, err := fireconf.LoadConfigFromYAML(configPath)
+	if err != nil {
+		return goerr.Wrap(err, "failed to load configuration")
+	}
+
+	// Create fireconf client
+	// The timeout is a deadline of the wait strategy rather than of ctx, so
+	// that it is reported as a timeout distinct from an interruption
+	opts := []fireconf.Option{
+		fireconf.WithLogger(logger),
+		fireconf.WithWaitStrategy(fireconf.WaitStrategy{Deadline: c.Duration("timeout")}),
+	}
+
+	if credentials := c.String("credentials"); credentials != "" {
+		opts = append(opts, fireconf.WithCredentialsFile(credentials))
+	}
+	opts = append(opts, apiLimitOptions(c)...)
+
+	client, err := fireconf.New(ctx, projectID, databaseID, config, opts...)
+	if err != nil {
+		return goerr.Wrap(err, "failed to create client")
+	}
+	defer func() { _ = client.Close() }()
+
+	start := time.Now()
+	if err := client.Wait(ctx); err != nil {
+		return goerr.Wrap(err, "failed to wait for indexes and TTL policies", goerr.V("elapsed", time.Since(start).Round(time.Second)))
+	}
+	logger.Info("Indexes and TTL policies are ready", "elapsed", time.Since(start).Round(time.Second))
+
+	return nil
+}
@@ -46,6 +46,17 @@ func run(args []string) error {
 				Usage:   "Service account key file path",
 				Sources: cli.EnvVars("GOOGLE_APPLICATION_CREDENTIALS"),
 			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of Firestore API calls in flight",
+				Sources: cli.EnvVars("FIRECONF_CONCURRENCY"),
+				Value:   10,
+			},
+			&cli.FloatFlag{
+				Name:    "rate-limit",
+				Usage:   "Maximum number of Firestore API calls per second (0 for no limit)",
+				Sources: cli.EnvVars("FIRECONF_RATE_LIMIT"),
+			},
 			&cli.BoolFlag{
 				Name:    "verbose",
 				Aliases: []string{"v"},
@@ -78,8 +89,18 @@ func run(args []string) error {
 		},
 		Commands: []*cli.Command{
 			commands.NewSyncCommand(),
+			commands.NewWaitCommand(),
+			commands.NewStatusCommand(),
+			commands.NewOperationsCommand(),
 			commands.NewImportCommand(),
 			commands.NewValidateCommand(),
+			commands.NewDeriveCommand(),
+			commands.NewExplainQueryCommand(),
+			commands.NewFromErrorCommand(),
+			commands.NewScanCommand(),
+			commands.NewUnusedCommand(),
+			commands.NewGenCommand(),
+			commands.NewCodegenCommand(),
 		},
 	}
 
@@ -0,0 +1,24 @@
+package fireconf
+
+import (
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// GenerateGo returns the source of a Go package named pkg that declares
+// constants for every collection name and field path of the configuration,
+// so that typos in field paths fail to compile. For each composite index it
+// also declares a helper applying the Where, OrderBy and FindNearest clauses
+// the index serves, e.g. for orders (customerId ASC, createdAt DESC):
+//
+//	func OrdersByCustomerIDCreatedAtDesc(q firestore.Query, customerID any) firestore.Query
+//
+// Indexes consisting of ordered fields only get an additional ...Order
+// helper applying the ordering of all fields.
+func (c *Config) GenerateGo(pkg string) ([]byte, error) {
+	src, err := usecase.GenerateGo(convertToInternalConfig(c), pkg)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to generate Go code")
+	}
+	return src, nil
+}
@@ -0,0 +1,62 @@
+package fireconf_test
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/gt"
+)
+
+func TestConfig_GenerateGo(t *testing.T) {
+	config := &fireconf.Config{
+		Collections: []fireconf.Collection{
+			{
+				Name: "orders",
+				Indexes: []fireconf.Index{
+					idx("customer_id:ASCENDING", "createdAt:DESCENDING"),
+					idx("status:ASCENDING", "stats.total:ASCENDING"),
+				},
+				TTL: &fireconf.TTL{Field: "expireAt"},
+			},
+			{
+				Name: "documents",
+				Indexes: []fireconf.Index{
+					vecIdx(fireconf.QueryScopeCollection, "embedding", 768, "category"),
+				},
+			},
+		},
+	}
+
+	src := string(gt.R1(config.GenerateGo("schema")).NoError(t))
+	_, err := parser.ParseFile(token.NewFileSet(), "schema.go", src, parser.AllErrors)
+	gt.NoError(t, err)
+
+	for _, want := range []string{
+		`CollectionOrders    = "orders"`,
+		`OrdersCustomerID = "customer_id"`,
+		`OrdersStatsTotal = "stats.total"`,
+		`OrdersExpireAt   = "expireAt"`,
+		`func OrdersByCustomerIDCreatedAtDesc(q firestore.Query, customerID any) firestore.Query {`,
+		`Where(OrdersCustomerID, "==", customerID).`,
+		`OrderBy(OrdersCreatedAt, firestore.Desc)`,
+		`func OrdersByStatusStatsTotalOrder(q firestore.Query) firestore.Query {`,
+		`func DocumentsByCategoryEmbeddingVector(q firestore.Query, category any, vector any, limit int, measure firestore.DistanceMeasure) firestore.VectorQuery {`,
+		`FindNearest(DocumentsEmbedding, vector, limit, measure, nil)`,
+	} {
+		gt.True(t, strings.Contains(src, want))
+	}
+
+	t.Run("Field paths differing only in case style", func(t *testing.T) {
+		config := singleCollection("orders", idx("created_at", "status"), idx("createdAt", "status"))
+		_, err := config.GenerateGo("schema")
+		gt.Error(t, err)
+	})
+
+	t.Run("Invalid package name", func(t *testing.T) {
+		_, err := config.GenerateGo("my-schema")
+		gt.Error(t, err)
+	})
+}
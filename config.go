@@ -42,6 +42,11 @@ type VectorConfig struct {
 // TTL represents TTL configuration
 type TTL struct {
 	Field string `yaml:"field"`
+	// IndexExemption exempts the TTL field from single-field indexing to
+	// avoid hotspots on monotonically increasing timestamps. Defaults to
+	// true; set to false to keep ordering and filtering on the field.
+	// Indexing is restored when the TTL policy is removed or moved.
+	IndexExemption *bool `yaml:"indexExemption,omitempty"`
 }
 
 // Order represents field ordering
@@ -162,7 +167,8 @@ func convertFromInternalConfig(internal *model.Config) *Config {
 
 		if col.TTL != nil {
 			collection.TTL = &TTL{
-				Field: col.TTL.Field,
+				Field:          col.TTL.Field,
+				IndexExemption: col.TTL.IndexExemption,
 			}
 		}
 
@@ -185,47 +191,53 @@ func convertToInternalConfig(config *Config) *model.Config {
 		}
 
 		for j, idx := range col.Indexes {
-			index := model.Index{
-				Fields: make([]model.IndexField, len(idx.Fields)),
-			}
+			collection.Indexes[j] = convertIndexToInternal(idx)
+		}
 
-			if idx.QueryScope != "" {
-				index.QueryScope = string(idx.QueryScope)
+		if col.TTL != nil {
+			collection.TTL = &model.TTL{
+				Field:          col.TTL.Field,
+				IndexExemption: col.TTL.IndexExemption,
 			}
+		}
 
-			for k, field := range idx.Fields {
-				indexField := model.IndexField{
-					Name: field.Path,
-				}
+		internal.Collections[i] = collection
+	}
 
-				if field.Order != "" {
-					indexField.Order = string(field.Order)
-				}
+	return internal
+}
 
-				if field.Array != "" {
-					indexField.ArrayConfig = string(field.Array)
-				}
+// convertIndexToInternal converts a public index to the internal model
+func convertIndexToInternal(idx Index) model.Index {
+	index := model.Index{
+		Fields: make([]model.IndexField, len(idx.Fields)),
+	}
 
-				if field.Vector != nil {
-					indexField.VectorConfig = &model.VectorConfig{
-						Dimension: field.Vector.Dimension,
-					}
-				}
+	if idx.QueryScope != "" {
+		index.QueryScope = string(idx.QueryScope)
+	}
 
-				index.Fields[k] = indexField
-			}
+	for k, field := range idx.Fields {
+		indexField := model.IndexField{
+			Name: field.Path,
+		}
 
-			collection.Indexes[j] = index
+		if field.Order != "" {
+			indexField.Order = string(field.Order)
 		}
 
-		if col.TTL != nil {
-			collection.TTL = &model.TTL{
-				Field: col.TTL.Field,
+		if field.Array != "" {
+			indexField.ArrayConfig = string(field.Array)
+		}
+
+		if field.Vector != nil {
+			indexField.VectorConfig = &model.VectorConfig{
+				Dimension: field.Vector.Dimension,
 			}
 		}
 
-		internal.Collections[i] = collection
+		index.Fields[k] = indexField
 	}
 
-	return internal
+	return index
 }
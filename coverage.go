@@ -0,0 +1,101 @@
+package fireconf
+
+import (
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// QueryCoverage describes how a query is served by a configuration
+type QueryCoverage struct {
+	Query Query
+	// Served is true if Firestore can execute the query with the configuration
+	Served bool
+	// SingleField is true if the query needs no composite index because it is
+	// served by Firestore's automatic single-field indexes
+	SingleField bool
+	// Index is the configured composite index serving the query, if any
+	Index *Index
+	// Required is the composite index the query needs, if any
+	Required *Index
+	// Reason explains why no configured index serves the query
+	Reason string
+}
+
+// CoverageReport is the result of checking queries against a configuration
+type CoverageReport struct {
+	Queries []QueryCoverage
+}
+
+// Uncovered returns the queries that are not served by the configuration
+func (r *CoverageReport) Uncovered() []QueryCoverage {
+	var result []QueryCoverage
+	for _, q := range r.Queries {
+		if !q.Served {
+			result = append(result, q)
+		}
+	}
+	return result
+}
+
+// ExplainQuery checks a single query against the configuration and reports
+// which index serves it or why none does.
+func (c *Config) ExplainQuery(q Query) (*QueryCoverage, error) {
+	return explainQuery(convertToInternalConfig(c), q)
+}
+
+// CheckQueries checks every query against the configuration. It is intended
+// to be used in unit tests so that a query requiring a missing index fails in
+// CI instead of failing with FAILED_PRECONDITION in production:
+//
+//	report, err := config.CheckQueries(queries)
+//	gt.NoError(t, err)
+//	for _, q := range report.Uncovered() {
+//	    t.Errorf("query %s is not served: %s", q.Query.Name, q.Reason)
+//	}
+func (c *Config) CheckQueries(queries []Query) (*CoverageReport, error) {
+	internal := convertToInternalConfig(c)
+
+	report := &CoverageReport{
+		Queries: make([]QueryCoverage, 0, len(queries)),
+	}
+	for _, q := range queries {
+		coverage, err := explainQuery(internal, q)
+		if err != nil {
+			return nil, err
+		}
+		report.Queries = append(report.Queries, *coverage)
+	}
+
+	return report, nil
+}
+
+func explainQuery(config *model.Config, q Query) (*QueryCoverage, error) {
+	explanation, err := usecase.ExplainQuery(config, convertQueriesToInternal([]Query{q})[0])
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to explain query")
+	}
+
+	coverage := &QueryCoverage{
+		Query:       q,
+		Served:      explanation.Served,
+		SingleField: explanation.SingleField,
+		Reason:      explanation.Reason,
+	}
+	if explanation.Index != nil {
+		index := convertIndexesToPublic([]model.Index{*explanation.Index})[0]
+		coverage.Index = &index
+	}
+	if explanation.Required != nil {
+		index := convertIndexesToPublic([]model.Index{*explanation.Required})[0]
+		coverage.Required = &index
+	}
+
+	return coverage, nil
+}
+
+// String returns a compact representation of the index such as
+// "(status ASC, createdAt DESC)"
+func (i Index) String() string {
+	return usecase.FormatIndex(convertIndexToInternal(i))
+}
@@ -103,12 +103,21 @@
 //
 //   - [MigrationError]: returned by Migrate on sync failure
 //
+//   - [MigrationErrors]: returned by Migrate with [WithContinueOnError], one
+//     [MigrationError] per failed collection
+//
 //   - [DiffError]: returned by DiffConfigs on invalid input
 //
 //   - [ValidationError]: returned by Config.Validate on configuration errors
 //
 //     var migErr *fireconf.MigrationError
 //     if errors.As(err, &migErr) {
-//     fmt.Printf("Migration operation %q failed: %v\n", migErr.Operation, migErr.Cause)
+//     fmt.Printf("Collection %q failed during %s: %v\n", migErr.Collection, migErr.Operation, migErr.Cause)
 //     }
+//
+// Failures of Firestore wrap [ErrPermissionDenied], [ErrQuotaExceeded],
+// [ErrAlreadyExists], [ErrIndexAlreadyExists], [ErrDatabaseNotFound] or
+// [ErrIndexBuildFailed], which can be tested with errors.Is. Waiting beyond
+// the timeouts of [WithWaitStrategy] returns an error wrapping
+// [ErrWaitTimeout].
 package fireconf
@@ -1,6 +1,41 @@
 package fireconf
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+)
+
+// Errors classifying failures of Firestore. Errors returned by Client methods
+// wrap them and can be tested with errors.Is; the gRPC status of an Admin API
+// error also remains available to status.FromError.
+var (
+	// ErrPermissionDenied is a PERMISSION_DENIED or UNAUTHENTICATED error:
+	// the caller lacks an IAM permission or valid credentials
+	ErrPermissionDenied = model.ErrPermissionDenied
+	// ErrQuotaExceeded is a RESOURCE_EXHAUSTED error: a quota or rate limit
+	// of the Admin API is exhausted, and the call may succeed later
+	ErrQuotaExceeded = model.ErrQuotaExceeded
+	// ErrAlreadyExists is an ALREADY_EXISTS error of any resource, e.g. an
+	// index or a collection
+	ErrAlreadyExists = model.ErrAlreadyExists
+	// ErrIndexAlreadyExists is an ALREADY_EXISTS error of an index creation.
+	// Such errors also wrap ErrAlreadyExists.
+	ErrIndexAlreadyExists = model.ErrIndexAlreadyExists
+	// ErrDatabaseNotFound is a NOT_FOUND error for a missing database. It is
+	// detected from the resource type of the error details, or from the
+	// message if Firestore attaches none.
+	ErrDatabaseNotFound = model.ErrDatabaseNotFound
+	// ErrIndexBuildFailed is returned when an index enters the ERROR or
+	// NEEDS_REPAIR state while waiting for it
+	ErrIndexBuildFailed = model.ErrIndexBuildFailed
+	// ErrWaitTimeout is returned when an index, index deletion or TTL policy
+	// change is not done within the timeout or deadline of the WaitStrategy.
+	// It is distinct from context errors, and Firestore keeps applying the
+	// change in the background.
+	ErrWaitTimeout = model.ErrWaitTimeout
+)
 
 // MigrationError represents an error that occurred during migration
 type MigrationError struct {
@@ -17,6 +52,30 @@ func (e *MigrationError) Unwrap() error {
 	return e.Cause
 }
 
+// MigrationErrors is returned by Migrate with WithContinueOnError when one or
+// more collections failed. Errors holds a *MigrationError per failed
+// collection in configuration order.
+type MigrationErrors struct {
+	Errors []*MigrationError
+}
+
+func (e *MigrationErrors) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("migration failed for %d collection(s):", len(e.Errors)))
+	for _, err := range e.Errors {
+		lines = append(lines, fmt.Sprintf("  %s (%s): %v", err.Collection, err.Operation, err.Cause))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *MigrationErrors) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
 // ValidationError represents a configuration validation error
 type ValidationError struct {
 	Field   string
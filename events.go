@@ -0,0 +1,180 @@
+package fireconf
+
+import (
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+)
+
+// Event is a progress event emitted by Migrate. Use a type switch to handle
+// the concrete event types of this package.
+type Event interface {
+	event()
+}
+
+// Observer receives progress events from Migrate. Calls are serialized even
+// though collections are processed concurrently, and they block the
+// migration, so OnEvent should return quickly.
+type Observer interface {
+	OnEvent(Event)
+}
+
+// ObserverFunc adapts a function to the Observer interface
+type ObserverFunc func(Event)
+
+// OnEvent calls f(event)
+func (f ObserverFunc) OnEvent(event Event) {
+	f(event)
+}
+
+// CollectionStarted is emitted when processing of a collection begins
+type CollectionStarted struct {
+	Collection string
+}
+
+// CollectionFinished is emitted when processing of a collection ends. Err is
+// nil on success.
+type CollectionFinished struct {
+	Collection string
+	Err        error
+}
+
+// IndexCreateSubmitted is emitted when Firestore accepted an index creation.
+// Name is the resource name of the index.
+type IndexCreateSubmitted struct {
+	Collection string
+	Index      Index
+	Name       string
+}
+
+// IndexDeleteSubmitted is emitted when Firestore accepted an index deletion
+type IndexDeleteSubmitted struct {
+	Collection string
+	Index      Index
+	Name       string
+}
+
+// IndexStateChanged is emitted while waiting for an index when its state
+// changes. Previous is empty for the first observed state.
+type IndexStateChanged struct {
+	Collection string
+	Name       string
+	Previous   string
+	// State is CREATING, READY, NEEDS_REPAIR or ERROR
+	State string
+}
+
+// WaitKind tells what a WaitingTick is waiting for
+type WaitKind string
+
+const (
+	// WaitIndexReady waits for an index to become READY
+	WaitIndexReady WaitKind = WaitKind(model.WaitIndexReady)
+	// WaitIndexDeletion waits for an index to disappear
+	WaitIndexDeletion WaitKind = WaitKind(model.WaitIndexDeletion)
+	// WaitTTLActive waits for a TTL policy to become ACTIVE
+	WaitTTLActive WaitKind = WaitKind(model.WaitTTLActive)
+	// WaitTTLCleared waits for a TTL policy to be removed
+	WaitTTLCleared WaitKind = WaitKind(model.WaitTTLCleared)
+)
+
+// WaitingTick is emitted periodically while waiting for a long-running
+// operation. Target is the index name or the TTL field.
+type WaitingTick struct {
+	Collection string
+	Kind       WaitKind
+	Target     string
+	Elapsed    time.Duration
+	// Progress is the build progress of an index, nil if unknown
+	Progress *OperationProgress
+}
+
+// OperationProgress is the progress of a long-running operation such as an
+// index build. Estimated values are 0 if unknown.
+type OperationProgress struct {
+	// State is INITIALIZING, PROCESSING, CANCELLING, FINALIZING, SUCCESSFUL,
+	// FAILED or CANCELLED
+	State              string
+	DocumentsCompleted int64
+	DocumentsEstimated int64
+	BytesCompleted     int64
+	BytesEstimated     int64
+}
+
+// String formats the progress, e.g. "43% (1.2M/2.8M docs)"
+func (p OperationProgress) String() string {
+	return usecase.FormatProgress(model.OperationProgress(p))
+}
+
+// TTLChanged is emitted when Firestore accepted a TTL policy change. Action
+// is "enable" or "disable".
+type TTLChanged struct {
+	Collection string
+	Field      string
+	Action     string
+}
+
+// ErrorOccurred is emitted when processing of a collection fails, before the
+// CollectionFinished event of the collection
+type ErrorOccurred struct {
+	Collection string
+	Err        error
+}
+
+func (CollectionStarted) event()    {}
+func (CollectionFinished) event()   {}
+func (IndexCreateSubmitted) event() {}
+func (IndexDeleteSubmitted) event() {}
+func (IndexStateChanged) event()    {}
+func (WaitingTick) event()          {}
+func (TTLChanged) event()           {}
+func (ErrorOccurred) event()        {}
+
+// convertEventToPublic converts an internal event to the public type, nil for
+// an unknown event
+func convertEventToPublic(event model.Event) Event {
+	switch e := event.(type) {
+	case model.CollectionStarted:
+		return CollectionStarted{Collection: e.Collection}
+	case model.CollectionFinished:
+		return CollectionFinished{Collection: e.Collection, Err: e.Err}
+	case model.IndexCreateSubmitted:
+		return IndexCreateSubmitted{
+			Collection: e.Collection,
+			Index:      convertIndexesToPublic([]model.Index{e.Index})[0],
+			Name:       e.Name,
+		}
+	case model.IndexDeleteSubmitted:
+		return IndexDeleteSubmitted{
+			Collection: e.Collection,
+			Index:      convertIndexesToPublic([]model.Index{e.Index})[0],
+			Name:       e.Name,
+		}
+	case model.IndexStateChanged:
+		return IndexStateChanged{
+			Collection: e.Collection,
+			Name:       e.Name,
+			Previous:   e.Previous,
+			State:      e.State,
+		}
+	case model.WaitingTick:
+		tick := WaitingTick{
+			Collection: e.Collection,
+			Kind:       WaitKind(e.Kind),
+			Target:     e.Target,
+			Elapsed:    e.Elapsed,
+		}
+		if e.Progress != nil {
+			progress := OperationProgress(*e.Progress)
+			tick.Progress = &progress
+		}
+		return tick
+	case model.TTLChanged:
+		return TTLChanged{Collection: e.Collection, Field: e.Field, Action: e.Action}
+	case model.ErrorOccurred:
+		return ErrorOccurred{Collection: e.Collection, Err: e.Err}
+	default:
+		return nil
+	}
+}
@@ -1,5 +1,7 @@
 package fireconf
 
+import "github.com/m-mizutani/fireconf/internal/interfaces"
+
 // NewDiffTestClient constructs a Client suitable for testing DiffConfigs
 // without establishing a Firestore connection. DiffConfigs is a pure
 // computation over the provided desired/current configs, so the Firestore
@@ -7,3 +9,9 @@ package fireconf
 func NewDiffTestClient(desired *Config) *Client {
 	return &Client{config: desired}
 }
+
+// NewFieldIndexStore returns the store of a field index state as used by
+// Migrate
+func NewFieldIndexStore(state *FieldIndexState) interfaces.FieldIndexStore {
+	return fieldIndexStore{state: state}
+}
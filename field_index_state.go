@@ -0,0 +1,173 @@
+package fireconf
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// FieldIndexState records the single-field index configurations that fields
+// had before Migrate exempted them from indexing for a TTL policy. When the
+// policy is removed or moved, even by a later migration, Migrate restores
+// exactly the recorded configuration instead of the database defaults, so
+// that indexes configured by hand survive a TTL enable/disable cycle.
+// Load it before Migrate, pass it with WithFieldIndexState and save it
+// afterwards.
+type FieldIndexState struct {
+	Project  string          `json:"project"`
+	Database string          `json:"database"`
+	Fields   []ExemptedField `json:"fields"`
+
+	mu sync.Mutex
+}
+
+// ExemptedField is a field exempted from single-field indexing by Migrate
+// with its configuration from before the exemption
+type ExemptedField struct {
+	Collection string `json:"collection"`
+	Field      string `json:"field"`
+	// UsesDefaults is true if the field used the database defaults
+	UsesDefaults bool `json:"usesDefaults,omitempty"`
+	// Indexes are the single-field indexes of the field otherwise
+	Indexes []FieldIndex `json:"indexes,omitempty"`
+}
+
+// FieldIndex is a single-field index
+type FieldIndex struct {
+	QueryScope  QueryScope  `json:"queryScope,omitempty"`
+	Order       Order       `json:"order,omitempty"`
+	ArrayConfig ArrayConfig `json:"arrayConfig,omitempty"`
+}
+
+// NewFieldIndexState creates an empty state for a database
+func NewFieldIndexState(projectID, databaseID string) *FieldIndexState {
+	return &FieldIndexState{Project: projectID, Database: databaseID}
+}
+
+// LoadFieldIndexState loads a state saved by FieldIndexState.Save
+func LoadFieldIndexState(path string) (*FieldIndexState, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is provided by user as CLI argument
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read field index state file")
+	}
+
+	var state FieldIndexState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, goerr.Wrap(err, "failed to parse field index state", goerr.V("path", path))
+	}
+
+	return &state, nil
+}
+
+// Save writes the state to a JSON file
+func (s *FieldIndexState) Save(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal field index state")
+	}
+
+	// #nosec G306 - the state holds no secrets
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return goerr.Wrap(err, "failed to write field index state file")
+	}
+
+	return nil
+}
+
+// Len returns the number of recorded fields
+func (s *FieldIndexState) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.Fields)
+}
+
+// fieldIndexStore adapts a FieldIndexState to interfaces.FieldIndexStore
+type fieldIndexStore struct {
+	state *FieldIndexState
+}
+
+// find returns the position of the record of a field, or -1
+func (s fieldIndexStore) find(collectionID, fieldName string) int {
+	for i, f := range s.state.Fields {
+		if f.Collection == collectionID && f.Field == fieldName {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s fieldIndexStore) PreviousFieldIndexConfig(collectionID, fieldName string) *interfaces.FirestoreFieldIndexConfig {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	i := s.find(collectionID, fieldName)
+	if i < 0 {
+		return nil
+	}
+	field := s.state.Fields[i]
+	config := &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: field.UsesDefaults}
+	for _, idx := range field.Indexes {
+		config.Indexes = append(config.Indexes, interfaces.FirestoreFieldIndex{
+			QueryScope:  string(idx.QueryScope),
+			Order:       string(idx.Order),
+			ArrayConfig: string(idx.ArrayConfig),
+		})
+	}
+	return config
+}
+
+func (s fieldIndexStore) RecordFieldIndexConfig(collectionID, fieldName string, config *interfaces.FirestoreFieldIndexConfig) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	field := ExemptedField{
+		Collection:   collectionID,
+		Field:        fieldName,
+		UsesDefaults: config == nil || config.UsesAncestorConfig,
+	}
+	if config != nil {
+		for _, idx := range config.Indexes {
+			field.Indexes = append(field.Indexes, FieldIndex{
+				QueryScope:  QueryScope(idx.QueryScope),
+				Order:       Order(idx.Order),
+				ArrayConfig: ArrayConfig(idx.ArrayConfig),
+			})
+		}
+	}
+
+	if i := s.find(collectionID, fieldName); i >= 0 {
+		s.state.Fields[i] = field
+		return
+	}
+	s.state.Fields = append(s.state.Fields, field)
+}
+
+func (s fieldIndexStore) ForgetFieldIndexConfig(collectionID, fieldName string) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	if i := s.find(collectionID, fieldName); i >= 0 {
+		s.state.Fields = append(s.state.Fields[:i], s.state.Fields[i+1:]...)
+	}
+}
+
+func (s fieldIndexStore) RecordedFields(collectionID string) []string {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	var fields []string
+	for _, f := range s.state.Fields {
+		if f.Collection == collectionID {
+			fields = append(fields, f.Field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
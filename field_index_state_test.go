@@ -0,0 +1,55 @@
+package fireconf_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/gt"
+)
+
+func TestFieldIndexState(t *testing.T) {
+	custom := &interfaces.FirestoreFieldIndexConfig{
+		Indexes: []interfaces.FirestoreFieldIndex{
+			{QueryScope: "COLLECTION", Order: "DESCENDING"},
+			{QueryScope: "COLLECTION_GROUP", ArrayConfig: "CONTAINS"},
+		},
+	}
+
+	t.Run("Normal: recorded configuration survives save and load", func(t *testing.T) {
+		state := fireconf.NewFieldIndexState("test-project", "(default)")
+		store := fireconf.NewFieldIndexStore(state)
+		store.RecordFieldIndexConfig("users", "expireAt", custom)
+		store.RecordFieldIndexConfig("posts", "deletedAt", &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true})
+
+		path := filepath.Join(t.TempDir(), "state.json")
+		gt.NoError(t, state.Save(path))
+
+		loaded, err := fireconf.LoadFieldIndexState(path)
+		gt.NoError(t, err)
+		gt.Equal(t, loaded.Project, "test-project")
+		gt.Equal(t, loaded.Len(), 2)
+
+		store = fireconf.NewFieldIndexStore(loaded)
+		gt.Equal(t, store.PreviousFieldIndexConfig("users", "expireAt"), custom)
+		gt.Equal(t, store.PreviousFieldIndexConfig("posts", "deletedAt"), &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true})
+		gt.Equal(t, store.RecordedFields("users"), []string{"expireAt"})
+	})
+
+	t.Run("Normal: forgotten field is not recorded", func(t *testing.T) {
+		state := fireconf.NewFieldIndexState("test-project", "(default)")
+		store := fireconf.NewFieldIndexStore(state)
+		store.RecordFieldIndexConfig("users", "expireAt", custom)
+		store.ForgetFieldIndexConfig("users", "expireAt")
+
+		gt.Nil(t, store.PreviousFieldIndexConfig("users", "expireAt"))
+		gt.Equal(t, len(store.RecordedFields("users")), 0)
+		gt.Equal(t, state.Len(), 0)
+	})
+
+	t.Run("Error: missing file", func(t *testing.T) {
+		_, err := fireconf.LoadFieldIndexState(filepath.Join(t.TempDir(), "missing.json"))
+		gt.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package fireconf
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/m-mizutani/fireconf/internal/adapter/firestore"
@@ -31,12 +32,34 @@ func New(ctx context.Context, projectID, databaseID string, config *Config, opts
 	if databaseID == "" {
 		return nil, goerr.New("database ID is required")
 	}
+	if options.Concurrency < 1 {
+		return nil, goerr.New("concurrency must be at least 1", goerr.V("concurrency", options.Concurrency))
+	}
+	if options.RateLimit < 0 {
+		return nil, goerr.New("rate limit must not be negative", goerr.V("rateLimit", options.RateLimit))
+	}
+	if err := options.WaitStrategy.validate(); err != nil {
+		return nil, err
+	}
+	if state := options.FieldIndexState; state != nil && (state.Project != projectID || state.Database != databaseID) {
+		return nil, goerr.New("field index state was written for another database",
+			goerr.V("project", state.Project),
+			goerr.V("database", state.Database))
+	}
 
 	// Create Firestore client
 	authConfig := firestore.AuthConfig{
 		ProjectID:   projectID,
 		DatabaseID:  databaseID,
 		Credentials: options.CredentialsFile,
+		Retry: firestore.RetryPolicy{
+			MaxAttempts:    options.RetryPolicy.MaxAttempts,
+			InitialBackoff: options.RetryPolicy.InitialBackoff,
+			MaxBackoff:     options.RetryPolicy.MaxBackoff,
+			Multiplier:     options.RetryPolicy.Multiplier,
+		},
+		Concurrency: options.Concurrency,
+		RateLimit:   options.RateLimit,
 	}
 
 	firestoreClient, err := firestore.NewClient(ctx, authConfig)
@@ -61,15 +84,27 @@ func (c *Client) Close() error {
 	return nil
 }
 
-// Migrate applies the configuration to Firestore
+// Migrate applies the configuration to Firestore. A failure is returned as
+// *MigrationError naming the failed collection, or as *MigrationErrors with
+// WithContinueOnError.
 func (c *Client) Migrate(ctx context.Context) error {
+	_, err := c.MigrateWithResult(ctx)
+	return err
+}
+
+// MigrateWithResult applies the configuration to Firestore like Migrate and
+// returns what was done for each collection: the indexes created, deleted,
+// repaired and left unchanged with their resource names and states, the TTL
+// change and durations. On a migration failure the result is returned along
+// with the error and covers the collections processed until then.
+func (c *Client) MigrateWithResult(ctx context.Context) (*SyncResult, error) {
 	if c.config == nil {
-		return goerr.New("config is required for Migrate; pass it to New()")
+		return nil, goerr.New("config is required for Migrate; pass it to New()")
 	}
 
 	// Validate configuration
 	if err := c.config.Validate(); err != nil {
-		return goerr.Wrap(err, "invalid configuration")
+		return nil, goerr.Wrap(err, "invalid configuration")
 	}
 
 	// Convert to internal model
@@ -80,11 +115,63 @@ func (c *Client) Migrate(ctx context.Context) error {
 	if c.options.DryRun {
 		syncOpts = append(syncOpts, usecase.SyncWithDryRun())
 	}
+	if c.options.CreateBeforeDelete {
+		syncOpts = append(syncOpts, usecase.SyncWithCreateBeforeDelete())
+	}
+	if c.options.RepairAttempts > 0 {
+		syncOpts = append(syncOpts, usecase.SyncWithRepair(c.options.RepairAttempts))
+	}
+	if c.options.NoWait {
+		syncOpts = append(syncOpts, usecase.SyncWithAsync())
+	}
+	if c.options.WaitTTL {
+		syncOpts = append(syncOpts, usecase.SyncWithWaitTTL())
+	}
+	if c.options.ContinueOnError {
+		syncOpts = append(syncOpts, usecase.SyncWithContinueOnError())
+	}
+	if c.options.Clock != nil {
+		syncOpts = append(syncOpts, usecase.SyncWithClock(c.options.Clock))
+	}
+	syncOpts = append(syncOpts, usecase.SyncWithWaitStrategy(c.options.WaitStrategy.toInternal()))
+	if state := c.options.FieldIndexState; state != nil {
+		syncOpts = append(syncOpts, usecase.SyncWithFieldIndexStore(fieldIndexStore{state: state}))
+	}
+	if observer := c.options.Observer; observer != nil {
+		syncOpts = append(syncOpts, usecase.SyncWithObserver(func(event model.Event) {
+			if e := convertEventToPublic(event); e != nil {
+				observer.OnEvent(e)
+			}
+		}))
+	}
 	sync := usecase.NewSync(c.client, c.logger, syncOpts...)
 
 	// Execute sync
-	if err := sync.Execute(ctx, internalConfig); err != nil {
-		return &MigrationError{Operation: "migrate", Cause: err}
+	result, err := sync.ExecuteWithResult(ctx, internalConfig)
+	if err != nil {
+		return convertSyncResultToPublic(result), convertSyncErrorToPublic(err)
+	}
+
+	return convertSyncResultToPublic(result), nil
+}
+
+// Wait polls Firestore until every index of the configuration is READY and
+// every TTL policy is ACTIVE, e.g. after Migrate with WithNoWait. It returns
+// an error when an index or TTL policy is missing or fails to build, and
+// when ctx is done. The IndexTimeout and Deadline of WithWaitStrategy bound
+// the whole wait and return an error wrapping ErrWaitTimeout.
+func (c *Client) Wait(ctx context.Context) error {
+	if c.config == nil {
+		return goerr.New("config is required for Wait; pass it to New()")
+	}
+
+	if err := c.config.Validate(); err != nil {
+		return goerr.Wrap(err, "invalid configuration")
+	}
+
+	wait := usecase.NewWait(c.client, c.logger, c.waitOptions()...)
+	if err := wait.Execute(ctx, convertToInternalConfig(c.config)); err != nil {
+		return goerr.Wrap(err, "wait failed")
 	}
 
 	return nil
@@ -172,10 +259,18 @@ func (c *Client) DiffConfigs(current *Config) (*DiffResult, error) {
 		if len(toDelete) > 0 {
 			diff.IndexesToDelete = firestoreIndexesToPublic(toDelete)
 		}
+		replacements, _, _ := usecase.PairReplacements(toCreate, toDelete)
+		for _, r := range replacements {
+			diff.IndexesToModify = append(diff.IndexesToModify, IndexModification{
+				From: firestoreIndexesToPublic([]interfaces.FirestoreIndex{r.Old})[0],
+				To:   firestoreIndexesToPublic([]interfaces.FirestoreIndex{r.New})[0],
+			})
+		}
 
 		// Compare TTL
 		if (desiredCol.TTL == nil) != (currentCol.TTL == nil) ||
-			(desiredCol.TTL != nil && currentCol.TTL != nil && desiredCol.TTL.Field != currentCol.TTL.Field) {
+			(desiredCol.TTL != nil && currentCol.TTL != nil &&
+				(desiredCol.TTL.Field != currentCol.TTL.Field || desiredCol.TTL.ExemptsIndex() != currentCol.TTL.ExemptsIndex())) {
 			diff.TTL = convertTTLToPublic(desiredCol.TTL)
 			diff.TTLAction = ActionModify
 			if desiredCol.TTL == nil {
@@ -257,10 +352,20 @@ type CollectionDiff struct {
 	Indexes         []Index
 	IndexesToAdd    []Index
 	IndexesToDelete []Index
+	// IndexesToModify pairs indexes of IndexesToDelete with the index of
+	// IndexesToAdd replacing them, i.e. covering the same fields with a
+	// different order, direction or scope
+	IndexesToModify []IndexModification
 	TTL             *TTL
 	TTLAction       DiffAction
 }
 
+// IndexModification is an index replaced by another index on the same fields
+type IndexModification struct {
+	From Index
+	To   Index
+}
+
 // DiffAction represents the type of change
 type DiffAction string
 
@@ -305,6 +410,41 @@ func convertTTLToPublic(ttl *model.TTL) *TTL {
 		return nil
 	}
 	return &TTL{
-		Field: ttl.Field,
+		Field:          ttl.Field,
+		IndexExemption: ttl.IndexExemption,
+	}
+}
+
+// convertSyncErrorToPublic converts an error of a sync to *MigrationErrors
+// for a sync continuing on errors, or else to *MigrationError
+func convertSyncErrorToPublic(err error) error {
+	var syncErrs *usecase.SyncErrors
+	if errors.As(err, &syncErrs) {
+		result := &MigrationErrors{}
+		for _, e := range syncErrs.Errors {
+			result.Errors = append(result.Errors, &MigrationError{
+				Collection: e.Collection,
+				Operation:  e.Operation,
+				Cause:      e.Err,
+			})
+		}
+		return result
+	}
+
+	var collErr *usecase.CollectionError
+	if errors.As(err, &collErr) {
+		return &MigrationError{Collection: collErr.Collection, Operation: collErr.Operation, Cause: collErr.Err}
+	}
+	return &MigrationError{Operation: "migrate", Cause: err}
+}
+
+// waitOptions returns the options of the Wait use case
+func (c *Client) waitOptions() []usecase.WaitOption {
+	opts := []usecase.WaitOption{
+		usecase.WaitWithStrategy(c.options.WaitStrategy.toInternal()),
+	}
+	if c.options.Clock != nil {
+		opts = append(opts, usecase.WaitWithClock(c.options.Clock))
 	}
+	return opts
 }
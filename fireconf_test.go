@@ -180,6 +180,7 @@ func TestDiffConfigs_IndexIdentity(t *testing.T) {
 		gt.Equal(t, len(diff.IndexesToAdd), 0)
 		gt.Equal(t, len(diff.IndexesToDelete), 1)
 		gt.Equal(t, diff.IndexesToDelete[0].Fields[0].Path, "email")
+		gt.Equal(t, len(diff.IndexesToModify), 0)
 	})
 
 	t.Run("A4: field order difference yields add and delete", func(t *testing.T) {
@@ -196,6 +197,9 @@ func TestDiffConfigs_IndexIdentity(t *testing.T) {
 		gt.NotNil(t, diff)
 		gt.Equal(t, len(diff.IndexesToAdd), 1)
 		gt.Equal(t, len(diff.IndexesToDelete), 1)
+		gt.Equal(t, len(diff.IndexesToModify), 1)
+		gt.Equal(t, diff.IndexesToModify[0].From.Fields[0].Path, "B")
+		gt.Equal(t, diff.IndexesToModify[0].To.Fields[0].Path, "A")
 	})
 
 	t.Run("A5: same path different order yields add and delete", func(t *testing.T) {
@@ -0,0 +1,273 @@
+package fireconf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/m-mizutani/fireconf/internal/adapter/firestore"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// MissingIndex is an index definition extracted from a Firestore
+// "The query requires an index" error
+type MissingIndex struct {
+	Collection string
+	Index      Index
+}
+
+// ParseIndexErrors extracts the index definitions embedded in Firestore
+// FAILED_PRECONDITION error messages. text may contain any number of error
+// messages, log lines or bare console URLs with a create_composite parameter.
+func ParseIndexErrors(text string) ([]MissingIndex, error) {
+	decoded, err := firestore.DecodeIndexURLs(text)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to decode index definition")
+	}
+
+	result := make([]MissingIndex, 0, len(decoded))
+	for _, d := range decoded {
+		index := usecase.NormalizeFirestoreIndex(d.Index)
+		result = append(result, MissingIndex{
+			Collection: d.Collection,
+			Index:      convertIndexesToPublic([]model.Index{index})[0],
+		})
+	}
+	return result, nil
+}
+
+// AddIndex appends the index to the named collection, creating the collection
+// if it is not configured yet. The index is not added if the collection
+// already has an identical index under the same semantics as Migrate
+// (__name__ ignored, vector indexes normalized to COLLECTION scope, field
+// order significant). Returns true if the index was added.
+func (c *Config) AddIndex(collection string, index Index) bool {
+	internal := convertIndexToInternal(index)
+
+	for i := range c.Collections {
+		if c.Collections[i].Name != collection {
+			continue
+		}
+		for _, existing := range c.Collections[i].Indexes {
+			if usecase.SameIndex(convertIndexToInternal(existing), internal) {
+				return false
+			}
+		}
+		c.Collections[i].Indexes = append(c.Collections[i].Indexes, index)
+		return true
+	}
+
+	c.Collections = append(c.Collections, Collection{
+		Name:    collection,
+		Indexes: []Index{index},
+	})
+	return true
+}
+
+// AppendIndexesToYAML adds the indexes that are not configured yet to the
+// YAML configuration file at path, creating the file if it does not exist.
+// Unlike SaveToYAML, only the new index entries are inserted, so comments,
+// key order and formatting of the file are kept. Returns the indexes added.
+func AppendIndexesToYAML(path string, indexes []MissingIndex) ([]MissingIndex, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is provided by user as CLI argument
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, goerr.Wrap(err, "failed to read config file")
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, goerr.Wrap(err, "failed to parse YAML", goerr.V("path", path))
+	}
+
+	// Collections that are configured already, in the order of the file
+	configured := make(map[string]int, len(config.Collections))
+	for i, col := range config.Collections {
+		configured[col.Name] = i
+	}
+
+	var added []MissingIndex
+	newIndexes := make(map[int][]Index)
+	var newCollections []Collection
+	created := make(map[string]int)
+	for _, m := range indexes {
+		if !config.AddIndex(m.Collection, m.Index) {
+			continue
+		}
+		added = append(added, m)
+		if i, ok := configured[m.Collection]; ok {
+			newIndexes[i] = append(newIndexes[i], m.Index)
+		} else if i, ok := created[m.Collection]; ok {
+			newCollections[i].Indexes = append(newCollections[i].Indexes, m.Index)
+		} else {
+			created[m.Collection] = len(newCollections)
+			newCollections = append(newCollections, Collection{Name: m.Collection, Indexes: []Index{m.Index}})
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	output, err := insertIntoYAML(data, newIndexes, newCollections)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to edit configuration", goerr.V("path", path))
+	}
+
+	// #nosec G306 - YAML config files should be readable by others
+	if err := os.WriteFile(path, output, 0644); err != nil {
+		return nil, goerr.Wrap(err, "failed to write config file")
+	}
+
+	return added, nil
+}
+
+// insertIntoYAML inserts indexes into the collections at the given positions
+// and appends new collections to the YAML document, leaving the rest of the
+// document as it is
+func insertIntoYAML(data []byte, newIndexes map[int][]Index, newCollections []Collection) ([]byte, error) {
+	file, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to parse YAML")
+	}
+
+	for i, indexes := range newIndexes {
+		path, err := yaml.PathString(fmt.Sprintf("$.collections[%d]", i))
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to build YAML path")
+		}
+		node, err := path.FilterFile(file)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to find collection", goerr.V("position", i))
+		}
+		collection, ok := node.(*ast.MappingNode)
+		if !ok {
+			return nil, goerr.New("collection is not a mapping", goerr.V("position", i))
+		}
+
+		// Appending requires a block sequence; an empty flow sequence or null
+		// is replaced
+		key := "indexes"
+		if value := mappingValue(collection, "indexes"); isBlockSequence(value) {
+			path, err = yaml.PathString(fmt.Sprintf("$.collections[%d].indexes", i))
+			if err != nil {
+				return nil, goerr.Wrap(err, "failed to build YAML path")
+			}
+			key = ""
+		} else {
+			removeMappingKey(collection, "indexes")
+		}
+
+		if err := mergeIntoYAML(file, path, key, indexes); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(newCollections) == 0 {
+		return []byte(file.String()), nil
+	}
+
+	var root *ast.MappingNode
+	if len(file.Docs) > 0 {
+		root, _ = file.Docs[0].Body.(*ast.MappingNode)
+	}
+	if root != nil && isBlockSequence(mappingValue(root, "collections")) {
+		path, err := yaml.PathString("$.collections")
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to build YAML path")
+		}
+		if err := mergeIntoYAML(file, path, "", newCollections); err != nil {
+			return nil, err
+		}
+		return []byte(file.String()), nil
+	}
+
+	// Without collections to append to, the collections key is replaced or
+	// added to the end of the document
+	collections := map[string][]Collection{"collections": newCollections}
+	if root != nil {
+		node, err := yaml.ValueToNode(collections, yaml.IndentSequence(true))
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to convert to YAML node")
+		}
+		generated, ok := node.(*ast.MappingNode)
+		if !ok {
+			return nil, goerr.New("collections are not converted to a mapping")
+		}
+		for i, v := range root.Values {
+			if v.Key.GetToken().Value == "collections" {
+				if err := generated.Values[0].SetComment(v.GetComment()); err != nil {
+					return nil, goerr.Wrap(err, "failed to keep comment")
+				}
+				root.Values[i] = generated.Values[0]
+				return []byte(file.String()), nil
+			}
+		}
+		root.Values = append(root.Values, generated.Values[0])
+		return []byte(file.String()), nil
+	}
+	if len(file.Docs) > 0 && file.Docs[0].Body != nil {
+		if _, ok := file.Docs[0].Body.(*ast.CommentGroupNode); !ok {
+			return nil, goerr.New("configuration is not a mapping")
+		}
+	}
+	appended, err := yaml.MarshalWithOptions(collections, yaml.IndentSequence(true))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to marshal collections")
+	}
+	output := file.String()
+	if strings.TrimSpace(output) == "" {
+		output = ""
+	} else if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return append([]byte(output), appended...), nil
+}
+
+// mergeIntoYAML appends value to the node at path, as the value of key if
+// key is not empty
+func mergeIntoYAML(file *ast.File, path *yaml.Path, key string, value any) error {
+	if key != "" {
+		value = map[string]any{key: value}
+	}
+	node, err := yaml.ValueToNode(value, yaml.IndentSequence(true))
+	if err != nil {
+		return goerr.Wrap(err, "failed to convert to YAML node")
+	}
+	if err := path.MergeFromNode(file, node); err != nil {
+		return goerr.Wrap(err, "failed to insert YAML node", goerr.V("path", path.String()))
+	}
+	return nil
+}
+
+// mappingValue returns the value of key in the mapping, or nil
+func mappingValue(mapping *ast.MappingNode, key string) ast.Node {
+	for _, v := range mapping.Values {
+		if v.Key.GetToken().Value == key {
+			return v.Value
+		}
+	}
+	return nil
+}
+
+// removeMappingKey removes key and its value from the mapping
+func removeMappingKey(mapping *ast.MappingNode, key string) {
+	kept := mapping.Values[:0]
+	for _, v := range mapping.Values {
+		if v.Key.GetToken().Value != key {
+			kept = append(kept, v)
+		}
+	}
+	mapping.Values = kept
+}
+
+// isBlockSequence reports whether node is a sequence in block style that
+// entries can be appended to
+func isBlockSequence(node ast.Node) bool {
+	seq, ok := node.(*ast.SequenceNode)
+	return ok && !seq.IsFlowStyle && len(seq.Values) > 0
+}
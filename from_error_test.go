@@ -0,0 +1,146 @@
+package fireconf_test
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	adminpb "cloud.google.com/go/firestore/apiv1/admin/adminpb"
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/gt"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseIndexErrors(t *testing.T) {
+	data, err := proto.Marshal(&adminpb.Index{
+		Name:       "projects/my-project/databases/(default)/collectionGroups/orders/indexes/_",
+		QueryScope: adminpb.Index_COLLECTION,
+		Fields: []*adminpb.Index_IndexField{
+			{FieldPath: "status", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_ASCENDING}},
+			{FieldPath: "createdAt", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_DESCENDING}},
+			{FieldPath: "__name__", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_DESCENDING}},
+		},
+	})
+	gt.NoError(t, err)
+
+	message := "rpc error: code = FailedPrecondition desc = The query requires an index. You can create it here: " +
+		"https://console.firebase.google.com/v1/r/project/my-project/firestore/indexes?create_composite=" +
+		base64.StdEncoding.EncodeToString(data)
+
+	missing := gt.R1(fireconf.ParseIndexErrors(message)).NoError(t)
+	gt.Equal(t, len(missing), 1)
+	gt.Equal(t, missing[0].Collection, "orders")
+	gt.Equal(t, missing[0].Index.String(), "(status ASC, createdAt DESC)")
+
+	t.Run("AddIndex deduplicates", func(t *testing.T) {
+		config := singleCollection("orders", idx("status:ASCENDING", "createdAt:DESCENDING"))
+		gt.False(t, config.AddIndex(missing[0].Collection, missing[0].Index))
+		gt.Equal(t, len(config.Collections[0].Indexes), 1)
+	})
+
+	t.Run("AddIndex appends to existing collection", func(t *testing.T) {
+		config := singleCollection("orders", idx("status:ASCENDING"))
+		gt.True(t, config.AddIndex(missing[0].Collection, missing[0].Index))
+		gt.Equal(t, len(config.Collections[0].Indexes), 2)
+		gt.NoError(t, config.Validate())
+	})
+
+	t.Run("AddIndex creates collection", func(t *testing.T) {
+		config := singleCollection("users", idx("status:ASCENDING"))
+		gt.True(t, config.AddIndex(missing[0].Collection, missing[0].Index))
+		gt.Equal(t, len(config.Collections), 2)
+		gt.Equal(t, config.Collections[1].Name, "orders")
+	})
+}
+
+func TestAppendIndexesToYAML(t *testing.T) {
+	const original = `# Indexes of the app
+collections:
+  # users of the app
+  - name: users
+    indexes:
+      - fields:
+          - path: email   # login
+            order: ASCENDING
+    ttl:
+      field: expireAt
+
+  - name: orders # placed orders
+    indexes: []
+`
+	missing := []fireconf.MissingIndex{
+		{Collection: "users", Index: idx("status:ASCENDING", "createdAt:DESCENDING")},
+		{Collection: "users", Index: idx("email:ASCENDING")},
+		{Collection: "orders", Index: idx("status:ASCENDING")},
+		{Collection: "posts", Index: idx("authorId:ASCENDING")},
+		{Collection: "tasks", Index: idx("dueAt:ASCENDING")},
+		{Collection: "posts", Index: idx("authorId:ASCENDING", "score:DESCENDING")},
+	}
+
+	t.Run("Normal: inserts only new indexes and keeps comments", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fireconf.yaml")
+		gt.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+		added := gt.R1(fireconf.AppendIndexesToYAML(path, missing)).NoError(t)
+		gt.Equal(t, len(added), 5)
+
+		data := gt.R1(os.ReadFile(path)).NoError(t)
+		text := string(data)
+		gt.True(t, strings.HasPrefix(text, "# Indexes of the app\ncollections:\n  # users of the app\n  - name: users\n"))
+		gt.True(t, strings.Contains(text, "# login"))
+		gt.True(t, strings.Contains(text, "- name: orders # placed orders"))
+		gt.True(t, strings.Contains(text, "    ttl:\n      field: expireAt\n"))
+
+		config := gt.R1(fireconf.LoadConfigFromYAML(path)).NoError(t)
+		gt.NoError(t, config.Validate())
+		gt.Equal(t, len(config.Collections), 4)
+		gt.Equal(t, config.Collections[0].Name, "users")
+		gt.Equal(t, len(config.Collections[0].Indexes), 2)
+		gt.Equal(t, config.Collections[0].Indexes[1].String(), "(status ASC, createdAt DESC)")
+		gt.Equal(t, config.Collections[0].TTL.Field, "expireAt")
+		gt.Equal(t, len(config.Collections[1].Indexes), 1)
+		gt.Equal(t, config.Collections[2].Name, "posts")
+		gt.Equal(t, len(config.Collections[2].Indexes), 2)
+		gt.Equal(t, config.Collections[3].Name, "tasks")
+
+		// Nothing is added a second time
+		added = gt.R1(fireconf.AppendIndexesToYAML(path, missing)).NoError(t)
+		gt.Equal(t, len(added), 0)
+		gt.Equal(t, string(gt.R1(os.ReadFile(path)).NoError(t)), text)
+	})
+
+	t.Run("Normal: adds collections to a file without any", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fireconf.yaml")
+		gt.NoError(t, os.WriteFile(path, []byte("# managed by fireconf\ncollections: []\n"), 0600))
+
+		added := gt.R1(fireconf.AppendIndexesToYAML(path, missing[:1])).NoError(t)
+		gt.Equal(t, len(added), 1)
+
+		data := gt.R1(os.ReadFile(path)).NoError(t)
+		gt.True(t, strings.HasPrefix(string(data), "# managed by fireconf\n"))
+		config := gt.R1(fireconf.LoadConfigFromYAML(path)).NoError(t)
+		gt.Equal(t, len(config.Collections), 1)
+		gt.Equal(t, config.Collections[0].Indexes[0].String(), "(status ASC, createdAt DESC)")
+	})
+
+	t.Run("Normal: creates missing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fireconf.yaml")
+
+		added := gt.R1(fireconf.AppendIndexesToYAML(path, missing[:1])).NoError(t)
+		gt.Equal(t, len(added), 1)
+
+		config := gt.R1(fireconf.LoadConfigFromYAML(path)).NoError(t)
+		gt.Equal(t, len(config.Collections), 1)
+		gt.Equal(t, config.Collections[0].Name, "users")
+	})
+
+	t.Run("Error: broken YAML", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fireconf.yaml")
+		gt.NoError(t, os.WriteFile(path, []byte("collections: [\n"), 0600))
+
+		_, err := fireconf.AppendIndexesToYAML(path, missing)
+		gt.Error(t, err)
+	})
+}
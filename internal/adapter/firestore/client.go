@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"cloud.google.com/go/firestore"
 	apiv1 "cloud.google.com/go/firestore/apiv1/admin"
 	adminpb "cloud.google.com/go/firestore/apiv1/admin/adminpb"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc"
 )
 
 // Client is the Firestore Admin API client wrapper
@@ -18,6 +20,12 @@ type Client struct {
 	client     *firestore.Client
 	projectID  string
 	databaseID string
+	retry      RetryPolicy
+
+	// indexOps maps index names to the name of the operation building them,
+	// or to "" if no operation was found
+	indexOps   map[string]string
+	indexOpsMu sync.Mutex
 }
 
 // AuthConfig represents authentication configuration
@@ -25,6 +33,15 @@ type AuthConfig struct {
 	ProjectID   string
 	DatabaseID  string
 	Credentials string // Service account key file path (optional)
+	// Retry is the retry policy of Admin API calls; zero fields take the
+	// values of DefaultRetryPolicy
+	Retry RetryPolicy
+	// Concurrency is the maximum number of API calls in flight, including
+	// those of collection listing and creation, DefaultConcurrency if 0
+	Concurrency int
+	// RateLimit is the maximum number of API calls started per second,
+	// unlimited if 0
+	RateLimit float64
 }
 
 // NewClient creates a new Firestore Admin API client
@@ -35,10 +52,21 @@ func NewClient(ctx context.Context, config AuthConfig) (*Client, error) {
 		opts = append(opts, option.WithCredentialsFile(config.Credentials))
 	}
 
+	// All Admin API and Firestore API calls share one pool bounding their
+	// concurrency and rate
+	concurrency := config.Concurrency
+	if concurrency == 0 {
+		concurrency = DefaultConcurrency
+	}
+	limiter := newCallLimiter(concurrency, config.RateLimit)
+	opts = append([]option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(limiter.unaryInterceptor)),
+	}, opts...)
+
 	// Create Admin API client
 	adminClient, err := apiv1.NewFirestoreAdminClient(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Firestore Admin client: %w", err)
+		return nil, wrapError(err, "failed to create Firestore Admin client")
 	}
 
 	// Create regular Firestore client for collection listing (only for default database)
@@ -62,6 +90,7 @@ func NewClient(ctx context.Context, config AuthConfig) (*Client, error) {
 		client:     firestoreClient,
 		projectID:  config.ProjectID,
 		databaseID: config.DatabaseID,
+		retry:      config.Retry.withDefaults(),
 	}, nil
 }
 
@@ -84,14 +113,14 @@ func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
 		Parent: fmt.Sprintf("projects/%s/databases/%s/collectionGroups/-", c.projectID, c.databaseID),
 	}
 
-	it := c.admin.ListIndexes(ctx, req)
+	it := c.admin.ListIndexes(ctx, req, c.readCallOptions()...)
 	for {
 		index, err := it.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list indexes: %w", err)
+			return nil, wrapError(err, "failed to list indexes")
 		}
 
 		// Extract collection name from index name
@@ -119,7 +148,7 @@ func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
 				break
 			}
 			if err != nil {
-				return nil, fmt.Errorf("failed to list collections: %w", err)
+				return nil, wrapError(err, "failed to list collections")
 			}
 			collectionMap[col.ID] = true
 		}
@@ -146,6 +175,11 @@ func extractCollectionFromIndexName(indexName string) string {
 	return ""
 }
 
+// getDatabasePath returns the resource name of the database
+func (c *Client) getDatabasePath() string {
+	return fmt.Sprintf("projects/%s/databases/%s", c.projectID, c.databaseID)
+}
+
 // getParent returns the parent path for collection groups
 func (c *Client) getParent(collectionID string) string {
 	return fmt.Sprintf("projects/%s/databases/%s/collectionGroups/%s",
@@ -164,13 +198,13 @@ func (c *Client) WaitForOperation(ctx context.Context, operation interface{}) er
 	switch op := operation.(type) {
 	case *apiv1.CreateIndexOperation:
 		if _, err := op.Wait(ctx); err != nil {
-			return fmt.Errorf("index creation operation failed: %w", err)
+			return wrapOperationError(err, operationCreateIndex, "index creation operation failed")
 		}
 		return nil
 
 	case *apiv1.UpdateFieldOperation:
 		if _, err := op.Wait(ctx); err != nil {
-			return fmt.Errorf("field update operation failed: %w", err)
+			return wrapError(err, "field update operation failed")
 		}
 		return nil
 
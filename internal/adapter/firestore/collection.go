@@ -11,7 +11,7 @@ func (c *Client) CollectionExists(ctx context.Context, collectionID string) (boo
 	if c.client != nil {
 		collections, err := c.ListCollections(ctx)
 		if err != nil {
-			return false, fmt.Errorf("failed to list collections: %w", err)
+			return false, wrapError(err, "failed to list collections")
 		}
 
 		for _, existingCollection := range collections {
@@ -46,7 +46,7 @@ func (c *Client) CreateCollection(ctx context.Context, collectionID string) erro
 		"__created_by": "fireconf",
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create collection %s: %w", collectionID, err)
+		return wrapError(err, fmt.Sprintf("failed to create collection %s", collectionID))
 	}
 
 	// Immediately delete the temporary document
@@ -0,0 +1,90 @@
+package firestore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// operationKind identifies the kind of call that failed, since the meaning
+// of some status codes depends on the resource the call acts on
+type operationKind int
+
+const (
+	// operationGeneric is any call without a specific classification
+	operationGeneric operationKind = iota
+	// operationCreateIndex is the creation of a composite index, including
+	// waiting for its operation
+	operationCreateIndex
+)
+
+// wrapError wraps an error of the Admin API with msg. The model errors
+// matching its gRPC status code, if any, are added so that callers can
+// classify the error with errors.Is; the status remains available to
+// status.FromError.
+func wrapError(err error, msg string) error {
+	return wrapOperationError(err, operationGeneric, msg)
+}
+
+// wrapOperationError wraps err like wrapError, classifying it for the kind of
+// call that failed
+func wrapOperationError(err error, op operationKind, msg string) error {
+	kinds := classifyError(err, op)
+	if len(kinds) == 0 {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	format := msg
+	args := make([]any, 0, len(kinds)+1)
+	for _, kind := range kinds {
+		format += ": %w"
+		args = append(args, kind)
+	}
+	return fmt.Errorf(format+": %w", append(args, err)...)
+}
+
+// classifyError returns the model errors matching the gRPC status code of
+// err for the kind of call that failed
+func classifyError(err error, op operationKind) []error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+
+	switch s.Code() {
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return []error{model.ErrPermissionDenied}
+	case codes.ResourceExhausted:
+		return []error{model.ErrQuotaExceeded}
+	case codes.AlreadyExists:
+		if op == operationCreateIndex {
+			return []error{model.ErrIndexAlreadyExists, model.ErrAlreadyExists}
+		}
+		return []error{model.ErrAlreadyExists}
+	case codes.NotFound:
+		if isDatabaseNotFound(s) {
+			return []error{model.ErrDatabaseNotFound}
+		}
+	}
+	return nil
+}
+
+// isDatabaseNotFound returns true if the NOT_FOUND status refers to the
+// database rather than a resource within it, based on the resource type of
+// the error details. Without such details the message (e.g. "The database
+// (default) does not exist for project my-project") is matched instead, which
+// breaks if Firestore rewords it.
+func isDatabaseNotFound(s *status.Status) bool {
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.ResourceInfo); ok {
+			return strings.Contains(strings.ToLower(info.GetResourceType()), "database")
+		}
+	}
+
+	msg := strings.ToLower(s.Message())
+	return strings.Contains(msg, "database") && strings.Contains(msg, "does not exist")
+}
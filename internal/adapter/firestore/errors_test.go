@@ -0,0 +1,79 @@
+package firestore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/firestore"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/gt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWrapError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"permission denied", status.Error(codes.PermissionDenied, "missing datastore.indexes.create"), model.ErrPermissionDenied},
+		{"unauthenticated", status.Error(codes.Unauthenticated, "invalid credentials"), model.ErrPermissionDenied},
+		{"quota exceeded", status.Error(codes.ResourceExhausted, "quota exceeded"), model.ErrQuotaExceeded},
+		{"already exists", status.Error(codes.AlreadyExists, "collection already exists"), model.ErrAlreadyExists},
+		{"database not found", status.Error(codes.NotFound, "The database (default) does not exist for project my-project"), model.ErrDatabaseNotFound},
+		{"database not found by details", notFoundWithResource(t, "firestore.googleapis.com/Database", "resource not found"), model.ErrDatabaseNotFound},
+		{"index not found by details", notFoundWithResource(t, "firestore.googleapis.com/Index", "the database does not exist"), nil},
+		{"index not found", status.Error(codes.NotFound, "index not found"), nil},
+		{"unavailable", status.Error(codes.Unavailable, "try again"), nil},
+		{"not a status", context.Canceled, nil},
+	}
+
+	sentinels := []error{
+		model.ErrPermissionDenied,
+		model.ErrQuotaExceeded,
+		model.ErrAlreadyExists,
+		model.ErrIndexAlreadyExists,
+		model.ErrDatabaseNotFound,
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := firestore.WrapError(tc.err, "failed to create index")
+			gt.Error(t, err).Contains("failed to create index")
+			gt.True(t, errors.Is(err, tc.err))
+			for _, sentinel := range sentinels {
+				gt.Equal(t, errors.Is(err, sentinel), sentinel == tc.want)
+			}
+			if s, ok := status.FromError(tc.err); ok {
+				gt.Equal(t, status.Code(err), s.Code())
+			}
+		})
+	}
+}
+
+func TestWrapIndexCreationError(t *testing.T) {
+	t.Run("Normal: already exists is classified as an existing index", func(t *testing.T) {
+		err := firestore.WrapIndexCreationError(status.Error(codes.AlreadyExists, "index already exists"), "failed to create index")
+		gt.True(t, errors.Is(err, model.ErrIndexAlreadyExists))
+		gt.True(t, errors.Is(err, model.ErrAlreadyExists))
+		gt.Equal(t, status.Code(err), codes.AlreadyExists)
+	})
+
+	t.Run("Normal: other errors are classified as usual", func(t *testing.T) {
+		err := firestore.WrapIndexCreationError(status.Error(codes.PermissionDenied, "denied"), "failed to create index")
+		gt.True(t, errors.Is(err, model.ErrPermissionDenied))
+		gt.False(t, errors.Is(err, model.ErrIndexAlreadyExists))
+	})
+}
+
+// notFoundWithResource returns a NOT_FOUND status error with resource info
+// details
+func notFoundWithResource(t *testing.T, resourceType, msg string) error {
+	t.Helper()
+	s, err := status.New(codes.NotFound, msg).WithDetails(&errdetails.ResourceInfo{ResourceType: resourceType})
+	gt.NoError(t, err)
+	return s.Err()
+}
@@ -0,0 +1,32 @@
+package firestore
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Export internal functions for testing
+var (
+	WrapError = wrapError
+)
+
+// WrapIndexCreationError wraps err as an error of an index creation
+func WrapIndexCreationError(err error, msg string) error {
+	return wrapOperationError(err, operationCreateIndex, msg)
+}
+
+// NewRetryFunc returns the Retry method of a retryer of the policy
+func NewRetryFunc(policy RetryPolicy) func(err error) (time.Duration, bool) {
+	return newRetryer(policy.withDefaults(), retryableCodes).Retry
+}
+
+// NewReadRetryFunc returns the Retry method of a retryer of idempotent reads
+func NewReadRetryFunc(policy RetryPolicy) func(err error) (time.Duration, bool) {
+	return newRetryer(policy.withDefaults(), readRetryableCodes).Retry
+}
+
+// NewLimitInterceptor returns the interceptor of a call limiter
+func NewLimitInterceptor(concurrency int, ratePerSecond float64) grpc.UnaryClientInterceptor {
+	return newCallLimiter(concurrency, ratePerSecond).unaryInterceptor
+}
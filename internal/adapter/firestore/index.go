@@ -18,7 +18,7 @@ func (c *Client) ListIndexes(ctx context.Context, collectionID string) ([]interf
 	}
 
 	var indexes []interfaces.FirestoreIndex
-	it := c.admin.ListIndexes(ctx, req)
+	it := c.admin.ListIndexes(ctx, req, c.readCallOptions()...)
 
 	for {
 		index, err := it.Next()
@@ -26,7 +26,7 @@ func (c *Client) ListIndexes(ctx context.Context, collectionID string) ([]interf
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list indexes: %w", err)
+			return nil, wrapError(err, "failed to list indexes")
 		}
 
 		// Extract collection ID from index name
@@ -47,7 +47,7 @@ func (c *Client) ListIndexes(ctx context.Context, collectionID string) ([]interf
 }
 
 // CreateIndex creates a new composite index and returns the index resource name.
-// Returns an empty string if the index already exists.
+// The error wraps model.ErrIndexAlreadyExists if the index already exists.
 func (c *Client) CreateIndex(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
 	// Convert domain model to API format
 	apiIndex := convertIndexToAPI(index)
@@ -57,13 +57,9 @@ func (c *Client) CreateIndex(ctx context.Context, collectionID string, index int
 		Index:  apiIndex,
 	}
 
-	op, err := c.admin.CreateIndex(ctx, req)
+	op, err := c.admin.CreateIndex(ctx, req, c.callOptions()...)
 	if err != nil {
-		// Handle already exists error gracefully
-		if s, ok := status.FromError(err); ok && s.Code() == codes.AlreadyExists {
-			return "", nil // Index already exists, no need to wait
-		}
-		return "", fmt.Errorf("failed to create index: %w", err)
+		return "", wrapOperationError(err, operationCreateIndex, "failed to create index")
 	}
 
 	// Extract the index resource name from the operation metadata
@@ -72,6 +68,9 @@ func (c *Client) CreateIndex(ctx context.Context, collectionID string, index int
 		return "", fmt.Errorf("failed to get index operation metadata: %w", err)
 	}
 
+	// Remember the operation to report the build progress of the index
+	c.setIndexOperation(meta.Index, op.Name())
+
 	return meta.Index, nil
 }
 
@@ -81,9 +80,9 @@ func (c *Client) GetIndex(ctx context.Context, indexName string) (*interfaces.Fi
 		Name: indexName,
 	}
 
-	index, err := c.admin.GetIndex(ctx, req)
+	index, err := c.admin.GetIndex(ctx, req, c.readCallOptions()...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get index %s: %w", indexName, err)
+		return nil, wrapError(err, fmt.Sprintf("failed to get index %s", indexName))
 	}
 
 	result := convertIndexFromAPI(index)
@@ -96,13 +95,13 @@ func (c *Client) DeleteIndex(ctx context.Context, indexName string) (interface{}
 		Name: indexName,
 	}
 
-	err := c.admin.DeleteIndex(ctx, req)
+	err := c.admin.DeleteIndex(ctx, req, c.callOptions()...)
 	if err != nil {
 		// Handle not found error gracefully
 		if s, ok := status.FromError(err); ok && s.Code() == codes.NotFound {
 			return nil, nil // Index already deleted, consider it success
 		}
-		return nil, fmt.Errorf("failed to delete index: %w", err)
+		return nil, wrapError(err, "failed to delete index")
 	}
 
 	// DeleteIndex doesn't return an operation object, just nil for success
@@ -0,0 +1,85 @@
+package firestore
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	adminpb "cloud.google.com/go/firestore/apiv1/admin/adminpb"
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"google.golang.org/protobuf/proto"
+)
+
+// EncodedIndex is an index definition decoded from a console URL
+type EncodedIndex struct {
+	Collection string
+	Index      interfaces.FirestoreIndex
+}
+
+// indexURLParam matches the encoded index definition in console URLs that
+// Firestore embeds in FAILED_PRECONDITION "The query requires an index" errors,
+// e.g. https://console.firebase.google.com/v1/r/project/{project}/firestore/indexes?create_composite=...
+var indexURLParam = regexp.MustCompile(`create_composite=([A-Za-z0-9+/=_%-]+)`)
+
+// DecodeIndexURLs extracts every index definition embedded in the text, which
+// may contain error messages, log lines or bare console URLs. Duplicate
+// occurrences of the same encoded definition are returned once.
+func DecodeIndexURLs(text string) ([]EncodedIndex, error) {
+	var result []EncodedIndex
+	seen := make(map[string]bool)
+
+	for _, match := range indexURLParam.FindAllStringSubmatch(text, -1) {
+		encoded := match[1]
+		if seen[encoded] {
+			continue
+		}
+		seen[encoded] = true
+
+		index, err := decodeIndex(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		collectionID := extractCollectionFromIndexName(index.GetName())
+		if collectionID == "" {
+			return nil, fmt.Errorf("encoded index has no collection group: %s", index.GetName())
+		}
+
+		result = append(result, EncodedIndex{
+			Collection: collectionID,
+			Index:      convertIndexFromAPI(index),
+		})
+	}
+
+	return result, nil
+}
+
+// decodeIndex decodes a base64 encoded, serialized google.firestore.admin.v1.Index
+func decodeIndex(encoded string) (*adminpb.Index, error) {
+	// PathUnescape keeps '+' of standard base64, which QueryUnescape would
+	// turn into a space
+	if unescaped, err := url.PathUnescape(encoded); err == nil {
+		encoded = unescaped
+	}
+	encoded = strings.TrimRight(encoded, "=")
+
+	var data []byte
+	var err error
+	for _, enc := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+		if data, err = enc.DecodeString(encoded); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode index definition: %w", err)
+	}
+
+	var index adminpb.Index
+	if err := proto.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal index definition: %w", err)
+	}
+
+	return &index, nil
+}
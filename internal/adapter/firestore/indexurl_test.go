@@ -0,0 +1,118 @@
+package firestore_test
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	adminpb "cloud.google.com/go/firestore/apiv1/admin/adminpb"
+	"github.com/m-mizutani/fireconf/internal/adapter/firestore"
+	"github.com/m-mizutani/gt"
+	"google.golang.org/protobuf/proto"
+)
+
+// encodeIndexURL builds a console URL as embedded by Firestore in
+// FAILED_PRECONDITION errors
+func encodeIndexURL(t *testing.T, index *adminpb.Index) string {
+	t.Helper()
+	data, err := proto.Marshal(index)
+	gt.NoError(t, err)
+	return "https://console.firebase.google.com/v1/r/project/my-project/firestore/indexes?create_composite=" +
+		base64.StdEncoding.EncodeToString(data)
+}
+
+func TestDecodeIndexURLs(t *testing.T) {
+	ordersIndex := &adminpb.Index{
+		Name:       "projects/my-project/databases/(default)/collectionGroups/orders/indexes/_",
+		QueryScope: adminpb.Index_COLLECTION,
+		Fields: []*adminpb.Index_IndexField{
+			{FieldPath: "customerId", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_ASCENDING}},
+			{FieldPath: "createdAt", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_DESCENDING}},
+			{FieldPath: "__name__", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_DESCENDING}},
+		},
+	}
+	postsIndex := &adminpb.Index{
+		Name:       "projects/my-project/databases/(default)/collectionGroups/posts/indexes/_",
+		QueryScope: adminpb.Index_COLLECTION_GROUP,
+		Fields: []*adminpb.Index_IndexField{
+			{FieldPath: "tags", ValueMode: &adminpb.Index_IndexField_ArrayConfig_{ArrayConfig: adminpb.Index_IndexField_CONTAINS}},
+			{FieldPath: "score", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_ASCENDING}},
+		},
+	}
+
+	t.Run("Decode error messages", func(t *testing.T) {
+		ordersURL := encodeIndexURL(t, ordersIndex)
+		text := "rpc error: code = FailedPrecondition desc = The query requires an index. You can create it here: " + ordersURL + "\n" +
+			"2024-01-01T00:00:00Z ERROR query failed: " + encodeIndexURL(t, postsIndex) + "\n" +
+			"duplicated: " + ordersURL
+
+		indexes, err := firestore.DecodeIndexURLs(text)
+		gt.NoError(t, err)
+		gt.Equal(t, len(indexes), 2)
+
+		gt.Equal(t, indexes[0].Collection, "orders")
+		gt.Equal(t, indexes[0].Index.QueryScope, "COLLECTION")
+		gt.Equal(t, len(indexes[0].Index.Fields), 3)
+		gt.Equal(t, indexes[0].Index.Fields[1].FieldPath, "createdAt")
+		gt.Equal(t, indexes[0].Index.Fields[1].Order, "DESCENDING")
+
+		gt.Equal(t, indexes[1].Collection, "posts")
+		gt.Equal(t, indexes[1].Index.QueryScope, "COLLECTION_GROUP")
+		gt.Equal(t, indexes[1].Index.Fields[0].ArrayConfig, "CONTAINS")
+	})
+
+	t.Run("URL encoded parameter", func(t *testing.T) {
+		data, err := proto.Marshal(ordersIndex)
+		gt.NoError(t, err)
+		encoded := base64.URLEncoding.EncodeToString(data)
+		text := "https://console.firebase.google.com/v1/r/project/p/firestore/databases/db/indexes?create_composite=" + encoded
+
+		indexes, err := firestore.DecodeIndexURLs(text)
+		gt.NoError(t, err)
+		gt.Equal(t, len(indexes), 1)
+		gt.Equal(t, indexes[0].Collection, "orders")
+	})
+
+	t.Run("Standard base64 containing plus signs", func(t *testing.T) {
+		regionIndex := &adminpb.Index{
+			Name:       "projects/my-project/databases/(default)/collectionGroups/orders/indexes/_",
+			QueryScope: adminpb.Index_COLLECTION,
+			Fields: []*adminpb.Index_IndexField{
+				{FieldPath: "価格", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_ASCENDING}},
+				{FieldPath: "地域", ValueMode: &adminpb.Index_IndexField_Order_{Order: adminpb.Index_IndexField_DESCENDING}},
+			},
+		}
+		text := encodeIndexURL(t, regionIndex)
+		gt.True(t, strings.Contains(text, "+"))
+
+		indexes, err := firestore.DecodeIndexURLs(text)
+		gt.NoError(t, err)
+		gt.Equal(t, len(indexes), 1)
+		gt.Equal(t, indexes[0].Index.Fields[1].FieldPath, "地域")
+		gt.Equal(t, indexes[0].Index.Fields[1].Order, "DESCENDING")
+	})
+
+	t.Run("Percent encoded standard base64", func(t *testing.T) {
+		data, err := proto.Marshal(ordersIndex)
+		gt.NoError(t, err)
+		encoded := url.QueryEscape(base64.StdEncoding.EncodeToString(data))
+		text := "https://console.firebase.google.com/v1/r/project/p/firestore/indexes?create_composite=" + encoded
+
+		indexes, err := firestore.DecodeIndexURLs(text)
+		gt.NoError(t, err)
+		gt.Equal(t, len(indexes), 1)
+		gt.Equal(t, indexes[0].Collection, "orders")
+	})
+
+	t.Run("No URL", func(t *testing.T) {
+		indexes, err := firestore.DecodeIndexURLs("rpc error: code = Unavailable")
+		gt.NoError(t, err)
+		gt.Equal(t, len(indexes), 0)
+	})
+
+	t.Run("Broken definition", func(t *testing.T) {
+		_, err := firestore.DecodeIndexURLs("indexes?create_composite=Zm9vYmFy")
+		gt.Error(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+package firestore
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+)
+
+// DefaultConcurrency is the number of concurrent API calls unless configured
+const DefaultConcurrency = 10
+
+// callLimiter is the pool shared by all Admin API and Firestore API calls of
+// a client, e.g. index management and collection listing and creation. It
+// bounds the number of calls in flight and the rate at which they start.
+// Each retry of a call is a call of its own.
+type callLimiter struct {
+	slots   chan struct{}
+	limiter *rate.Limiter // nil if unlimited
+}
+
+// newCallLimiter creates a limiter of concurrency calls in flight, at most
+// ratePerSecond calls per second or unlimited if 0
+func newCallLimiter(concurrency int, ratePerSecond float64) *callLimiter {
+	l := &callLimiter{
+		slots: make(chan struct{}, concurrency),
+	}
+	if ratePerSecond > 0 {
+		l.limiter = rate.NewLimiter(rate.Limit(ratePerSecond), 1)
+	}
+	return l
+}
+
+// acquire blocks until a call may start and returns the function releasing
+// its slot
+func (l *callLimiter) acquire(ctx context.Context) (func(), error) {
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-l.slots }
+
+	if l.limiter != nil {
+		if err := l.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// unaryInterceptor applies the limiter to gRPC calls
+func (l *callLimiter) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	release, err := l.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
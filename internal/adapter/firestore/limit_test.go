@@ -0,0 +1,82 @@
+package firestore_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/firestore"
+	"github.com/m-mizutani/gt"
+	"google.golang.org/grpc"
+)
+
+func TestCallLimiter(t *testing.T) {
+	// invoke runs n calls through the interceptor concurrently and returns
+	// the maximum number of calls in flight
+	invoke := func(t *testing.T, interceptor grpc.UnaryClientInterceptor, n int) int32 {
+		var inFlight, maxInFlight atomic.Int32
+		invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			cur := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				prev := maxInFlight.Load()
+				if cur <= prev || maxInFlight.CompareAndSwap(prev, cur) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := interceptor(context.Background(), "/test", nil, nil, nil, invoker); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+		return maxInFlight.Load()
+	}
+
+	t.Run("Normal: calls in flight are bounded", func(t *testing.T) {
+		maxInFlight := invoke(t, firestore.NewLimitInterceptor(3, 0), 20)
+		gt.True(t, maxInFlight <= 3)
+		gt.True(t, maxInFlight > 0)
+	})
+
+	t.Run("Normal: calls are rate limited", func(t *testing.T) {
+		start := time.Now()
+		invoke(t, firestore.NewLimitInterceptor(10, 100), 6)
+		// The first call starts at once and the others 10ms apart
+		gt.True(t, time.Since(start) >= 50*time.Millisecond)
+	})
+
+	t.Run("Error: context is done while waiting for a slot", func(t *testing.T) {
+		interceptor := firestore.NewLimitInterceptor(1, 0)
+		release := make(chan struct{})
+		blocking := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			<-release
+			return nil
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = interceptor(context.Background(), "/test", nil, nil, nil, blocking)
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := interceptor(ctx, "/test", nil, nil, nil, blocking)
+		gt.Error(t, err)
+
+		close(release)
+		<-done
+	})
+}
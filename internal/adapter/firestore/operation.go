@@ -0,0 +1,190 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+
+	adminpb "cloud.google.com/go/firestore/apiv1/admin/adminpb"
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GetIndexOperation returns the latest operation building an index, or nil if
+// there is none. The operation started by CreateIndex is used if known;
+// otherwise the operations of the database are searched once, e.g. for an
+// index created by an earlier run.
+func (c *Client) GetIndexOperation(ctx context.Context, indexName string) (*interfaces.FirestoreOperation, error) {
+	c.indexOpsMu.Lock()
+	opName, known := c.indexOps[indexName]
+	c.indexOpsMu.Unlock()
+
+	if !known {
+		op, err := c.findIndexOperation(ctx, indexName)
+		if err != nil {
+			return nil, err
+		}
+		if op == nil {
+			c.setIndexOperation(indexName, "")
+			return nil, nil
+		}
+		c.setIndexOperation(indexName, op.GetName())
+		return convertOperationFromAPI(op), nil
+	}
+	if opName == "" {
+		return nil, nil
+	}
+
+	op, err := c.admin.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: opName}, c.readCallOptions()...)
+	if err != nil {
+		return nil, wrapError(err, fmt.Sprintf("failed to get operation %s", opName))
+	}
+	return convertOperationFromAPI(op), nil
+}
+
+// setIndexOperation remembers the operation building an index
+func (c *Client) setIndexOperation(indexName, opName string) {
+	c.indexOpsMu.Lock()
+	defer c.indexOpsMu.Unlock()
+	if c.indexOps == nil {
+		c.indexOps = make(map[string]string)
+	}
+	c.indexOps[indexName] = opName
+}
+
+// findIndexOperation searches the operations of the database for the one
+// building an index, preferring an operation in progress over the latest
+// finished one
+func (c *Client) findIndexOperation(ctx context.Context, indexName string) (*longrunningpb.Operation, error) {
+	req := &longrunningpb.ListOperationsRequest{
+		Name: c.getDatabasePath(),
+	}
+
+	var found *longrunningpb.Operation
+	var foundMeta *adminpb.IndexOperationMetadata
+	it := c.admin.ListOperations(ctx, req, c.readCallOptions()...)
+	for {
+		op, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, wrapError(err, "failed to list operations")
+		}
+
+		meta := indexOperationMetadata(op)
+		if meta == nil || meta.GetIndex() != indexName {
+			continue
+		}
+		if !op.GetDone() {
+			return op, nil
+		}
+		if found == nil || meta.GetStartTime().AsTime().After(foundMeta.GetStartTime().AsTime()) {
+			found, foundMeta = op, meta
+		}
+	}
+
+	return found, nil
+}
+
+// indexOperationMetadata returns the metadata of an index operation, or nil
+// for other operations
+func indexOperationMetadata(op *longrunningpb.Operation) *adminpb.IndexOperationMetadata {
+	meta := &adminpb.IndexOperationMetadata{}
+	if op.GetMetadata() == nil || op.GetMetadata().UnmarshalTo(meta) != nil {
+		return nil
+	}
+	return meta
+}
+
+// ListOperations lists the index and field operations of the database,
+// including finished ones. Other operations such as exports are skipped.
+func (c *Client) ListOperations(ctx context.Context) ([]interfaces.FirestoreOperation, error) {
+	req := &longrunningpb.ListOperationsRequest{
+		Name: c.getDatabasePath(),
+	}
+
+	var operations []interfaces.FirestoreOperation
+	it := c.admin.ListOperations(ctx, req, c.readCallOptions()...)
+	for {
+		op, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, wrapError(err, "failed to list operations")
+		}
+
+		result := convertOperationFromAPI(op)
+		if result.Kind == "" {
+			continue
+		}
+		operations = append(operations, *result)
+	}
+
+	return operations, nil
+}
+
+// CancelOperation requests cancellation of an operation. Firestore stops it
+// on a best-effort basis.
+func (c *Client) CancelOperation(ctx context.Context, operationName string) error {
+	req := &longrunningpb.CancelOperationRequest{
+		Name: operationName,
+	}
+	if err := c.admin.CancelOperation(ctx, req, c.callOptions()...); err != nil {
+		return wrapError(err, fmt.Sprintf("failed to cancel operation %s", operationName))
+	}
+	return nil
+}
+
+// convertOperationFromAPI converts an index or field operation to domain
+// model. Kind is empty for other operations.
+func convertOperationFromAPI(op *longrunningpb.Operation) *interfaces.FirestoreOperation {
+	result := &interfaces.FirestoreOperation{
+		Name: op.GetName(),
+		Done: op.GetDone(),
+	}
+
+	var progressDocs, progressBytes *adminpb.Progress
+	var startTime, endTime *timestamppb.Timestamp
+	if meta := indexOperationMetadata(op); meta != nil {
+		result.Kind = "index"
+		result.Target = meta.GetIndex()
+		result.State = meta.GetState().String()
+		progressDocs, progressBytes = meta.GetProgressDocuments(), meta.GetProgressBytes()
+		startTime, endTime = meta.GetStartTime(), meta.GetEndTime()
+	} else if meta := fieldOperationMetadata(op); meta != nil {
+		result.Kind = "field"
+		result.Target = meta.GetField()
+		result.State = meta.GetState().String()
+		progressDocs, progressBytes = meta.GetProgressDocuments(), meta.GetProgressBytes()
+		startTime, endTime = meta.GetStartTime(), meta.GetEndTime()
+	} else {
+		return result
+	}
+
+	result.Collection = extractCollectionFromIndexName(result.Target)
+
+	result.DocumentsCompleted = progressDocs.GetCompletedWork()
+	result.DocumentsEstimated = progressDocs.GetEstimatedWork()
+	result.BytesCompleted = progressBytes.GetCompletedWork()
+	result.BytesEstimated = progressBytes.GetEstimatedWork()
+	if startTime != nil {
+		result.StartTime = startTime.AsTime()
+	}
+	if endTime != nil {
+		result.EndTime = endTime.AsTime()
+	}
+	return result
+}
+
+// fieldOperationMetadata returns the metadata of a field operation, or nil
+// for other operations
+func fieldOperationMetadata(op *longrunningpb.Operation) *adminpb.FieldOperationMetadata {
+	meta := &adminpb.FieldOperationMetadata{}
+	if op.GetMetadata() == nil || op.GetMetadata().UnmarshalTo(meta) != nil {
+		return nil
+	}
+	return meta
+}
@@ -0,0 +1,126 @@
+package firestore
+
+import (
+	"time"
+
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy configures retries of Admin API calls failing with a transient
+// error: UNAVAILABLE, RESOURCE_EXHAUSTED or ABORTED, and for idempotent reads
+// also INTERNAL or DEADLINE_EXCEEDED. Zero fields take the values of
+// DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls including the first one; 1
+	// disables retries
+	MaxAttempts int
+	// InitialBackoff is the maximum delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the maximum delay between retries
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the maximum delay grows per retry
+	Multiplier float64
+}
+
+// DefaultRetryPolicy returns the retry policy used unless configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// withDefaults fills the zero fields of the policy with the defaults
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	def := DefaultRetryPolicy()
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.InitialBackoff == 0 {
+		p.InitialBackoff = def.InitialBackoff
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = def.MaxBackoff
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = def.Multiplier
+	}
+	return p
+}
+
+// retryableCodes are the gRPC status codes of transient errors
+var retryableCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.ResourceExhausted: true,
+	codes.Aborted:           true,
+}
+
+// readRetryableCodes are the gRPC status codes retried for idempotent reads.
+// Like the defaults of the generated client, they include INTERNAL and
+// DEADLINE_EXCEEDED, which may be returned after a mutation was applied and
+// are therefore not retried for mutations.
+var readRetryableCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.ResourceExhausted: true,
+	codes.Aborted:           true,
+	codes.Internal:          true,
+	codes.DeadlineExceeded:  true,
+}
+
+// retryer retries transient errors with exponential backoff and full jitter,
+// i.e. each delay is chosen at random up to the current maximum
+type retryer struct {
+	maxAttempts int
+	attempts    int
+	codes       map[codes.Code]bool
+	backoff     gax.Backoff
+}
+
+func newRetryer(policy RetryPolicy, retryable map[codes.Code]bool) *retryer {
+	return &retryer{
+		maxAttempts: policy.MaxAttempts,
+		codes:       retryable,
+		attempts:    1,
+		backoff: gax.Backoff{
+			Initial:    policy.InitialBackoff,
+			Max:        policy.MaxBackoff,
+			Multiplier: policy.Multiplier,
+		},
+	}
+}
+
+// Retry implements gax.Retryer
+func (r *retryer) Retry(err error) (time.Duration, bool) {
+	if r.attempts >= r.maxAttempts {
+		return 0, false
+	}
+	s, ok := status.FromError(err)
+	if !ok || !r.codes[s.Code()] {
+		return 0, false
+	}
+	r.attempts++
+	return r.backoff.Pause(), true
+}
+
+// callOptions returns the options of an Admin API mutation applying the
+// retry policy of the client. They replace the default retries of the
+// generated client.
+func (c *Client) callOptions() []gax.CallOption {
+	policy := c.retry
+	return []gax.CallOption{
+		gax.WithRetry(func() gax.Retryer { return newRetryer(policy, retryableCodes) }),
+	}
+}
+
+// readCallOptions returns the options of an idempotent Admin API read
+// applying the retry policy of the client. Iterators apply them to each page.
+func (c *Client) readCallOptions() []gax.CallOption {
+	policy := c.retry
+	return []gax.CallOption{
+		gax.WithRetry(func() gax.Retryer { return newRetryer(policy, readRetryableCodes) }),
+	}
+}
@@ -0,0 +1,73 @@
+package firestore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/firestore"
+	"github.com/m-mizutani/gt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := firestore.RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     250 * time.Millisecond,
+	}
+
+	t.Run("Normal: transient errors are retried with jittered backoff", func(t *testing.T) {
+		for _, code := range []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted} {
+			retry := firestore.NewRetryFunc(policy)
+			limits := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 250 * time.Millisecond}
+			for _, limit := range limits {
+				delay, ok := retry(status.Error(code, "try again"))
+				gt.True(t, ok)
+				gt.True(t, delay > 0 && delay <= limit)
+			}
+
+			// MaxAttempts includes the first call
+			_, ok := retry(status.Error(code, "try again"))
+			gt.False(t, ok)
+		}
+	})
+
+	t.Run("Normal: other errors are not retried", func(t *testing.T) {
+		for _, err := range []error{
+			status.Error(codes.PermissionDenied, "denied"),
+			status.Error(codes.AlreadyExists, "exists"),
+			context.Canceled,
+		} {
+			_, ok := firestore.NewRetryFunc(policy)(err)
+			gt.False(t, ok)
+		}
+	})
+
+	t.Run("Normal: mutations do not retry errors that may follow a change", func(t *testing.T) {
+		for _, code := range []codes.Code{codes.Internal, codes.DeadlineExceeded} {
+			_, ok := firestore.NewRetryFunc(policy)(status.Error(code, "failed"))
+			gt.False(t, ok)
+		}
+	})
+
+	t.Run("Normal: reads retry the codes of the generated client defaults", func(t *testing.T) {
+		retryable := map[codes.Code]bool{
+			codes.Unavailable:       true,
+			codes.ResourceExhausted: true,
+			codes.Aborted:           true,
+			codes.Internal:          true,
+			codes.DeadlineExceeded:  true,
+		}
+		for code := codes.OK; code <= codes.Unauthenticated; code++ {
+			_, ok := firestore.NewReadRetryFunc(policy)(status.Error(code, "failed"))
+			gt.Equal(t, ok, retryable[code])
+		}
+	})
+
+	t.Run("Normal: a single attempt disables retries", func(t *testing.T) {
+		_, ok := firestore.NewRetryFunc(firestore.RetryPolicy{MaxAttempts: 1})(status.Error(codes.Unavailable, "try again"))
+		gt.False(t, ok)
+	})
+}
@@ -2,13 +2,14 @@ package firestore
 
 import (
 	"context"
-	"fmt"
 	"path"
 
 	adminpb "cloud.google.com/go/firestore/apiv1/admin/adminpb"
 	"github.com/m-mizutani/fireconf/internal/interfaces"
 	"google.golang.org/api/iterator"
 	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GetTTLPolicy gets the TTL policy for a specific field
@@ -19,14 +20,14 @@ func (c *Client) GetTTLPolicy(ctx context.Context, collectionID string, fieldNam
 		Filter: "ttlConfig:*",
 	}
 
-	it := c.admin.ListFields(ctx, req)
+	it := c.admin.ListFields(ctx, req, c.readCallOptions()...)
 	for {
 		field, err := it.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list TTL policies: %w", err)
+			return nil, wrapError(err, "failed to list TTL policies")
 		}
 
 		// Check if this is the field we're looking for
@@ -45,15 +46,11 @@ func (c *Client) GetTTLPolicy(ctx context.Context, collectionID string, fieldNam
 	return nil, nil
 }
 
-// EnableTTLPolicy enables TTL policy on a field
+// EnableTTLPolicy enables TTL policy on a field. The single-field index
+// configuration of the field is left as it is; see UpdateFieldIndexConfig.
 func (c *Client) EnableTTLPolicy(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
 	fieldPath := c.getFieldPath(collectionID, fieldName)
 
-	// First, disable indexing on the TTL field to avoid hotspots
-	if err := c.disableIndexOnTTLField(ctx, collectionID, fieldName); err != nil {
-		return nil, fmt.Errorf("failed to disable index on TTL field: %w", err)
-	}
-
 	// Enable TTL policy
 	req := &adminpb.UpdateFieldRequest{
 		Field: &adminpb.Field{
@@ -67,9 +64,9 @@ func (c *Client) EnableTTLPolicy(ctx context.Context, collectionID string, field
 		},
 	}
 
-	op, err := c.admin.UpdateField(ctx, req)
+	op, err := c.admin.UpdateField(ctx, req, c.callOptions()...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to enable TTL policy: %w", err)
+		return nil, wrapError(err, "failed to enable TTL policy")
 	}
 
 	return op, nil
@@ -101,38 +98,98 @@ func (c *Client) DisableTTLPolicy(ctx context.Context, collectionID string) (int
 		},
 	}
 
-	op, err := c.admin.UpdateField(ctx, req)
+	op, err := c.admin.UpdateField(ctx, req, c.callOptions()...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to disable TTL policy: %w", err)
+		return nil, wrapError(err, "failed to disable TTL policy")
 	}
 
 	return op, nil
 }
 
-// disableIndexOnTTLField disables single-field index on TTL field to avoid hotspots
-func (c *Client) disableIndexOnTTLField(ctx context.Context, collectionID string, fieldName string) error {
-	fieldPath := c.getFieldPath(collectionID, fieldName)
+// GetFieldIndexConfig gets the single-field index configuration of a field
+func (c *Client) GetFieldIndexConfig(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+	field, err := c.admin.GetField(ctx, &adminpb.GetFieldRequest{
+		Name: c.getFieldPath(collectionID, fieldName),
+	}, c.readCallOptions()...)
+	if err != nil {
+		// Fields without their own configuration may not exist yet
+		if s, ok := status.FromError(err); ok && s.Code() == codes.NotFound {
+			return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+		}
+		return nil, wrapError(err, "failed to get field")
+	}
+
+	indexConfig := field.GetIndexConfig()
+	if indexConfig == nil {
+		return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+	}
+
+	result := &interfaces.FirestoreFieldIndexConfig{
+		UsesAncestorConfig: indexConfig.GetUsesAncestorConfig(),
+	}
+	for _, index := range indexConfig.GetIndexes() {
+		fieldIndex := interfaces.FirestoreFieldIndex{
+			QueryScope: index.GetQueryScope().String(),
+		}
+		for _, f := range index.GetFields() {
+			switch v := f.ValueMode.(type) {
+			case *adminpb.Index_IndexField_Order_:
+				fieldIndex.Order = v.Order.String()
+			case *adminpb.Index_IndexField_ArrayConfig_:
+				fieldIndex.ArrayConfig = v.ArrayConfig.String()
+			}
+		}
+		result.Indexes = append(result.Indexes, fieldIndex)
+	}
+
+	return result, nil
+}
+
+// UpdateFieldIndexConfig replaces the single-field index configuration of a
+// field. A nil config clears the configuration of the field so that it uses
+// the database defaults again.
+func (c *Client) UpdateFieldIndexConfig(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+	field := &adminpb.Field{
+		Name: c.getFieldPath(collectionID, fieldName),
+	}
+
+	if config != nil {
+		field.IndexConfig = &adminpb.Field_IndexConfig{
+			Indexes: []*adminpb.Index{}, // Empty means no single-field indexes
+		}
+		for _, idx := range config.Indexes {
+			apiField := &adminpb.Index_IndexField{FieldPath: fieldName}
+			if idx.ArrayConfig != "" {
+				apiField.ValueMode = &adminpb.Index_IndexField_ArrayConfig_{
+					ArrayConfig: adminpb.Index_IndexField_CONTAINS,
+				}
+			} else {
+				order := adminpb.Index_IndexField_ASCENDING
+				if idx.Order == "DESCENDING" {
+					order = adminpb.Index_IndexField_DESCENDING
+				}
+				apiField.ValueMode = &adminpb.Index_IndexField_Order_{Order: order}
+			}
+			field.IndexConfig.Indexes = append(field.IndexConfig.Indexes, &adminpb.Index{
+				QueryScope: convertQueryScope(idx.QueryScope),
+				Fields:     []*adminpb.Index_IndexField{apiField},
+			})
+		}
+	}
 
 	req := &adminpb.UpdateFieldRequest{
-		Field: &adminpb.Field{
-			Name: fieldPath,
-			IndexConfig: &adminpb.Field_IndexConfig{
-				Indexes: []*adminpb.Index{}, // Empty means no single-field indexes
-			},
-		},
+		Field: field,
 		UpdateMask: &fieldmaskpb.FieldMask{
 			Paths: []string{"index_config"},
 		},
 	}
 
-	// Fire-and-forget: disabling the single-field index is a hotspot prevention
-	// optimization. We do not need to wait for this operation before enabling TTL.
-	_, err := c.admin.UpdateField(ctx, req)
+	op, err := c.admin.UpdateField(ctx, req, c.callOptions()...)
 	if err != nil {
-		return fmt.Errorf("failed to update field index config: %w", err)
+		return nil, wrapError(err, "failed to update field index config")
 	}
 
-	return nil
+	return op, nil
 }
 
 // FindTTLField finds which field has TTL enabled in a collection
@@ -142,14 +199,14 @@ func (c *Client) FindTTLField(ctx context.Context, collectionID string) (string,
 		Filter: "ttlConfig:*",
 	}
 
-	it := c.admin.ListFields(ctx, req)
+	it := c.admin.ListFields(ctx, req, c.readCallOptions()...)
 	for {
 		field, err := it.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
-			return "", fmt.Errorf("failed to list TTL policies: %w", err)
+			return "", wrapError(err, "failed to list TTL policies")
 		}
 
 		ttlConfig := field.GetTtlConfig()
@@ -0,0 +1,247 @@
+// Package querylog reads executed Firestore queries from JSON lines logs.
+package querylog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+)
+
+// maxLineSize bounds the size of a single log line
+const maxLineSize = 16 * 1024 * 1024
+
+// queryRecord is a query shape as logged by the application, using the same
+// keys as the query description file
+type queryRecord struct {
+	Collection    string   `json:"collection"`
+	QueryScope    string   `json:"queryScope"`
+	Equality      []string `json:"equality"`
+	Range         []string `json:"range"`
+	ArrayContains string   `json:"arrayContains"`
+	OrderBy       []struct {
+		Path  string `json:"path"`
+		Order string `json:"order"`
+	} `json:"orderBy"`
+	FindNearest *struct {
+		Path      string `json:"path"`
+		Dimension int    `json:"dimension"`
+	} `json:"findNearest"`
+}
+
+// shapeKeys are the keys identifying a query shape record
+var shapeKeys = []string{"equality", "range", "arraycontains", "orderby", "findnearest"}
+
+// Parse reads JSON lines and returns the executed queries. Two kinds of
+// records are recognized, possibly nested in a log envelope such as the
+// jsonPayload of Cloud Logging:
+//
+//   - query explain output, whose planSummary.indexesUsed lists the used
+//     indexes as {"query_scope": "Collection", "properties": "(a ASC, __name__ ASC)"}.
+//     Explain output does not contain the collection; a "collection" key
+//     next to it is used when present.
+//   - query shapes: {"collection": "orders", "equality": [...], "orderBy": [...]}
+//
+// It also returns the number of non-empty lines that are not such a record.
+func Parse(r io.Reader) ([]model.QueryUsage, int, error) {
+	var usages []model.QueryUsage
+	skipped := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record map[string]any
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			skipped++
+			continue
+		}
+
+		found, err := parseRecord(record)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid record at line %d: %w", line, err)
+		}
+		if len(found) == 0 {
+			skipped++
+			continue
+		}
+		for _, u := range found {
+			u.Line = line
+			usages = append(usages, u)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read query log: %w", err)
+	}
+
+	return usages, skipped, nil
+}
+
+// parseRecord converts a single log record
+func parseRecord(record map[string]any) ([]model.QueryUsage, error) {
+	collection, _ := findValue(record, "collection", "collectionid").(string)
+
+	if used, ok := findValue(record, "indexesused").([]any); ok {
+		var result []model.QueryUsage
+		for _, entry := range used {
+			m, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			properties, _ := lookup(m, "properties").(string)
+			scope, _ := lookup(m, "queryscope").(string)
+			index, err := parseProperties(properties, scope)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, model.QueryUsage{Collection: collection, Index: index})
+		}
+		return result, nil
+	}
+
+	shape := findShape(record)
+	if shape == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(shape)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+	var q queryRecord
+	if err := json.Unmarshal(data, &q); err != nil {
+		return nil, fmt.Errorf("failed to decode query: %w", err)
+	}
+
+	query := &model.Query{
+		Collection:    q.Collection,
+		QueryScope:    q.QueryScope,
+		Equality:      q.Equality,
+		Range:         q.Range,
+		ArrayContains: q.ArrayContains,
+	}
+	for _, order := range q.OrderBy {
+		query.OrderBy = append(query.OrderBy, model.QueryOrder{Field: order.Path, Order: order.Order})
+	}
+	if q.FindNearest != nil {
+		query.FindNearest = &model.FindNearest{Field: q.FindNearest.Path, Dimension: q.FindNearest.Dimension}
+	}
+
+	return []model.QueryUsage{{Collection: q.Collection, Query: query}}, nil
+}
+
+// propertyPattern matches a single property of explain output such as
+// "createdAt DESC", "tags CONTAINS" or "embedding VECTOR<768>"
+var propertyPattern = regexp.MustCompile(`^(.+?)\s+(ASC|DESC|CONTAINS|ARRAY_CONTAINS|VECTOR)\W*(\d*)\W*$`)
+
+// parseProperties converts the properties of an explain index entry, e.g.
+// "(status ASC, createdAt DESC, __name__ DESC)", into an index. The
+// dimension of a vector field is 0 if the output does not include it.
+func parseProperties(properties, scope string) (*model.Index, error) {
+	properties = strings.TrimSpace(properties)
+	properties = strings.TrimSuffix(strings.TrimPrefix(properties, "("), ")")
+	if properties == "" {
+		return nil, fmt.Errorf("index properties are empty")
+	}
+
+	index := &model.Index{QueryScope: "COLLECTION"}
+	if strings.EqualFold(strings.ReplaceAll(strings.ReplaceAll(scope, " ", ""), "_", ""), "collectiongroup") {
+		index.QueryScope = "COLLECTION_GROUP"
+	}
+
+	for _, prop := range strings.Split(properties, ",") {
+		m := propertyPattern.FindStringSubmatch(strings.TrimSpace(prop))
+		if m == nil {
+			return nil, fmt.Errorf("unsupported index property: %q", prop)
+		}
+		field := model.IndexField{Name: strings.Trim(m[1], "`")}
+		switch m[2] {
+		case "ASC":
+			field.Order = "ASCENDING"
+		case "DESC":
+			field.Order = "DESCENDING"
+		case "CONTAINS", "ARRAY_CONTAINS":
+			field.ArrayConfig = "CONTAINS"
+		case "VECTOR":
+			dimension, _ := strconv.Atoi(m[3])
+			field.VectorConfig = &model.VectorConfig{Dimension: dimension}
+		}
+		index.Fields = append(index.Fields, field)
+	}
+
+	return index, nil
+}
+
+// findShape returns the first object of the record that looks like a query
+// shape: it has a collection and at least one query clause
+func findShape(v any) map[string]any {
+	switch v := v.(type) {
+	case map[string]any:
+		if _, ok := lookup(v, "collection").(string); ok {
+			for _, key := range shapeKeys {
+				if lookup(v, key) != nil {
+					return v
+				}
+			}
+		}
+		for _, child := range v {
+			if shape := findShape(child); shape != nil {
+				return shape
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if shape := findShape(child); shape != nil {
+				return shape
+			}
+		}
+	}
+	return nil
+}
+
+// findValue searches the record depth-first for the first of the keys.
+// Keys are compared case-insensitively and without underscores, so that
+// both REST (indexesUsed) and client library (IndexesUsed, indexes_used)
+// spellings are found.
+func findValue(v any, keys ...string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, key := range keys {
+			if found := lookup(v, key); found != nil {
+				return found
+			}
+		}
+		for _, child := range v {
+			if found := findValue(child, keys...); found != nil {
+				return found
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if found := findValue(child, keys...); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// lookup returns the value of a normalized key of the object
+func lookup(m map[string]any, key string) any {
+	for k, v := range m {
+		if strings.ToLower(strings.ReplaceAll(k, "_", "")) == key {
+			return v
+		}
+	}
+	return nil
+}
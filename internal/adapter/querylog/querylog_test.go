@@ -0,0 +1,49 @@
+package querylog_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/querylog"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/gt"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Explain output with several indexes", func(t *testing.T) {
+		line := `{"collection":"posts","explainMetrics":{"planSummary":{"indexesUsed":[` +
+			`{"query_scope":"Collection group","properties":"(tags CONTAINS, score DESC, __name__ DESC)"},` +
+			`{"query_scope":"Collection","properties":"(author ASC, __name__ ASC)"}]}}}`
+		usages, skipped := gt.R2(querylog.Parse(strings.NewReader(line))).NoError(t)
+		gt.Equal(t, skipped, 0)
+		gt.Equal(t, len(usages), 2)
+		gt.Equal(t, usages[0].Collection, "posts")
+		gt.Equal(t, usages[0].Line, 1)
+		gt.Equal(t, *usages[0].Index, model.Index{
+			QueryScope: "COLLECTION_GROUP",
+			Fields: []model.IndexField{
+				{Name: "tags", ArrayConfig: "CONTAINS"},
+				{Name: "score", Order: "DESCENDING"},
+				{Name: "__name__", Order: "DESCENDING"},
+			},
+		})
+		gt.Equal(t, usages[1].Index.QueryScope, "COLLECTION")
+	})
+
+	t.Run("Vector property without dimension", func(t *testing.T) {
+		line := `{"indexesUsed":[{"query_scope":"Collection","properties":"(embedding VECTOR)"}]}`
+		usages, _ := gt.R2(querylog.Parse(strings.NewReader(line))).NoError(t)
+		gt.Equal(t, usages[0].Index.Fields[0].VectorConfig, &model.VectorConfig{Dimension: 0})
+	})
+
+	t.Run("Query shape", func(t *testing.T) {
+		lines := "not json\n\n" + `{"severity":"INFO","jsonPayload":{"query":{"collection":"orders","range":["total"],"findNearest":{"path":"embedding","dimension":3}}}}`
+		usages, skipped := gt.R2(querylog.Parse(strings.NewReader(lines))).NoError(t)
+		gt.Equal(t, skipped, 1)
+		gt.Equal(t, len(usages), 1)
+		gt.Equal(t, usages[0].Line, 3)
+		gt.Equal(t, usages[0].Collection, "orders")
+		gt.Equal(t, usages[0].Query.Range, []string{"total"})
+		gt.Equal(t, usages[0].Query.FindNearest, &model.FindNearest{Field: "embedding", Dimension: 3})
+	})
+}
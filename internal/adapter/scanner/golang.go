@@ -0,0 +1,373 @@
+package scanner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"golang.org/x/tools/go/packages"
+)
+
+// firestorePkgPath is the import path of the Firestore Go client library
+const firestorePkgPath = "cloud.google.com/go/firestore"
+
+// ScanGo loads the Go packages matching the patterns (e.g. "./...") relative
+// to dir and extracts the shape of every Firestore query chain such as
+// client.Collection("orders").Where("status", "==", s).OrderBy("createdAt", firestore.Desc).
+//
+// Only chains written as a single expression are detected. Queries built
+// across several statements (q = q.Where(...)) are not followed.
+func ScanGo(dir string, patterns ...string) ([]model.SourceQuery, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	// Dependencies are type-checked from source so that loading does not
+	// depend on the export data format of the installed Go toolchain
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir:   dir,
+		Tests: true,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var result []model.SourceQuery
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		s := &goScanner{fset: pkg.Fset, info: pkg.TypesInfo, dir: dir}
+		for _, file := range pkg.Syntax {
+			for _, q := range s.scanFile(file) {
+				// Test variants of a package share files with the package itself
+				if key := q.Position + "|" + q.Query.Collection; !seen[key] {
+					seen[key] = true
+					result = append(result, q)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// goScanner extracts query shapes from the syntax of a single package
+type goScanner struct {
+	fset *token.FileSet
+	info *types.Info
+	dir  string
+}
+
+// scanFile returns every query chain of the file. Calls that are part of an
+// already reported chain are skipped, so that each chain is reported once
+// from its outermost call.
+func (s *goScanner) scanFile(file *ast.File) []model.SourceQuery {
+	var result []model.SourceQuery
+	visited := make(map[*ast.CallExpr]bool)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || visited[call] {
+			return true
+		}
+
+		chain, root := s.unwind(call)
+		if root == nil {
+			return true
+		}
+		for _, c := range chain {
+			visited[c] = true
+		}
+
+		q, ok := s.buildQuery(root, chain)
+		if ok {
+			result = append(result, q)
+		}
+		return true
+	})
+
+	return result
+}
+
+// unwind follows a method call chain down to its root Collection or
+// CollectionGroup call. It returns the chained calls from the innermost to
+// the outermost and the root call, or a nil root if the expression is not a
+// Firestore query chain.
+func (s *goScanner) unwind(call *ast.CallExpr) ([]*ast.CallExpr, *ast.CallExpr) {
+	var chain []*ast.CallExpr
+	for {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !s.isFirestoreMethod(sel) {
+			return nil, nil
+		}
+
+		switch sel.Sel.Name {
+		case "Collection", "CollectionGroup":
+			// Reverse to innermost-first order
+			for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+				chain[i], chain[j] = chain[j], chain[i]
+			}
+			return chain, call
+		}
+
+		chain = append(chain, call)
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return nil, nil
+		}
+		call = inner
+	}
+}
+
+// isFirestoreMethod reports whether the selector refers to a method of the
+// Firestore client library. Without type information, every selector is
+// accepted and the chain is identified by method names only.
+func (s *goScanner) isFirestoreMethod(sel *ast.SelectorExpr) bool {
+	if s.info == nil {
+		return true
+	}
+	selection, ok := s.info.Selections[sel]
+	if !ok {
+		// Type checking failed for this expression; fall back to names
+		return s.info.Uses[sel.Sel] == nil
+	}
+	pkg := selection.Obj().Pkg()
+	return pkg != nil && pkg.Path() == firestorePkgPath
+}
+
+// buildQuery converts the chain into a query shape. Chains without any
+// filter, orderBy or findNearest clause are not reported since they never
+// need a composite index.
+func (s *goScanner) buildQuery(root *ast.CallExpr, chain []*ast.CallExpr) (model.SourceQuery, bool) {
+	result := model.SourceQuery{Position: s.position(root)}
+	q := &result.Query
+	unresolved := func(format string, args ...any) {
+		if result.Unresolved == "" {
+			result.Unresolved = fmt.Sprintf(format, args...)
+		}
+	}
+
+	sel := root.Fun.(*ast.SelectorExpr)
+	if sel.Sel.Name == "CollectionGroup" {
+		q.QueryScope = "COLLECTION_GROUP"
+	}
+	if name, ok := s.stringArg(root, 0); ok {
+		// Collection paths such as "users/alice/orders" address the last segment
+		q.Collection = name[strings.LastIndex(name, "/")+1:]
+	} else {
+		unresolved("collection name is not a constant")
+	}
+
+	relevant := false
+	for _, call := range chain {
+		method := call.Fun.(*ast.SelectorExpr).Sel.Name
+		switch method {
+		case "Where", "WherePath":
+			relevant = true
+			path, ok1 := s.pathArg(call, 0)
+			op, ok2 := s.stringArg(call, 1)
+			if !ok1 || !ok2 {
+				unresolved("%s arguments are not constants", method)
+				continue
+			}
+			if err := q.AddFilter(path, op); err != nil {
+				unresolved("%s", err.Error())
+			}
+
+		case "WhereEntity":
+			relevant = true
+			if len(call.Args) == 0 || !s.addEntityFilter(q, call.Args[0]) {
+				unresolved("WhereEntity filter is not a constant PropertyFilter")
+			}
+
+		case "OrderBy", "OrderByPath":
+			relevant = true
+			path, ok := s.pathArg(call, 0)
+			if !ok {
+				unresolved("%s field is not a constant", method)
+				continue
+			}
+			order, ok := s.directionArg(call, 1)
+			if !ok {
+				unresolved("%s direction is not a constant", method)
+				continue
+			}
+			q.OrderBy = append(q.OrderBy, model.QueryOrder{Field: path, Order: order})
+
+		case "FindNearest", "FindNearestPath":
+			relevant = true
+			path, ok := s.pathArg(call, 0)
+			if !ok {
+				unresolved("%s field is not a constant", method)
+				continue
+			}
+			q.FindNearest = &model.FindNearest{Field: path, Dimension: s.vectorDimension(call, 1)}
+		}
+	}
+
+	return result, relevant
+}
+
+// addEntityFilter records a PropertyFilter or PropertyPathFilter composite
+// literal. Composite filters (AndFilter, OrFilter) are not supported.
+func (s *goScanner) addEntityFilter(q *model.Query, expr ast.Expr) bool {
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		expr = u.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+
+	var path, op string
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return false
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		switch key.Name {
+		case "Path":
+			if path, ok = s.pathExpr(kv.Value); !ok {
+				return false
+			}
+		case "Operator":
+			if op, ok = s.stringExpr(kv.Value); !ok {
+				return false
+			}
+		}
+	}
+
+	return path != "" && q.AddFilter(path, op) == nil
+}
+
+// stringArg returns the constant string value of the i-th argument
+func (s *goScanner) stringArg(call *ast.CallExpr, i int) (string, bool) {
+	if i >= len(call.Args) {
+		return "", false
+	}
+	return s.stringExpr(call.Args[i])
+}
+
+// stringExpr returns the constant string value of the expression
+func (s *goScanner) stringExpr(expr ast.Expr) (string, bool) {
+	if s.info != nil {
+		if tv, ok := s.info.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return constant.StringVal(tv.Value), true
+		}
+	}
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		v, err := strconv.Unquote(lit.Value)
+		return v, err == nil
+	}
+	return "", false
+}
+
+// pathArg returns the field path of the i-th argument, which is either a
+// constant string or a firestore.FieldPath literal
+func (s *goScanner) pathArg(call *ast.CallExpr, i int) (string, bool) {
+	if i >= len(call.Args) {
+		return "", false
+	}
+	return s.pathExpr(call.Args[i])
+}
+
+// pathExpr returns the field path of a constant string or a FieldPath literal
+func (s *goScanner) pathExpr(expr ast.Expr) (string, bool) {
+	if path, ok := s.stringExpr(expr); ok {
+		return path, true
+	}
+
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return "", false
+	}
+	parts := make([]string, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		part, ok := s.stringExpr(elt)
+		if !ok {
+			return "", false
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "."), len(parts) > 0
+}
+
+// directionArg returns the order of a firestore.Asc / firestore.Desc argument
+func (s *goScanner) directionArg(call *ast.CallExpr, i int) (string, bool) {
+	if i >= len(call.Args) {
+		return "", false
+	}
+	expr := call.Args[i]
+
+	if s.info != nil {
+		if tv, ok := s.info.Types[expr]; ok && tv.Value != nil {
+			if v, exact := constant.Int64Val(tv.Value); exact {
+				switch v {
+				case 1:
+					return "ASCENDING", true
+				case 2:
+					return "DESCENDING", true
+				}
+			}
+		}
+	}
+
+	var name string
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	case *ast.Ident:
+		name = e.Name
+	}
+	switch name {
+	case "Asc":
+		return "ASCENDING", true
+	case "Desc":
+		return "DESCENDING", true
+	}
+	return "", false
+}
+
+// vectorDimension returns the number of elements of a literal query vector,
+// or 0 if the dimension cannot be determined statically
+func (s *goScanner) vectorDimension(call *ast.CallExpr, i int) int {
+	if i >= len(call.Args) {
+		return 0
+	}
+	if lit, ok := call.Args[i].(*ast.CompositeLit); ok {
+		return len(lit.Elts)
+	}
+	return 0
+}
+
+// position returns "file:line" of the node, relative to the scan directory
+// when possible
+func (s *goScanner) position(node ast.Node) string {
+	return relativePosition(s.fset.Position(node.Pos()), s.dir)
+}
+
+// relativePosition formats a position as "file:line", with the file name
+// relative to dir when it is inside dir
+func relativePosition(pos token.Position, dir string) string {
+	filename := pos.Filename
+	if dir != "" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			if rel, err := filepath.Rel(abs, filename); err == nil && !strings.HasPrefix(rel, "..") {
+				filename = rel
+			}
+		}
+	}
+	return fmt.Sprintf("%s:%d", filename, pos.Line)
+}
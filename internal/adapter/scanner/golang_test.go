@@ -0,0 +1,52 @@
+package scanner_test
+
+import (
+	"testing"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/scanner"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/gt"
+)
+
+func TestScanGo(t *testing.T) {
+	queries, err := scanner.ScanGo(".", "./testdata/goapp")
+	gt.NoError(t, err)
+	gt.Equal(t, len(queries), 5)
+
+	t.Run("Collection with equality and orderBy", func(t *testing.T) {
+		q := queries[0]
+		gt.Equal(t, q.Position, "testdata/goapp/app.go:13")
+		gt.Equal(t, q.Unresolved, "")
+		gt.Equal(t, q.Query.Collection, "orders")
+		gt.Equal(t, q.Query.QueryScope, "")
+		gt.Equal(t, q.Query.Equality, []string{"customerId"})
+		gt.Equal(t, q.Query.OrderBy, []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}})
+	})
+
+	t.Run("Collection group with in and range", func(t *testing.T) {
+		q := queries[1]
+		gt.Equal(t, q.Query.QueryScope, "COLLECTION_GROUP")
+		gt.Equal(t, q.Query.Equality, []string{"status"})
+		gt.Equal(t, q.Query.Range, []string{"total"})
+	})
+
+	t.Run("Subcollection with entity filter and field path", func(t *testing.T) {
+		q := queries[2]
+		gt.Equal(t, q.Query.Collection, "posts")
+		gt.Equal(t, q.Query.ArrayContains, "tags")
+		gt.Equal(t, q.Query.OrderBy, []model.QueryOrder{{Field: "stats.score", Order: "ASCENDING"}})
+	})
+
+	t.Run("Vector search", func(t *testing.T) {
+		q := queries[3]
+		gt.Equal(t, q.Query.Collection, "documents")
+		gt.Equal(t, q.Query.Equality, []string{"category"})
+		gt.Equal(t, q.Query.FindNearest, &model.FindNearest{Field: "embedding", Dimension: 3})
+	})
+
+	t.Run("Non-constant field is reported as unresolved", func(t *testing.T) {
+		q := queries[4]
+		gt.Equal(t, q.Query.Collection, "users")
+		gt.Equal(t, q.Unresolved, "Where arguments are not constants")
+	})
+}
@@ -0,0 +1,664 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+)
+
+// jsExtensions lists the file extensions scanned by ScanJS
+var jsExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+	".ts": true, ".tsx": true, ".mts": true, ".cts": true,
+}
+
+// ScanJS walks the files and directories and extracts the shape of every
+// Firestore query of JavaScript and TypeScript sources. Two styles are
+// recognized:
+//
+//   - the modular Web SDK: query(collection(db, "orders"), where("status", "==", s), orderBy("createdAt", "desc"))
+//   - the namespaced Web SDK and the Admin SDK: db.collection("orders").where("status", "==", s).orderBy("createdAt", "desc")
+//
+// node_modules, hidden directories and declaration files are skipped.
+func ScanJS(paths ...string) ([]model.SourceQuery, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	var result []model.SourceQuery
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != root && (name == "node_modules" || strings.HasPrefix(name, ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if path != root && (!jsExtensions[filepath.Ext(path)] || isDeclarationFile(path)) {
+				return nil
+			}
+
+			src, err := os.ReadFile(path) // #nosec G304 - path is provided by user as CLI argument
+			if err != nil {
+				return err
+			}
+			result = append(result, scanJSSource(path, string(src))...)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	return result, nil
+}
+
+// isDeclarationFile reports whether the path is a TypeScript declaration file
+func isDeclarationFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasSuffix(base, ".d.ts") || strings.HasSuffix(base, ".d.mts") || strings.HasSuffix(base, ".d.cts")
+}
+
+// jsRange is a half-open range of token indexes forming an expression
+type jsRange struct{ lo, hi int }
+
+// jsScanner extracts query shapes from the tokens of a single file
+type jsScanner struct {
+	path   string
+	tokens []jsToken
+	// match maps the index of an opening bracket to its closing bracket
+	match map[int]int
+	// consts holds string constants declared in the file
+	consts map[string]string
+	// refs holds variables initialized with a collection() or
+	// collectionGroup() call, for query(ref, ...)
+	refs map[string]jsRange
+}
+
+// scanJSSource returns every query of a JavaScript/TypeScript source file
+func scanJSSource(path, src string) []model.SourceQuery {
+	s := &jsScanner{
+		path:   path,
+		tokens: tokenizeJS(src),
+		consts: make(map[string]string),
+		refs:   make(map[string]jsRange),
+	}
+	s.matchBrackets()
+	s.collectDeclarations()
+
+	var result []model.SourceQuery
+	nested := make(map[int]bool)
+	chained := make(map[int]bool)
+
+	for i := range s.tokens {
+		switch {
+		case s.isIdent(i, "query") && s.isPunct(i+1, "(") && !nested[i]:
+			if q, ok := s.modularQuery(i, nested); ok {
+				result = append(result, q)
+			}
+
+		case s.isPunct(i, ".") && (s.isIdent(i+1, "collection") || s.isIdent(i+1, "collectionGroup")) &&
+			s.isPunct(i+2, "(") && !chained[i+1]:
+			if q, ok := s.chainQuery(i+1, chained); ok {
+				result = append(result, q)
+			}
+		}
+	}
+
+	return result
+}
+
+// matchBrackets pairs opening and closing brackets. Unbalanced brackets are
+// left unmatched.
+func (s *jsScanner) matchBrackets() {
+	s.match = make(map[int]int)
+	pairs := map[string]string{")": "(", "]": "[", "}": "{"}
+	var stack []int
+	for i, tok := range s.tokens {
+		if tok.kind != jsPunct {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			stack = append(stack, i)
+		case ")", "]", "}":
+			// Pop up to the matching opener so that one stray bracket does
+			// not break the rest of the file
+			for n := len(stack) - 1; n >= 0; n-- {
+				if s.tokens[stack[n]].text == pairs[tok.text] {
+					s.match[stack[n]] = i
+					stack = stack[:n]
+					break
+				}
+			}
+		}
+	}
+}
+
+// collectDeclarations records `const NAME = "value"` string constants and
+// variables holding collection references. Names declared more than once
+// with different values are ambiguous and dropped.
+func (s *jsScanner) collectDeclarations() {
+	ambiguous := make(map[string]bool)
+	for i := range s.tokens {
+		if !(s.isIdent(i, "const") || s.isIdent(i, "let") || s.isIdent(i, "var")) ||
+			!s.isKind(i+1, jsIdent) {
+			continue
+		}
+		name := s.tokens[i+1].text
+
+		// Skip a TypeScript type annotation
+		j := i + 2
+		if s.isPunct(j, ":") {
+			for j < len(s.tokens) && !s.isPunct(j, "=") && !s.isPunct(j, ";") {
+				j++
+			}
+		}
+		if !s.isPunct(j, "=") {
+			continue
+		}
+		end := s.exprEnd(j + 1)
+		expr := jsRange{j + 1, end}
+
+		if v, ok := s.literal(expr); ok {
+			if old, exists := s.consts[name]; exists && old != v {
+				ambiguous[name] = true
+			}
+			s.consts[name] = v
+		} else if callee, _, ok := s.call(expr); ok && (callee == "collection" || callee == "collectionGroup") {
+			s.refs[name] = expr
+		}
+	}
+
+	for name := range ambiguous {
+		delete(s.consts, name)
+	}
+}
+
+// exprEnd returns the end of the expression starting at i, which is the next
+// top-level comma or semicolon, a closing bracket or the start of a new line
+// after a complete expression
+func (s *jsScanner) exprEnd(i int) int {
+	for j := i; j < len(s.tokens); j++ {
+		tok := s.tokens[j]
+		if tok.kind == jsPunct {
+			switch tok.text {
+			case ",", ";", ")", "]", "}":
+				return j
+			case "(", "[", "{":
+				end, ok := s.match[j]
+				if !ok {
+					return len(s.tokens)
+				}
+				j = end
+				continue
+			}
+		}
+		// Without semicolons, a new line between two operands ends the statement
+		if j > i && tok.line > s.tokens[j-1].line && tok.kind != jsPunct && s.endsOperand(j-1) {
+			return j
+		}
+	}
+	return len(s.tokens)
+}
+
+// modularQuery parses query(ref, constraint...) starting at the query
+// identifier. Nested query() calls in the first argument are merged and
+// recorded in nested so that they are not reported on their own.
+func (s *jsScanner) modularQuery(i int, nested map[int]bool) (model.SourceQuery, bool) {
+	result := model.SourceQuery{Position: s.position(i)}
+	args := s.args(i + 1)
+	if len(args) == 0 {
+		return result, false
+	}
+
+	// Constraints of nested queries apply before those of the outer query
+	constraints := [][]jsRange{args[1:]}
+	base := args[0]
+	for {
+		callee, open, ok := s.call(base)
+		if !ok || callee != "query" {
+			break
+		}
+		nested[open-1] = true
+		inner := s.args(open)
+		if len(inner) == 0 {
+			return result, false
+		}
+		constraints = append(constraints, inner[1:])
+		base = inner[0]
+	}
+
+	relevant := false
+	for n := len(constraints) - 1; n >= 0; n-- {
+		if s.applyConstraints(&result, constraints[n]) {
+			relevant = true
+		}
+	}
+
+	if !relevant {
+		return result, false
+	}
+
+	if ident, ok := s.ident(base); ok {
+		if ref, ok := s.refs[ident]; ok {
+			base = ref
+		}
+	}
+	callee, open, ok := s.call(base)
+	if !ok || (callee != "collection" && callee != "collectionGroup") {
+		setUnresolved(&result, "collection reference is not a collection() call")
+		return result, true
+	}
+	if callee == "collectionGroup" {
+		result.Query.QueryScope = "COLLECTION_GROUP"
+	}
+
+	// collection(db, "users", uid, "orders") addresses the last segment
+	refArgs := s.args(open)
+	if len(refArgs) < 2 {
+		setUnresolved(&result, "collection path is missing")
+		return result, true
+	}
+	if name, ok := s.collectionName(refArgs[len(refArgs)-1]); ok {
+		result.Query.Collection = name
+	} else {
+		setUnresolved(&result, "collection name is not a constant")
+	}
+
+	return result, true
+}
+
+// applyConstraints records the query constraints of a modular query. It
+// reports whether any constraint affects index selection.
+func (s *jsScanner) applyConstraints(result *model.SourceQuery, args []jsRange) bool {
+	relevant := false
+	for _, arg := range args {
+		callee, open, ok := s.call(arg)
+		if !ok {
+			continue
+		}
+		switch callee {
+		case "where", "and", "or":
+			relevant = true
+			s.applyFilter(result, arg)
+		case "orderBy":
+			relevant = true
+			s.applyOrderBy(result, s.args(open))
+		}
+	}
+	return relevant
+}
+
+// chainQuery parses a method chain starting at a collection or
+// collectionGroup identifier, e.g. db.collection("orders").where(...).
+// Every collection identifier of the chain is recorded in chained so that
+// subcollection chains are reported once.
+func (s *jsScanner) chainQuery(i int, chained map[int]bool) (model.SourceQuery, bool) {
+	var result model.SourceQuery
+	relevant := false
+	root := false
+
+	for {
+		name := s.tokens[i].text
+		open := i + 1
+		args := s.args(open)
+
+		switch name {
+		case "collection", "collectionGroup":
+			chained[i] = true
+			// A subcollection starts a new query
+			result = model.SourceQuery{Position: s.position(i)}
+			relevant = false
+			root = true
+			if name == "collectionGroup" {
+				result.Query.QueryScope = "COLLECTION_GROUP"
+			}
+			if len(args) == 0 {
+				setUnresolved(&result, "collection path is missing")
+			} else if coll, ok := s.collectionName(args[0]); ok {
+				result.Query.Collection = coll
+			} else {
+				setUnresolved(&result, "collection name is not a constant")
+			}
+
+		case "doc":
+			root = false
+
+		case "where":
+			relevant = true
+			if len(args) == 1 {
+				s.applyFilter(&result, args[0])
+			} else {
+				s.applyWhere(&result, args)
+			}
+
+		case "orderBy":
+			relevant = true
+			s.applyOrderBy(&result, args)
+
+		case "findNearest":
+			relevant = true
+			s.applyFindNearest(&result, args)
+		}
+
+		end, ok := s.match[open]
+		if !ok {
+			break
+		}
+		next := end + 1
+		if !s.isPunct(next, ".") || !s.isKind(next+1, jsIdent) || !s.isPunct(next+2, "(") {
+			break
+		}
+		i = next + 1
+	}
+
+	return result, root && relevant
+}
+
+// applyFilter records a filter expression: where(field, op, value) and the
+// composite and(...) filter, with or without the Filter namespace of the
+// Admin SDK. OR filters need one index per disjunction and are not resolved.
+func (s *jsScanner) applyFilter(result *model.SourceQuery, expr jsRange) {
+	callee, open, ok := s.call(expr)
+	if !ok {
+		setUnresolved(result, "filter is not a where() or and() call")
+		return
+	}
+	switch callee {
+	case "where":
+		s.applyWhere(result, s.args(open))
+	case "and":
+		for _, arg := range s.args(open) {
+			s.applyFilter(result, arg)
+		}
+	case "or":
+		setUnresolved(result, "or() filters are not supported")
+	default:
+		setUnresolved(result, "filter is not a where() or and() call")
+	}
+}
+
+// applyWhere records the field and operator of where(field, op, value)
+func (s *jsScanner) applyWhere(result *model.SourceQuery, args []jsRange) {
+	if len(args) < 2 {
+		setUnresolved(result, "where arguments are missing")
+		return
+	}
+	path, ok1 := s.fieldPath(args[0])
+	op, ok2 := s.literal(args[1])
+	if !ok1 || !ok2 {
+		setUnresolved(result, "where arguments are not constants")
+		return
+	}
+	if err := result.Query.AddFilter(path, op); err != nil {
+		setUnresolved(result, err.Error())
+	}
+}
+
+// applyOrderBy records orderBy(field, direction)
+func (s *jsScanner) applyOrderBy(result *model.SourceQuery, args []jsRange) {
+	if len(args) == 0 {
+		setUnresolved(result, "orderBy field is missing")
+		return
+	}
+	path, ok := s.fieldPath(args[0])
+	if !ok {
+		setUnresolved(result, "orderBy field is not a constant")
+		return
+	}
+
+	order := "ASCENDING"
+	if len(args) > 1 {
+		dir, ok := s.literal(args[1])
+		switch {
+		case ok && dir == "asc":
+		case ok && dir == "desc":
+			order = "DESCENDING"
+		default:
+			setUnresolved(result, "orderBy direction is not a constant")
+			return
+		}
+	}
+	result.Query.OrderBy = append(result.Query.OrderBy, model.QueryOrder{Field: path, Order: order})
+}
+
+// applyFindNearest records findNearest(field, vector, options) or
+// findNearest({vectorField, queryVector, ...}) of the Admin SDK
+func (s *jsScanner) applyFindNearest(result *model.SourceQuery, args []jsRange) {
+	if len(args) == 0 {
+		setUnresolved(result, "findNearest arguments are missing")
+		return
+	}
+
+	field, vector := args[0], jsRange{}
+	if len(args) > 1 {
+		vector = args[1]
+	}
+	if s.isPunct(args[0].lo, "{") {
+		var ok bool
+		if field, ok = s.property(args[0], "vectorField"); !ok {
+			setUnresolved(result, "findNearest vectorField is missing")
+			return
+		}
+		vector, _ = s.property(args[0], "queryVector")
+	}
+
+	path, ok := s.fieldPath(field)
+	if !ok {
+		setUnresolved(result, "findNearest field is not a constant")
+		return
+	}
+	result.Query.FindNearest = &model.FindNearest{Field: path, Dimension: s.vectorDimension(vector)}
+}
+
+// vectorDimension returns the length of a literal array or
+// FieldValue.vector([...]) argument, or 0 if unknown
+func (s *jsScanner) vectorDimension(expr jsRange) int {
+	if callee, open, ok := s.call(expr); ok && callee == "vector" {
+		args := s.args(open)
+		if len(args) != 1 {
+			return 0
+		}
+		expr = args[0]
+	}
+	if expr.hi-expr.lo < 2 || !s.isPunct(expr.lo, "[") || s.match[expr.lo] != expr.hi-1 {
+		return 0
+	}
+	return len(s.args(expr.lo))
+}
+
+// collectionName returns the last segment of a constant collection path.
+// For template literals such as `users/${uid}/orders` the last segment is
+// used if it is constant.
+func (s *jsScanner) collectionName(expr jsRange) (string, bool) {
+	if v, ok := s.literal(expr); ok {
+		return v[strings.LastIndex(v, "/")+1:], v != ""
+	}
+	if expr.hi-expr.lo == 1 && s.tokens[expr.lo].kind == jsTemplate {
+		raw := s.tokens[expr.lo].text
+		last := raw[strings.LastIndex(raw, "/")+1:]
+		if last != "" && !strings.Contains(last, "${") && !strings.Contains(last, "}") {
+			return last, true
+		}
+	}
+	return "", false
+}
+
+// fieldPath returns the field path of a constant string, new FieldPath(...)
+// or documentId() expression
+func (s *jsScanner) fieldPath(expr jsRange) (string, bool) {
+	if v, ok := s.literal(expr); ok {
+		return v, true
+	}
+
+	callee, open, ok := s.call(expr)
+	if !ok {
+		return "", false
+	}
+	switch callee {
+	case "documentId":
+		return "__name__", true
+	case "FieldPath":
+		args := s.args(open)
+		parts := make([]string, 0, len(args))
+		for _, arg := range args {
+			part, ok := s.literal(arg)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, "."), len(parts) > 0
+	}
+	return "", false
+}
+
+// literal returns the value of a string literal, a template literal without
+// substitutions or an identifier of a string constant. TypeScript `as`
+// assertions are ignored.
+func (s *jsScanner) literal(expr jsRange) (string, bool) {
+	if expr.hi-expr.lo >= 3 && s.isIdent(expr.hi-2, "as") {
+		expr.hi -= 2
+	}
+	if expr.hi-expr.lo != 1 {
+		return "", false
+	}
+	tok := s.tokens[expr.lo]
+	switch tok.kind {
+	case jsString:
+		return tok.text, true
+	case jsTemplate:
+		return tok.text, !strings.Contains(tok.text, "${")
+	case jsIdent:
+		v, ok := s.consts[tok.text]
+		return v, ok
+	}
+	return "", false
+}
+
+// ident returns the name of an expression consisting of a single identifier
+func (s *jsScanner) ident(expr jsRange) (string, bool) {
+	if expr.hi-expr.lo == 1 && s.tokens[expr.lo].kind == jsIdent {
+		return s.tokens[expr.lo].text, true
+	}
+	return "", false
+}
+
+// call matches expressions of the form [new] a.b.name<T>(...) and returns the
+// last name and the index of the opening parenthesis
+func (s *jsScanner) call(expr jsRange) (string, int, bool) {
+	i := expr.lo
+	if s.isIdent(i, "new") {
+		i++
+	}
+
+	name := ""
+	for {
+		if i >= expr.hi || s.tokens[i].kind != jsIdent {
+			return "", 0, false
+		}
+		name = s.tokens[i].text
+		i++
+		if !s.isPunct(i, ".") {
+			break
+		}
+		i++
+	}
+
+	// Skip type arguments such as collection<Order>(...)
+	if s.isPunct(i, "<") {
+		for i < expr.hi && !s.isPunct(i, ">") {
+			i++
+		}
+		i++
+	}
+
+	if !s.isPunct(i, "(") || s.match[i] != expr.hi-1 {
+		return "", 0, false
+	}
+	return name, i, true
+}
+
+// property returns the value of a property of an object literal
+func (s *jsScanner) property(obj jsRange, key string) (jsRange, bool) {
+	for _, prop := range s.args(obj.lo) {
+		if prop.hi-prop.lo >= 3 && s.isPunct(prop.lo+1, ":") {
+			tok := s.tokens[prop.lo]
+			if (tok.kind == jsIdent || tok.kind == jsString) && tok.text == key {
+				return jsRange{prop.lo + 2, prop.hi}, true
+			}
+		}
+	}
+	return jsRange{}, false
+}
+
+// args splits the contents of the bracket at open into its top-level
+// comma-separated elements
+func (s *jsScanner) args(open int) []jsRange {
+	end, ok := s.match[open]
+	if !ok {
+		return nil
+	}
+
+	var result []jsRange
+	start := open + 1
+	for i := start; i < end; i++ {
+		if c, ok := s.match[i]; ok {
+			i = c
+			continue
+		}
+		if s.isPunct(i, ",") {
+			result = append(result, jsRange{start, i})
+			start = i + 1
+		}
+	}
+	if start < end {
+		result = append(result, jsRange{start, end})
+	}
+	return result
+}
+
+// endsOperand reports whether the token can be the last token of an operand
+func (s *jsScanner) endsOperand(i int) bool {
+	if !s.isKind(i, jsPunct) {
+		return true
+	}
+	switch s.tokens[i].text {
+	case ")", "]", "}":
+		return true
+	}
+	return false
+}
+
+func (s *jsScanner) isKind(i int, kind jsTokenKind) bool {
+	return i >= 0 && i < len(s.tokens) && s.tokens[i].kind == kind
+}
+
+func (s *jsScanner) isIdent(i int, name string) bool {
+	return s.isKind(i, jsIdent) && s.tokens[i].text == name
+}
+
+func (s *jsScanner) isPunct(i int, text string) bool {
+	return s.isKind(i, jsPunct) && s.tokens[i].text == text
+}
+
+// position returns "file:line" of the token
+func (s *jsScanner) position(i int) string {
+	return fmt.Sprintf("%s:%d", s.path, s.tokens[i].line)
+}
+
+// setUnresolved records the first reason why a query is not fully resolved
+func setUnresolved(q *model.SourceQuery, reason string) {
+	if q.Unresolved == "" {
+		q.Unresolved = reason
+	}
+}
@@ -0,0 +1,70 @@
+package scanner_test
+
+import (
+	"testing"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/scanner"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/gt"
+)
+
+func TestScanJS(t *testing.T) {
+	queries, err := scanner.ScanJS("testdata/jsapp")
+	gt.NoError(t, err)
+	gt.Equal(t, len(queries), 7)
+
+	// Files are walked in lexical order: functions.js before web.ts
+	t.Run("Admin SDK chain on a subcollection with composite filter", func(t *testing.T) {
+		q := queries[0]
+		gt.Equal(t, q.Position, "testdata/jsapp/src/functions.js:5")
+		gt.Equal(t, q.Unresolved, "")
+		gt.Equal(t, q.Query.Collection, "events")
+		gt.Equal(t, q.Query.Equality, []string{"type", "geo.country"})
+		gt.Equal(t, q.Query.OrderBy, []model.QueryOrder{{Field: "at", Order: "DESCENDING"}})
+	})
+
+	t.Run("Admin SDK vector search", func(t *testing.T) {
+		q := queries[1]
+		gt.Equal(t, q.Query.Collection, "documents")
+		gt.Equal(t, q.Query.Equality, []string{"category"})
+		gt.Equal(t, q.Query.FindNearest, &model.FindNearest{Field: "embedding", Dimension: 3})
+	})
+
+	t.Run("OR filter is reported as unresolved", func(t *testing.T) {
+		q := queries[2]
+		gt.Equal(t, q.Query.Collection, "tasks")
+		gt.Equal(t, q.Query.QueryScope, "COLLECTION_GROUP")
+		gt.Equal(t, q.Unresolved, "or() filters are not supported")
+	})
+
+	t.Run("Modular query with constant collection", func(t *testing.T) {
+		q := queries[3]
+		gt.Equal(t, q.Position, "testdata/jsapp/src/web.ts:9")
+		gt.Equal(t, q.Unresolved, "")
+		gt.Equal(t, q.Query.Collection, "orders")
+		gt.Equal(t, q.Query.Equality, []string{"customerId"})
+		gt.Equal(t, q.Query.OrderBy, []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}})
+	})
+
+	t.Run("Modular query on a template collection path", func(t *testing.T) {
+		q := queries[4]
+		gt.Equal(t, q.Query.Collection, "posts")
+		gt.Equal(t, q.Query.ArrayContains, "tags")
+		gt.Equal(t, q.Query.OrderBy, []model.QueryOrder{{Field: "score", Order: "ASCENDING"}})
+	})
+
+	t.Run("Nested query on a collection group variable", func(t *testing.T) {
+		q := queries[5]
+		gt.Equal(t, q.Unresolved, "")
+		gt.Equal(t, q.Query.Collection, "orders")
+		gt.Equal(t, q.Query.QueryScope, "COLLECTION_GROUP")
+		gt.Equal(t, q.Query.Equality, []string{"status"})
+		gt.Equal(t, q.Query.Range, []string{"total"})
+	})
+
+	t.Run("Non-constant field is reported as unresolved", func(t *testing.T) {
+		q := queries[6]
+		gt.Equal(t, q.Query.Collection, "users")
+		gt.Equal(t, q.Unresolved, "where arguments are not constants")
+	})
+}
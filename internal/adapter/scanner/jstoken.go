@@ -0,0 +1,244 @@
+package scanner
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// jsTokenKind is the kind of a JavaScript/TypeScript token
+type jsTokenKind int
+
+const (
+	jsIdent jsTokenKind = iota
+	jsString
+	jsTemplate // template literal; text holds the raw content between backquotes
+	jsNumber
+	jsPunct
+)
+
+// jsToken is a token of JavaScript/TypeScript source. Strings hold their
+// unquoted value.
+type jsToken struct {
+	kind jsTokenKind
+	text string
+	line int
+}
+
+// jsRegexPrefix lists the keywords after which a slash starts a regular
+// expression literal rather than a division
+var jsRegexPrefix = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true,
+	"in": true, "of": true, "new": true, "delete": true, "void": true,
+	"throw": true, "yield": true, "await": true,
+}
+
+// tokenizeJS splits the source into tokens, dropping whitespace and
+// comments. It is not a complete lexer: it only has to be accurate enough
+// for the bracket structure and literals of Firestore query expressions to
+// survive, and recovers at the end of a line from anything it does not
+// understand (e.g. JSX text containing quotes).
+func tokenizeJS(src string) []jsToken {
+	var tokens []jsToken
+	line := 1
+	i := 0
+
+	emit := func(kind jsTokenKind, text string, startLine int) {
+		tokens = append(tokens, jsToken{kind: kind, text: text, line: startLine})
+	}
+
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+
+		case c == '\'' || c == '"':
+			start := line
+			value, n := readJSString(src[i:])
+			emit(jsString, value, start)
+			i += n
+
+		case c == '`':
+			start := line
+			n := skipJSTemplate(src[i:])
+			raw := src[i+1 : i+n]
+			if strings.HasSuffix(raw, "`") {
+				raw = raw[:len(raw)-1]
+			}
+			line += strings.Count(src[i:i+n], "\n")
+			emit(jsTemplate, raw, start)
+			i += n
+
+		case c == '/' && isJSRegexStart(tokens):
+			i += skipJSRegex(src[i:])
+
+		case isJSIdentStart(src[i:]):
+			j := i
+			for j < len(src) && isJSIdentPart(src[j:]) {
+				_, size := utf8.DecodeRuneInString(src[j:])
+				j += size
+			}
+			emit(jsIdent, src[i:j], line)
+			i = j
+
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (isJSIdentPart(src[j:]) || src[j] == '.') {
+				j++
+			}
+			emit(jsNumber, src[i:j], line)
+			i = j
+
+		case strings.HasPrefix(src[i:], "?."):
+			// Optional chaining behaves like member access for our purposes
+			emit(jsPunct, ".", line)
+			i += 2
+
+		case strings.HasPrefix(src[i:], "..."):
+			emit(jsPunct, "...", line)
+			i += 3
+
+		default:
+			emit(jsPunct, string(c), line)
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// readJSString reads a single or double quoted string literal and returns
+// its value and length in bytes. An unterminated literal ends at the line
+// break.
+func readJSString(src string) (string, int) {
+	quote := src[0]
+	var sb strings.Builder
+	i := 1
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == quote:
+			return sb.String(), i + 1
+		case c == '\n':
+			return sb.String(), i
+		case c == '\\' && i+1 < len(src):
+			switch e := src[i+1]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case '\n':
+				// line continuation
+			default:
+				sb.WriteByte(e)
+			}
+			i += 2
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String(), i
+}
+
+// skipJSTemplate returns the length of the template literal at the start of
+// src, including nested substitutions
+func skipJSTemplate(src string) int {
+	i := 1
+	for i < len(src) {
+		switch {
+		case src[i] == '\\':
+			i += 2
+		case src[i] == '`':
+			return i + 1
+		case strings.HasPrefix(src[i:], "${"):
+			i += 2
+			depth := 1
+			for i < len(src) && depth > 0 {
+				switch src[i] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				case '\'', '"':
+					_, n := readJSString(src[i:])
+					i += n - 1
+				case '`':
+					i += skipJSTemplate(src[i:]) - 1
+				}
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// skipJSRegex returns the length of the regular expression literal at the
+// start of src, including flags
+func skipJSRegex(src string) int {
+	i := 1
+	inClass := false
+	for i < len(src) && src[i] != '\n' {
+		switch c := src[i]; {
+		case c == '\\':
+			i++
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			i++
+			for i < len(src) && isJSIdentPart(src[i:]) {
+				i++
+			}
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+// isJSRegexStart reports whether a slash following the tokens starts a
+// regular expression literal
+func isJSRegexStart(tokens []jsToken) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	prev := tokens[len(tokens)-1]
+	switch prev.kind {
+	case jsIdent:
+		return jsRegexPrefix[prev.text]
+	case jsPunct:
+		return prev.text != ")" && prev.text != "]" && prev.text != "}"
+	}
+	return false
+}
+
+func isJSIdentStart(s string) bool {
+	c := s[0]
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= utf8.RuneSelf
+}
+
+func isJSIdentPart(s string) bool {
+	c := s[0]
+	return isJSIdentStart(s) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"golang.org/x/tools/go/packages"
+)
+
+// ScanGoStructs loads the Go packages matching the patterns relative to dir
+// and returns the named struct types that have at least one field with a
+// fireconf struct tag. Field paths are resolved the same way as by
+// reflection: the firestore struct tag or the field name, with nested
+// structs flattened into dotted paths.
+func ScanGoStructs(dir string, patterns ...string) ([]model.TaggedStruct, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	// See ScanGo for why dependencies are loaded
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var result []model.TaggedStruct
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("failed to load package %s: %w", pkg.PkgPath, pkg.Errors[0])
+		}
+
+		w := &structWalker{fset: pkg.Fset, dir: dir}
+		scope := pkg.Types.Scope()
+
+		// Report types in source order
+		var objs []*types.TypeName
+		for _, name := range scope.Names() {
+			if obj, ok := scope.Lookup(name).(*types.TypeName); ok && !obj.IsAlias() {
+				objs = append(objs, obj)
+			}
+		}
+		sort.Slice(objs, func(i, j int) bool { return objs[i].Pos() < objs[j].Pos() })
+
+		for _, obj := range objs {
+			st, ok := obj.Type().Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			fields := w.fields(st, "", map[*types.Struct]bool{st: true})
+			if len(fields) == 0 {
+				continue
+			}
+			result = append(result, model.TaggedStruct{
+				Name:   pkg.Name + "." + obj.Name(),
+				Fields: fields,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// structWalker collects tagged fields of struct types
+type structWalker struct {
+	fset *token.FileSet
+	dir  string
+}
+
+// fields collects the fields of st carrying a fireconf tag, including those
+// of nested structs. visiting guards against recursive types.
+func (w *structWalker) fields(st *types.Struct, prefix string, visiting map[*types.Struct]bool) []model.TaggedField {
+	var result []model.TaggedField
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tags := reflect.StructTag(st.Tag(i))
+		tag, hasTag := tags.Lookup("fireconf")
+
+		if f.Name() == "_" {
+			if hasTag {
+				result = append(result, model.TaggedField{Tag: tag, Source: w.position(f.Pos())})
+			}
+			continue
+		}
+		if !f.Exported() && !f.Embedded() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tags.Get("firestore"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if hasTag {
+			path := name
+			if path == "" {
+				path = f.Name()
+			}
+			result = append(result, model.TaggedField{Path: prefix + path, Tag: tag, Source: w.position(f.Pos())})
+		}
+
+		ft := f.Type()
+		for {
+			ptr, ok := ft.(*types.Pointer)
+			if !ok {
+				break
+			}
+			ft = ptr.Elem()
+		}
+		if named, ok := ft.(*types.Named); ok && named.Obj().Pkg() != nil &&
+			named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time" {
+			continue
+		}
+		nestedStruct, ok := ft.Underlying().(*types.Struct)
+		if !ok || visiting[nestedStruct] {
+			continue
+		}
+
+		// Embedded structs without a name are promoted like in the
+		// Firestore client library; other structs become nested maps
+		nested := prefix
+		if !f.Embedded() || name != "" {
+			if name == "" {
+				name = f.Name()
+			}
+			nested = prefix + name + "."
+		}
+		visiting[nestedStruct] = true
+		result = append(result, w.fields(nestedStruct, nested, visiting)...)
+		delete(visiting, nestedStruct)
+	}
+	return result
+}
+
+// position returns "file:line" relative to the scan directory when possible
+func (w *structWalker) position(pos token.Pos) string {
+	return relativePosition(w.fset.Position(pos), w.dir)
+}
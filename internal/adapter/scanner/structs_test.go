@@ -0,0 +1,27 @@
+package scanner_test
+
+import (
+	"testing"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/scanner"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/gt"
+)
+
+func TestScanGoStructs(t *testing.T) {
+	structs, err := scanner.ScanGoStructs(".", "./testdata/models")
+	gt.NoError(t, err)
+
+	// Stats carries a tag and is reported as well, although it is only a
+	// nested part of a document
+	gt.Equal(t, len(structs), 2)
+	gt.Equal(t, structs[0].Name, "models.Stats")
+	gt.Equal(t, structs[1].Name, "models.Post")
+	gt.Equal(t, structs[1].Fields, []model.TaggedField{
+		{Path: "", Tag: "collection=posts", Source: "testdata/models/models.go:11"},
+		{Path: "tags", Tag: "index=byTag,0,contains", Source: "testdata/models/models.go:12"},
+		{Path: "stats.score", Tag: "index=byTag,1,desc", Source: "testdata/models/models.go:7"},
+		{Path: "expireAt", Tag: "ttl", Source: "testdata/models/models.go:14"},
+		{Path: "embedding", Tag: "vector=3", Source: "testdata/models/models.go:15"},
+	})
+}
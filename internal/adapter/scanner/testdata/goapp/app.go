@@ -0,0 +1,47 @@
+// Package goapp is a fixture for the Go query scanner tests.
+package goapp
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+)
+
+const ordersCollection = "orders"
+
+func listOrders(ctx context.Context, client *firestore.Client, customerID string) *firestore.DocumentIterator {
+	return client.Collection(ordersCollection).
+		Where("customerId", "==", customerID).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(20).
+		Documents(ctx)
+}
+
+func listLargeOrders(ctx context.Context, client *firestore.Client) *firestore.DocumentIterator {
+	return client.CollectionGroup("orders").
+		Where("status", "in", []string{"paid", "shipped"}).
+		Where("total", ">=", 1000).
+		Documents(ctx)
+}
+
+func listTagged(ctx context.Context, client *firestore.Client, userID string) *firestore.DocumentIterator {
+	return client.Collection("users").Doc(userID).Collection("posts").
+		WhereEntity(firestore.PropertyFilter{Path: "tags", Operator: "array-contains", Value: "go"}).
+		OrderByPath(firestore.FieldPath{"stats", "score"}, firestore.Asc).
+		Documents(ctx)
+}
+
+func similar(ctx context.Context, client *firestore.Client) *firestore.VectorQuery {
+	q := client.Collection("documents").
+		Where("category", "==", "news").
+		FindNearest("embedding", firestore.Vector32{0.1, 0.2, 0.3}, 10, firestore.DistanceMeasureCosine, nil)
+	return &q
+}
+
+func dynamic(ctx context.Context, client *firestore.Client, field string) *firestore.DocumentIterator {
+	return client.Collection("users").Where(field, "==", 1).OrderBy("name", firestore.Asc).Documents(ctx)
+}
+
+func noFilter(ctx context.Context, client *firestore.Client) *firestore.DocumentIterator {
+	return client.Collection("users").Limit(10).Documents(ctx)
+}
@@ -0,0 +1,19 @@
+/* Fixture for the JavaScript/TypeScript query scanner tests. */
+const { Filter, FieldPath, FieldValue } = require('firebase-admin/firestore');
+
+exports.recent = async (db, uid) => {
+  const snapshot = await db.collection('users').doc(uid).collection('events')
+    .where(Filter.and(Filter.where('type', '==', 'login'), Filter.where(new FieldPath('geo', 'country'), '==', 'JP')))
+    .orderBy('at', 'desc')
+    .get();
+  return snapshot.docs;
+};
+
+exports.similar = (db) =>
+  db.collection('documents')
+    .where('category', '==', 'news')
+    .findNearest('embedding', FieldValue.vector([0.1, 0.2, 0.3]), { limit: 10, distanceMeasure: 'COSINE' });
+
+exports.either = (db) => db.collectionGroup('tasks').where(Filter.or(Filter.where('a', '==', 1), Filter.where('b', '==', 2)));
+
+exports.doc = (db) => db.collection('users').doc('alice').get();
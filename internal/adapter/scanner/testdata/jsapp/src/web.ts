@@ -0,0 +1,37 @@
+// Fixture for the JavaScript/TypeScript query scanner tests.
+import { collection, collectionGroup, documentId, getDocs, orderBy, query, where, limit } from 'firebase/firestore';
+import type { Firestore } from 'firebase/firestore';
+
+const ORDERS = 'orders';
+const pattern = /['"]/g;
+
+export async function listOrders(db: Firestore, customerId: string) {
+  const q = query(
+    collection(db, ORDERS),
+    where('customerId', '==', customerId),
+    orderBy('createdAt', 'desc'),
+    limit(20),
+  );
+  return getDocs(q);
+}
+
+export function listPosts(db: Firestore, uid: string, tag: string) {
+  // collection path with a dynamic document id
+  return query(collection(db, `users/${uid}/posts`), where('tags', 'array-contains', tag), orderBy('score'));
+}
+
+const orderGroup = collectionGroup(db, 'orders')
+
+export function nested(db: Firestore) {
+  return query(query(orderGroup, where('status', 'in', ['paid'])), where('total', '>=', 100));
+}
+
+export function unknownField(db: Firestore, field: string) {
+  return query(collection(db, 'users'), where(field, '==', 1));
+}
+
+export function singleLookup(db: Firestore) {
+  return query(collection(db, 'users'), limit(1));
+}
+
+export const label = `${pattern.source} query(`;
@@ -0,0 +1,22 @@
+// Package models is a fixture for the struct tag scanner tests.
+package models
+
+import "time"
+
+type Stats struct {
+	Score int `firestore:"score" fireconf:"index=byTag,1,desc"`
+}
+
+type Post struct {
+	_         struct{}  `fireconf:"collection=posts"`
+	Tags      []string  `firestore:"tags" fireconf:"index=byTag,0,contains"`
+	Stats     Stats     `firestore:"stats"`
+	ExpireAt  time.Time `firestore:"expireAt" fireconf:"ttl"`
+	Embedding []float32 `firestore:"embedding,omitempty" fireconf:"vector=3"`
+	Body      string    `firestore:"body"`
+}
+
+// Untagged types are ignored
+type Author struct {
+	Name string
+}
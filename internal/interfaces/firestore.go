@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"io"
+	"time"
 )
 
 //go:generate task mock
@@ -18,8 +19,13 @@ type FirestoreClient interface {
 	// Index operations
 	ListIndexes(ctx context.Context, collectionID string) ([]FirestoreIndex, error)
 	GetIndex(ctx context.Context, indexName string) (*FirestoreIndex, error)
+	// CreateIndex returns the resource name of the new index. The error wraps
+	// model.ErrIndexAlreadyExists if an identical index exists.
 	CreateIndex(ctx context.Context, collectionID string, index FirestoreIndex) (string, error)
 	DeleteIndex(ctx context.Context, indexName string) (interface{}, error)
+	// GetIndexOperation returns the latest long-running operation building
+	// an index, or nil if there is none
+	GetIndexOperation(ctx context.Context, indexName string) (*FirestoreOperation, error)
 
 	// TTL operations
 	GetTTLPolicy(ctx context.Context, collectionID string, fieldName string) (*FirestoreTTL, error)
@@ -27,6 +33,18 @@ type FirestoreClient interface {
 	EnableTTLPolicy(ctx context.Context, collectionID string, fieldName string) (interface{}, error)
 	DisableTTLPolicy(ctx context.Context, collectionID string) (interface{}, error)
 
+	// Single-field index configuration
+	GetFieldIndexConfig(ctx context.Context, collectionID string, fieldName string) (*FirestoreFieldIndexConfig, error)
+	// UpdateFieldIndexConfig replaces the single-field index configuration of
+	// a field. A nil config reverts the field to the database defaults.
+	UpdateFieldIndexConfig(ctx context.Context, collectionID string, fieldName string, config *FirestoreFieldIndexConfig) (interface{}, error)
+
+	// Long-running operations
+	// ListOperations lists the index and field operations of the database,
+	// including finished ones
+	ListOperations(ctx context.Context) ([]FirestoreOperation, error)
+	CancelOperation(ctx context.Context, operationName string) error
+
 	// Wait for operation to complete
 	WaitForOperation(ctx context.Context, operation interface{}) error
 }
@@ -52,8 +70,67 @@ type FirestoreVectorConfig struct {
 	Dimension int
 }
 
+// FirestoreOperation represents a long-running Admin API operation
+type FirestoreOperation struct {
+	Name string
+	// Kind is "index" for index builds and "field" for single-field index
+	// and TTL changes
+	Kind string
+	// Target is the resource name of the index or field the operation
+	// works on
+	Target     string
+	Collection string
+	// State is INITIALIZING, PROCESSING, CANCELLING, FINALIZING, SUCCESSFUL,
+	// FAILED or CANCELLED
+	State string
+	Done  bool
+	// Progress of the operation; Estimated values are 0 if unknown
+	DocumentsCompleted int64
+	DocumentsEstimated int64
+	BytesCompleted     int64
+	BytesEstimated     int64
+	StartTime          time.Time
+	// EndTime is zero while the operation is in progress
+	EndTime time.Time
+}
+
 // FirestoreTTL represents a TTL policy
 type FirestoreTTL struct {
 	FieldPath string
-	State     string // ENABLED or DISABLED
+	State     string // CREATING, ACTIVE or NEEDS_REPAIR
+}
+
+// FirestoreFieldIndexConfig represents the single-field index configuration
+// of a field
+type FirestoreFieldIndexConfig struct {
+	// UsesAncestorConfig is true when the field inherits the database
+	// defaults instead of having its own configuration
+	UsesAncestorConfig bool
+	// Indexes are the single-field indexes of the field; empty with
+	// UsesAncestorConfig false means the field is exempt from indexing
+	Indexes []FirestoreFieldIndex
+}
+
+// FieldIndexStore records the single-field index configurations that fields
+// had before Sync exempted them from indexing for a TTL policy, so that the
+// same configuration is restored when the policy is removed or moved, even
+// by a later run. Implementations must be safe for concurrent use.
+type FieldIndexStore interface {
+	// PreviousFieldIndexConfig returns the configuration recorded for a
+	// field, or nil if none is recorded
+	PreviousFieldIndexConfig(collectionID, fieldName string) *FirestoreFieldIndexConfig
+	// RecordFieldIndexConfig records the configuration of a field before it
+	// is exempted
+	RecordFieldIndexConfig(collectionID, fieldName string, config *FirestoreFieldIndexConfig)
+	// ForgetFieldIndexConfig removes the record of a field
+	ForgetFieldIndexConfig(collectionID, fieldName string)
+	// RecordedFields returns the fields of a collection with a record
+	RecordedFields(collectionID string) []string
+}
+
+// FirestoreFieldIndex represents a single-field index
+type FirestoreFieldIndex struct {
+	QueryScope  string
+	Order       string
+	ArrayConfig string
 }
@@ -19,6 +19,9 @@ var _ interfaces.FirestoreClient = &FirestoreClientMock{}
 //
 //		// make and configure a mocked interfaces.FirestoreClient
 //		mockedFirestoreClient := &FirestoreClientMock{
+//			CancelOperationFunc: func(ctx context.Context, operationName string) error {
+//				panic("mock out the CancelOperation method")
+//			},
 //			CloseFunc: func() error {
 //				panic("mock out the Close method")
 //			},
@@ -43,9 +46,15 @@ var _ interfaces.FirestoreClient = &FirestoreClientMock{}
 //			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
 //				panic("mock out the FindTTLField method")
 //			},
+//			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+//				panic("mock out the GetFieldIndexConfig method")
+//			},
 //			GetIndexFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
 //				panic("mock out the GetIndex method")
 //			},
+//			GetIndexOperationFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreOperation, error) {
+//				panic("mock out the GetIndexOperation method")
+//			},
 //			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
 //				panic("mock out the GetTTLPolicy method")
 //			},
@@ -55,6 +64,12 @@ var _ interfaces.FirestoreClient = &FirestoreClientMock{}
 //			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
 //				panic("mock out the ListIndexes method")
 //			},
+//			ListOperationsFunc: func(ctx context.Context) ([]interfaces.FirestoreOperation, error) {
+//				panic("mock out the ListOperations method")
+//			},
+//			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+//				panic("mock out the UpdateFieldIndexConfig method")
+//			},
 //			WaitForOperationFunc: func(ctx context.Context, operation interface{}) error {
 //				panic("mock out the WaitForOperation method")
 //			},
@@ -65,6 +80,9 @@ var _ interfaces.FirestoreClient = &FirestoreClientMock{}
 //
 //	}
 type FirestoreClientMock struct {
+	// CancelOperationFunc mocks the CancelOperation method.
+	CancelOperationFunc func(ctx context.Context, operationName string) error
+
 	// CloseFunc mocks the Close method.
 	CloseFunc func() error
 
@@ -89,9 +107,15 @@ type FirestoreClientMock struct {
 	// FindTTLFieldFunc mocks the FindTTLField method.
 	FindTTLFieldFunc func(ctx context.Context, collectionID string) (string, error)
 
+	// GetFieldIndexConfigFunc mocks the GetFieldIndexConfig method.
+	GetFieldIndexConfigFunc func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error)
+
 	// GetIndexFunc mocks the GetIndex method.
 	GetIndexFunc func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error)
 
+	// GetIndexOperationFunc mocks the GetIndexOperation method.
+	GetIndexOperationFunc func(ctx context.Context, indexName string) (*interfaces.FirestoreOperation, error)
+
 	// GetTTLPolicyFunc mocks the GetTTLPolicy method.
 	GetTTLPolicyFunc func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error)
 
@@ -101,11 +125,24 @@ type FirestoreClientMock struct {
 	// ListIndexesFunc mocks the ListIndexes method.
 	ListIndexesFunc func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error)
 
+	// ListOperationsFunc mocks the ListOperations method.
+	ListOperationsFunc func(ctx context.Context) ([]interfaces.FirestoreOperation, error)
+
+	// UpdateFieldIndexConfigFunc mocks the UpdateFieldIndexConfig method.
+	UpdateFieldIndexConfigFunc func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error)
+
 	// WaitForOperationFunc mocks the WaitForOperation method.
 	WaitForOperationFunc func(ctx context.Context, operation interface{}) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// CancelOperation holds details about calls to the CancelOperation method.
+		CancelOperation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OperationName is the operationName argument value.
+			OperationName string
+		}
 		// Close holds details about calls to the Close method.
 		Close []struct {
 		}
@@ -162,6 +199,15 @@ type FirestoreClientMock struct {
 			// CollectionID is the collectionID argument value.
 			CollectionID string
 		}
+		// GetFieldIndexConfig holds details about calls to the GetFieldIndexConfig method.
+		GetFieldIndexConfig []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// FieldName is the fieldName argument value.
+			FieldName string
+		}
 		// GetIndex holds details about calls to the GetIndex method.
 		GetIndex []struct {
 			// Ctx is the ctx argument value.
@@ -169,6 +215,13 @@ type FirestoreClientMock struct {
 			// IndexName is the indexName argument value.
 			IndexName string
 		}
+		// GetIndexOperation holds details about calls to the GetIndexOperation method.
+		GetIndexOperation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// IndexName is the indexName argument value.
+			IndexName string
+		}
 		// GetTTLPolicy holds details about calls to the GetTTLPolicy method.
 		GetTTLPolicy []struct {
 			// Ctx is the ctx argument value.
@@ -190,6 +243,22 @@ type FirestoreClientMock struct {
 			// CollectionID is the collectionID argument value.
 			CollectionID string
 		}
+		// ListOperations holds details about calls to the ListOperations method.
+		ListOperations []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// UpdateFieldIndexConfig holds details about calls to the UpdateFieldIndexConfig method.
+		UpdateFieldIndexConfig []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// FieldName is the fieldName argument value.
+			FieldName string
+			// Config is the config argument value.
+			Config *interfaces.FirestoreFieldIndexConfig
+		}
 		// WaitForOperation holds details about calls to the WaitForOperation method.
 		WaitForOperation []struct {
 			// Ctx is the ctx argument value.
@@ -198,19 +267,60 @@ type FirestoreClientMock struct {
 			Operation interface{}
 		}
 	}
-	lockClose            sync.RWMutex
-	lockCollectionExists sync.RWMutex
-	lockCreateCollection sync.RWMutex
-	lockCreateIndex      sync.RWMutex
-	lockDeleteIndex      sync.RWMutex
-	lockDisableTTLPolicy sync.RWMutex
-	lockEnableTTLPolicy  sync.RWMutex
-	lockFindTTLField     sync.RWMutex
-	lockGetIndex         sync.RWMutex
-	lockGetTTLPolicy     sync.RWMutex
-	lockListCollections  sync.RWMutex
-	lockListIndexes      sync.RWMutex
-	lockWaitForOperation sync.RWMutex
+	lockCancelOperation        sync.RWMutex
+	lockClose                  sync.RWMutex
+	lockCollectionExists       sync.RWMutex
+	lockCreateCollection       sync.RWMutex
+	lockCreateIndex            sync.RWMutex
+	lockDeleteIndex            sync.RWMutex
+	lockDisableTTLPolicy       sync.RWMutex
+	lockEnableTTLPolicy        sync.RWMutex
+	lockFindTTLField           sync.RWMutex
+	lockGetFieldIndexConfig    sync.RWMutex
+	lockGetIndex               sync.RWMutex
+	lockGetIndexOperation      sync.RWMutex
+	lockGetTTLPolicy           sync.RWMutex
+	lockListCollections        sync.RWMutex
+	lockListIndexes            sync.RWMutex
+	lockListOperations         sync.RWMutex
+	lockUpdateFieldIndexConfig sync.RWMutex
+	lockWaitForOperation       sync.RWMutex
+}
+
+// CancelOperation calls CancelOperationFunc.
+func (mock *FirestoreClientMock) CancelOperation(ctx context.Context, operationName string) error {
+	if mock.CancelOperationFunc == nil {
+		panic("FirestoreClientMock.CancelOperationFunc: method is nil but FirestoreClient.CancelOperation was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		OperationName string
+	}{
+		Ctx:           ctx,
+		OperationName: operationName,
+	}
+	mock.lockCancelOperation.Lock()
+	mock.calls.CancelOperation = append(mock.calls.CancelOperation, callInfo)
+	mock.lockCancelOperation.Unlock()
+	return mock.CancelOperationFunc(ctx, operationName)
+}
+
+// CancelOperationCalls gets all the calls that were made to CancelOperation.
+// Check the length with:
+//
+//	len(mockedFirestoreClient.CancelOperationCalls())
+func (mock *FirestoreClientMock) CancelOperationCalls() []struct {
+	Ctx           context.Context
+	OperationName string
+} {
+	var calls []struct {
+		Ctx           context.Context
+		OperationName string
+	}
+	mock.lockCancelOperation.RLock()
+	calls = mock.calls.CancelOperation
+	mock.lockCancelOperation.RUnlock()
+	return calls
 }
 
 // Close calls CloseFunc.
@@ -500,6 +610,46 @@ func (mock *FirestoreClientMock) FindTTLFieldCalls() []struct {
 	return calls
 }
 
+// GetFieldIndexConfig calls GetFieldIndexConfigFunc.
+func (mock *FirestoreClientMock) GetFieldIndexConfig(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+	if mock.GetFieldIndexConfigFunc == nil {
+		panic("FirestoreClientMock.GetFieldIndexConfigFunc: method is nil but FirestoreClient.GetFieldIndexConfig was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		CollectionID string
+		FieldName    string
+	}{
+		Ctx:          ctx,
+		CollectionID: collectionID,
+		FieldName:    fieldName,
+	}
+	mock.lockGetFieldIndexConfig.Lock()
+	mock.calls.GetFieldIndexConfig = append(mock.calls.GetFieldIndexConfig, callInfo)
+	mock.lockGetFieldIndexConfig.Unlock()
+	return mock.GetFieldIndexConfigFunc(ctx, collectionID, fieldName)
+}
+
+// GetFieldIndexConfigCalls gets all the calls that were made to GetFieldIndexConfig.
+// Check the length with:
+//
+//	len(mockedFirestoreClient.GetFieldIndexConfigCalls())
+func (mock *FirestoreClientMock) GetFieldIndexConfigCalls() []struct {
+	Ctx          context.Context
+	CollectionID string
+	FieldName    string
+} {
+	var calls []struct {
+		Ctx          context.Context
+		CollectionID string
+		FieldName    string
+	}
+	mock.lockGetFieldIndexConfig.RLock()
+	calls = mock.calls.GetFieldIndexConfig
+	mock.lockGetFieldIndexConfig.RUnlock()
+	return calls
+}
+
 // GetIndex calls GetIndexFunc.
 func (mock *FirestoreClientMock) GetIndex(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
 	if mock.GetIndexFunc == nil {
@@ -536,6 +686,42 @@ func (mock *FirestoreClientMock) GetIndexCalls() []struct {
 	return calls
 }
 
+// GetIndexOperation calls GetIndexOperationFunc.
+func (mock *FirestoreClientMock) GetIndexOperation(ctx context.Context, indexName string) (*interfaces.FirestoreOperation, error) {
+	if mock.GetIndexOperationFunc == nil {
+		panic("FirestoreClientMock.GetIndexOperationFunc: method is nil but FirestoreClient.GetIndexOperation was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		IndexName string
+	}{
+		Ctx:       ctx,
+		IndexName: indexName,
+	}
+	mock.lockGetIndexOperation.Lock()
+	mock.calls.GetIndexOperation = append(mock.calls.GetIndexOperation, callInfo)
+	mock.lockGetIndexOperation.Unlock()
+	return mock.GetIndexOperationFunc(ctx, indexName)
+}
+
+// GetIndexOperationCalls gets all the calls that were made to GetIndexOperation.
+// Check the length with:
+//
+//	len(mockedFirestoreClient.GetIndexOperationCalls())
+func (mock *FirestoreClientMock) GetIndexOperationCalls() []struct {
+	Ctx       context.Context
+	IndexName string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		IndexName string
+	}
+	mock.lockGetIndexOperation.RLock()
+	calls = mock.calls.GetIndexOperation
+	mock.lockGetIndexOperation.RUnlock()
+	return calls
+}
+
 // GetTTLPolicy calls GetTTLPolicyFunc.
 func (mock *FirestoreClientMock) GetTTLPolicy(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
 	if mock.GetTTLPolicyFunc == nil {
@@ -644,6 +830,82 @@ func (mock *FirestoreClientMock) ListIndexesCalls() []struct {
 	return calls
 }
 
+// ListOperations calls ListOperationsFunc.
+func (mock *FirestoreClientMock) ListOperations(ctx context.Context) ([]interfaces.FirestoreOperation, error) {
+	if mock.ListOperationsFunc == nil {
+		panic("FirestoreClientMock.ListOperationsFunc: method is nil but FirestoreClient.ListOperations was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListOperations.Lock()
+	mock.calls.ListOperations = append(mock.calls.ListOperations, callInfo)
+	mock.lockListOperations.Unlock()
+	return mock.ListOperationsFunc(ctx)
+}
+
+// ListOperationsCalls gets all the calls that were made to ListOperations.
+// Check the length with:
+//
+//	len(mockedFirestoreClient.ListOperationsCalls())
+func (mock *FirestoreClientMock) ListOperationsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListOperations.RLock()
+	calls = mock.calls.ListOperations
+	mock.lockListOperations.RUnlock()
+	return calls
+}
+
+// UpdateFieldIndexConfig calls UpdateFieldIndexConfigFunc.
+func (mock *FirestoreClientMock) UpdateFieldIndexConfig(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+	if mock.UpdateFieldIndexConfigFunc == nil {
+		panic("FirestoreClientMock.UpdateFieldIndexConfigFunc: method is nil but FirestoreClient.UpdateFieldIndexConfig was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		CollectionID string
+		FieldName    string
+		Config       *interfaces.FirestoreFieldIndexConfig
+	}{
+		Ctx:          ctx,
+		CollectionID: collectionID,
+		FieldName:    fieldName,
+		Config:       config,
+	}
+	mock.lockUpdateFieldIndexConfig.Lock()
+	mock.calls.UpdateFieldIndexConfig = append(mock.calls.UpdateFieldIndexConfig, callInfo)
+	mock.lockUpdateFieldIndexConfig.Unlock()
+	return mock.UpdateFieldIndexConfigFunc(ctx, collectionID, fieldName, config)
+}
+
+// UpdateFieldIndexConfigCalls gets all the calls that were made to UpdateFieldIndexConfig.
+// Check the length with:
+//
+//	len(mockedFirestoreClient.UpdateFieldIndexConfigCalls())
+func (mock *FirestoreClientMock) UpdateFieldIndexConfigCalls() []struct {
+	Ctx          context.Context
+	CollectionID string
+	FieldName    string
+	Config       *interfaces.FirestoreFieldIndexConfig
+} {
+	var calls []struct {
+		Ctx          context.Context
+		CollectionID string
+		FieldName    string
+		Config       *interfaces.FirestoreFieldIndexConfig
+	}
+	mock.lockUpdateFieldIndexConfig.RLock()
+	calls = mock.calls.UpdateFieldIndexConfig
+	mock.lockUpdateFieldIndexConfig.RUnlock()
+	return calls
+}
+
 // WaitForOperation calls WaitForOperationFunc.
 func (mock *FirestoreClientMock) WaitForOperation(ctx context.Context, operation interface{}) error {
 	if mock.WaitForOperationFunc == nil {
@@ -0,0 +1,29 @@
+package model
+
+import "errors"
+
+// Errors classifying failures of Firestore, matched with errors.Is
+var (
+	// ErrPermissionDenied is returned when the caller lacks a permission or
+	// valid credentials
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrQuotaExceeded is returned when a quota or rate limit of the Admin
+	// API is exhausted
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrAlreadyExists is returned when creating a resource, e.g. an index
+	// or a collection, that already exists
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrIndexAlreadyExists is returned when creating an index that already
+	// exists, together with ErrAlreadyExists
+	ErrIndexAlreadyExists = errors.New("index already exists")
+	// ErrDatabaseNotFound is returned when the database does not exist. It is
+	// detected from the error details, or from the message if Firestore
+	// attaches none
+	ErrDatabaseNotFound = errors.New("database not found")
+	// ErrIndexBuildFailed is returned when an index enters the ERROR or
+	// NEEDS_REPAIR state
+	ErrIndexBuildFailed = errors.New("index build failed")
+	// ErrWaitTimeout is returned when an index, index deletion or TTL policy
+	// change is not done within the configured timeout or deadline
+	ErrWaitTimeout = errors.New("wait timed out")
+)
@@ -0,0 +1,98 @@
+package model
+
+import "time"
+
+// Event is a progress event emitted while synchronizing a configuration
+type Event interface {
+	event()
+}
+
+// CollectionStarted is emitted when processing of a collection begins
+type CollectionStarted struct {
+	Collection string
+}
+
+// CollectionFinished is emitted when processing of a collection ends. Err is
+// nil on success.
+type CollectionFinished struct {
+	Collection string
+	Err        error
+}
+
+// IndexCreateSubmitted is emitted when an index creation was accepted. Name
+// is empty if Firestore did not report the operation name.
+type IndexCreateSubmitted struct {
+	Collection string
+	Index      Index
+	Name       string
+}
+
+// IndexDeleteSubmitted is emitted when an index deletion was accepted
+type IndexDeleteSubmitted struct {
+	Collection string
+	Index      Index
+	Name       string
+}
+
+// IndexStateChanged is emitted when polling observes a new index state,
+// including the first observed state
+type IndexStateChanged struct {
+	Collection string
+	Name       string
+	Previous   string
+	State      string
+}
+
+// WaitKind tells what a WaitingTick is waiting for
+type WaitKind string
+
+const (
+	WaitIndexReady    WaitKind = "index_ready"
+	WaitIndexDeletion WaitKind = "index_deletion"
+	WaitTTLActive     WaitKind = "ttl_active"
+	WaitTTLCleared    WaitKind = "ttl_cleared"
+)
+
+// WaitingTick is emitted periodically while waiting for an operation.
+// Target is the index name or the TTL field.
+type WaitingTick struct {
+	Collection string
+	Kind       WaitKind
+	Target     string
+	Elapsed    time.Duration
+	// Progress is the build progress of an index, nil if unknown
+	Progress *OperationProgress
+}
+
+// OperationProgress is the progress of a long-running operation. Estimated
+// values are 0 if unknown.
+type OperationProgress struct {
+	State              string
+	DocumentsCompleted int64
+	DocumentsEstimated int64
+	BytesCompleted     int64
+	BytesEstimated     int64
+}
+
+// TTLChanged is emitted when a TTL policy change was submitted. Action is
+// "enable" or "disable".
+type TTLChanged struct {
+	Collection string
+	Field      string
+	Action     string
+}
+
+// ErrorOccurred is emitted when processing of a collection fails
+type ErrorOccurred struct {
+	Collection string
+	Err        error
+}
+
+func (CollectionStarted) event()    {}
+func (CollectionFinished) event()   {}
+func (IndexCreateSubmitted) event() {}
+func (IndexDeleteSubmitted) event() {}
+func (IndexStateChanged) event()    {}
+func (WaitingTick) event()          {}
+func (TTLChanged) event()           {}
+func (ErrorOccurred) event()        {}
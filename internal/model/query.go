@@ -0,0 +1,125 @@
+package model
+
+import "fmt"
+
+// Query represents the shape of a Firestore query: which fields are filtered,
+// how they are filtered and how the result is ordered. Values are irrelevant
+// for index selection and are therefore not part of the model.
+type Query struct {
+	Name          string
+	Collection    string
+	QueryScope    string // COLLECTION or COLLECTION_GROUP
+	Equality      []string
+	Range         []string
+	ArrayContains string
+	OrderBy       []QueryOrder
+	FindNearest   *FindNearest
+}
+
+// QueryOrder represents an orderBy clause of a query
+type QueryOrder struct {
+	Field string
+	Order string // ASCENDING or DESCENDING
+}
+
+// FindNearest represents a vector search clause of a query.
+// Dimension 0 means the dimension is unknown (e.g. when the query shape was
+// extracted from source code).
+type FindNearest struct {
+	Field     string
+	Dimension int
+}
+
+// AddFilter classifies a filter operator as used by the Firestore SDKs and
+// records the field in the matching clause of the query.
+func (q *Query) AddFilter(field, op string) error {
+	switch op {
+	case "==", "in":
+		q.Equality = append(q.Equality, field)
+	case "<", "<=", ">", ">=", "!=", "not-in":
+		q.Range = append(q.Range, field)
+	case "array-contains", "array-contains-any":
+		if q.ArrayContains != "" && q.ArrayContains != field {
+			return fmt.Errorf("query can have at most one array-contains filter: %s and %s", q.ArrayContains, field)
+		}
+		q.ArrayContains = field
+	default:
+		return fmt.Errorf("unsupported filter operator: %s", op)
+	}
+	return nil
+}
+
+// GetQueryScope returns normalized query scope
+func (q *Query) GetQueryScope() string {
+	if q.QueryScope == "" {
+		return "COLLECTION"
+	}
+	return q.QueryScope
+}
+
+// Validate validates the query description
+func (q *Query) Validate() error {
+	if q.Collection == "" {
+		return fmt.Errorf("query collection is required")
+	}
+
+	if q.QueryScope != "" && q.QueryScope != "COLLECTION" && q.QueryScope != "COLLECTION_GROUP" {
+		return fmt.Errorf("invalid queryScope: %s", q.QueryScope)
+	}
+
+	for _, field := range q.Equality {
+		if field == "" {
+			return fmt.Errorf("equality filter field is required")
+		}
+	}
+	for _, field := range q.Range {
+		if field == "" {
+			return fmt.Errorf("range filter field is required")
+		}
+	}
+
+	for i := range q.OrderBy {
+		order := &q.OrderBy[i]
+		if order.Field == "" {
+			return fmt.Errorf("orderBy field is required")
+		}
+		if order.Order == "" {
+			order.Order = "ASCENDING"
+		}
+		if order.Order != "ASCENDING" && order.Order != "DESCENDING" {
+			return fmt.Errorf("invalid order for field %s: %s", order.Field, order.Order)
+		}
+	}
+
+	if q.FindNearest != nil {
+		if q.FindNearest.Field == "" {
+			return fmt.Errorf("findNearest field is required")
+		}
+		if q.FindNearest.Dimension < 0 {
+			return fmt.Errorf("findNearest dimension must not be negative for field %s", q.FindNearest.Field)
+		}
+	}
+
+	return nil
+}
+
+// SourceQuery is a query shape found in application source code
+type SourceQuery struct {
+	Query    Query
+	Position string // file:line of the query expression
+	// Unresolved explains why the query shape could not be determined
+	// completely, e.g. because of a non-constant field path. Empty if the
+	// query was fully resolved.
+	Unresolved string
+}
+
+// QueryUsage is an executed query read from a query log. Either Index, the
+// index that query explain reported as used, or Query, the shape of the
+// executed query, is set.
+type QueryUsage struct {
+	// Collection is empty if the log does not record it
+	Collection string
+	Index      *Index
+	Query      *Query
+	Line       int
+}
@@ -0,0 +1,21 @@
+package model
+
+// TaggedStruct is a Go struct type annotated with fireconf struct tags
+type TaggedStruct struct {
+	// Name is the type name used in error messages
+	Name   string
+	Fields []TaggedField
+}
+
+// TaggedField is a field of a TaggedStruct carrying a fireconf tag. Fields
+// of nested structs are flattened into dotted paths.
+type TaggedField struct {
+	// Path is the Firestore field path, empty for marker fields such as
+	// `_ struct{} `fireconf:"collection=orders"``
+	Path string
+	// Tag is the value of the fireconf struct tag
+	Tag string
+	// Source locates the field in error messages, e.g. "Order.CreatedAt" or
+	// "model/order.go:12"
+	Source string
+}
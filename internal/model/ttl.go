@@ -5,6 +5,15 @@ import "fmt"
 // TTL represents TTL configuration
 type TTL struct {
 	Field string `yaml:"field"`
+	// IndexExemption exempts the TTL field from single-field indexing; nil
+	// means true
+	IndexExemption *bool `yaml:"indexExemption,omitempty"`
+}
+
+// ExemptsIndex reports whether the TTL field is exempt from single-field
+// indexing
+func (t *TTL) ExemptsIndex() bool {
+	return t.IndexExemption == nil || *t.IndexExemption
 }
 
 // Validate validates the TTL configuration
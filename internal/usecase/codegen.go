@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"bytes"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// commonInitialisms are words written in upper case in Go identifiers
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+}
+
+// goFile is the data of the Go template
+type goFile struct {
+	Package     string
+	Collections []goCollection
+}
+
+type goCollection struct {
+	Name    string
+	Ident   string
+	Fields  []goField
+	Helpers []goHelper
+}
+
+type goField struct {
+	Path  string
+	Ident string
+}
+
+// goHelper is a function building a query served by one index
+type goHelper struct {
+	Name  string
+	Index string
+	// Filters are the parameters and clauses of the query, in index order
+	Filters []goFilter
+	// OrderBy is the ordering on the last field, if any
+	OrderBy *goOrder
+	// Vector is the vector search on the last field, if any
+	Vector string
+	// Orders is the full ordering of the index for OrderBy helpers, empty if
+	// the index contains array or vector fields
+	Orders []goOrder
+}
+
+type goFilter struct {
+	Param string
+	Field string
+	Op    string
+}
+
+type goOrder struct {
+	Field     string
+	Direction string
+}
+
+var goTemplate = template.Must(template.New("go").Parse(`// Code generated by fireconf codegen. DO NOT EDIT.
+
+// Package {{.Package}} provides the collection names and field paths of the
+// Firestore configuration, and query helpers for each composite index.
+package {{.Package}}
+
+import "cloud.google.com/go/firestore"
+
+// Collection names
+const (
+{{- range .Collections}}
+	Collection{{.Ident}} = {{printf "%q" .Name}}
+{{- end}}
+)
+{{range $c := .Collections}}
+{{- if .Fields}}
+// Field paths of the {{.Name}} collection
+const (
+{{- range .Fields}}
+	{{$c.Ident}}{{.Ident}} = {{printf "%q" .Path}}
+{{- end}}
+)
+{{end}}
+{{- range .Helpers}}
+// {{.Name}} applies to q the clauses served by the {{$c.Name}} index
+// {{.Index}}.
+func {{.Name}}(q firestore.Query{{range .Filters}}, {{.Param}} any{{end}}{{if .Vector}}, vector any, limit int, measure firestore.DistanceMeasure{{end}}) firestore.{{if .Vector}}VectorQuery{{else}}Query{{end}} {
+	return q
+{{- range .Filters}}.
+		Where({{$c.Ident}}{{.Field}}, {{printf "%q" .Op}}, {{.Param}})
+{{- end}}
+{{- with .OrderBy}}.
+		OrderBy({{$c.Ident}}{{.Field}}, firestore.{{.Direction}})
+{{- end}}
+{{- if .Vector}}.
+		FindNearest({{$c.Ident}}{{.Vector}}, vector, limit, measure, nil)
+{{- end}}
+}
+{{if .Orders}}
+// {{.Name}}Order orders q like the {{$c.Name}} index {{.Index}}.
+func {{.Name}}Order(q firestore.Query) firestore.Query {
+	return q
+{{- range .Orders}}.
+		OrderBy({{$c.Ident}}{{.Field}}, firestore.{{.Direction}})
+{{- end}}
+}
+{{end}}
+{{- end}}
+{{- end}}`))
+
+// GenerateGo returns the source of a Go package with constants for the
+// collection names and field paths of the configuration, and a helper per
+// composite index. The helper filters by equality on the leading fields
+// (array-contains for array fields) and orders by the last field, or runs a
+// vector search if the last field is a vector field.
+func GenerateGo(config *model.Config, pkg string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, goerr.New("invalid package name", goerr.V("package", pkg))
+	}
+
+	data := goFile{Package: pkg}
+	collectionIdents := make(map[string]string)
+
+	for _, collection := range config.Collections {
+		c := goCollection{Name: collection.Name, Ident: goIdentifier(collection.Name)}
+		if other, ok := collectionIdents[c.Ident]; ok {
+			return nil, goerr.New("collections have the same Go name",
+				goerr.V("collections", []string{other, c.Name}), goerr.V("name", c.Ident))
+		}
+		collectionIdents[c.Ident] = c.Name
+
+		fieldIdents := make(map[string]string)
+		addField := func(path string) (string, error) {
+			ident := fieldIdentifier(path)
+			if other, ok := fieldIdents[ident]; ok {
+				if other != path {
+					return "", goerr.New("field paths have the same Go name",
+						goerr.V("collection", collection.Name), goerr.V("paths", []string{other, path}), goerr.V("name", ident))
+				}
+				return ident, nil
+			}
+			fieldIdents[ident] = path
+			c.Fields = append(c.Fields, goField{Path: path, Ident: ident})
+			return ident, nil
+		}
+
+		helperNames := make(map[string]bool)
+		for _, index := range collection.Indexes {
+			helper := goHelper{Index: FormatIndex(index)}
+
+			nameParts := []string{c.Ident, "By"}
+			hasArrayOrVector := false
+			for i, field := range index.Fields {
+				ident, err := addField(field.Name)
+				if err != nil {
+					return nil, err
+				}
+				last := i == len(index.Fields)-1
+				direction := "Asc"
+				if field.Order == "DESCENDING" {
+					direction = "Desc"
+				}
+
+				switch {
+				case field.VectorConfig != nil:
+					hasArrayOrVector = true
+					helper.Vector = ident
+					nameParts = append(nameParts, ident, "Vector")
+				case field.ArrayConfig != "":
+					hasArrayOrVector = true
+					helper.Filters = append(helper.Filters, goFilter{Param: paramName(ident), Field: ident, Op: "array-contains"})
+					nameParts = append(nameParts, ident, "Contains")
+				case last:
+					helper.OrderBy = &goOrder{Field: ident, Direction: direction}
+					nameParts = append(nameParts, ident)
+					if direction == "Desc" {
+						nameParts = append(nameParts, "Desc")
+					}
+				default:
+					helper.Filters = append(helper.Filters, goFilter{Param: paramName(ident), Field: ident, Op: "=="})
+					nameParts = append(nameParts, ident)
+				}
+				helper.Orders = append(helper.Orders, goOrder{Field: ident, Direction: direction})
+			}
+			if hasArrayOrVector {
+				helper.Orders = nil
+			}
+			if index.QueryScope == "COLLECTION_GROUP" {
+				nameParts = append(nameParts, "Group")
+			}
+
+			name := strings.Join(nameParts, "")
+			for n := 2; helperNames[name]; n++ {
+				name = strings.Join(nameParts, "") + strconv.Itoa(n)
+			}
+			helperNames[name] = true
+			helper.Name = name
+			c.Helpers = append(c.Helpers, helper)
+		}
+
+		if collection.TTL != nil {
+			if _, err := addField(collection.TTL.Field); err != nil {
+				return nil, err
+			}
+		}
+
+		data.Collections = append(data.Collections, c)
+	}
+
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, data); err != nil {
+		return nil, goerr.Wrap(err, "failed to execute template")
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to format generated code")
+	}
+	return src, nil
+}
+
+// goIdentifier converts a collection name or field path into an exported Go
+// identifier, e.g. "customer_id" and "customerId" into CustomerID and
+// "stats.score" into StatsScore
+func goIdentifier(name string) string {
+	var sb strings.Builder
+	for _, word := range splitWords(name) {
+		upper := strings.ToUpper(word)
+		if commonInitialisms[upper] {
+			sb.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+
+	ident := sb.String()
+	if ident == "" || !unicode.IsLetter([]rune(ident)[0]) {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+// fieldIdentifier returns the Go name of a field path
+func fieldIdentifier(path string) string {
+	if path == "__name__" {
+		return "DocumentID"
+	}
+	return goIdentifier(path)
+}
+
+// splitWords splits a name at non-alphanumeric characters and at lower to
+// upper case transitions
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// paramName returns an unexported parameter name for a field identifier
+func paramName(ident string) string {
+	runes := []rune(ident)
+	// Lower the leading initialism as a whole: IDToken -> idToken, URL -> url
+	n := 1
+	for n < len(runes) && unicode.IsUpper(runes[n]) && (n+1 == len(runes) || unicode.IsUpper(runes[n+1])) {
+		n++
+	}
+	name := strings.ToLower(string(runes[:n])) + string(runes[n:])
+	if token.IsKeyword(name) || name == "q" || name == "vector" || name == "limit" || name == "measure" {
+		name += "Value"
+	}
+	return name
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/m-mizutani/fireconf/internal/interfaces"
@@ -40,6 +41,92 @@ func DiffIndexes(desired []model.Index, existing []interfaces.FirestoreIndex) (t
 	return toCreate, toDelete
 }
 
+// IndexReplacement pairs an existing index with the desired index that
+// replaces it
+type IndexReplacement struct {
+	Old interfaces.FirestoreIndex
+	New interfaces.FirestoreIndex
+}
+
+// PairReplacements finds the indexes to create that replace an index to
+// delete: both cover the same set of fields but differ in field order,
+// direction, array or vector configuration or query scope. It returns the
+// pairs and the creations and deletions that are not part of any pair.
+func PairReplacements(toCreate, toDelete []interfaces.FirestoreIndex) (replacements []IndexReplacement, creates, deletes []interfaces.FirestoreIndex) {
+	paired := make([]bool, len(toCreate))
+
+	for _, old := range toDelete {
+		found := -1
+		for i, idx := range toCreate {
+			if !paired[i] && fieldSetKey(idx) == fieldSetKey(old) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			deletes = append(deletes, old)
+			continue
+		}
+		paired[found] = true
+		replacements = append(replacements, IndexReplacement{Old: old, New: toCreate[found]})
+	}
+
+	for i, idx := range toCreate {
+		if !paired[i] {
+			creates = append(creates, idx)
+		}
+	}
+
+	return replacements, creates, deletes
+}
+
+// fieldSetKey returns the sorted field paths of an index, ignoring __name__
+func fieldSetKey(idx interfaces.FirestoreIndex) string {
+	paths := make([]string, 0, len(idx.Fields))
+	for _, field := range idx.Fields {
+		if field.FieldPath != "__name__" {
+			paths = append(paths, field.FieldPath)
+		}
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, "|")
+}
+
+// IndexRepair pairs an unhealthy existing index with the desired index to
+// recreate in its place
+type IndexRepair struct {
+	Existing interfaces.FirestoreIndex
+	Desired  interfaces.FirestoreIndex
+}
+
+// FindUnhealthyIndexes returns the existing indexes in ERROR or NEEDS_REPAIR
+// state that match a desired index. DiffIndexes treats them as satisfying
+// the configuration, so they are only fixed by recreating them.
+func FindUnhealthyIndexes(desired []model.Index, existing []interfaces.FirestoreIndex) []IndexRepair {
+	desiredMap := make(map[string]interfaces.FirestoreIndex)
+	for _, idx := range desired {
+		converted := ConvertModelToFirestoreIndex(idx)
+		desiredMap[getIndexKey(converted)] = converted
+	}
+
+	var result []IndexRepair
+	for _, idx := range existing {
+		if !IsUnhealthyIndexState(idx.State) {
+			continue
+		}
+		if d, found := desiredMap[getIndexKey(idx)]; found {
+			result = append(result, IndexRepair{Existing: idx, Desired: d})
+		}
+	}
+	return result
+}
+
+// IsUnhealthyIndexState reports whether an index in the state can not serve
+// queries without being recreated
+func IsUnhealthyIndexState(state string) bool {
+	return state == "ERROR" || state == "NEEDS_REPAIR"
+}
+
 // DiffTTL compares desired and existing TTL configuration
 func DiffTTL(desired *model.TTL, existing *interfaces.FirestoreTTL) (needsUpdate bool, action string) {
 	// No TTL desired
@@ -171,3 +258,17 @@ func convertFirestoreToModelIndex(idx interfaces.FirestoreIndex) model.Index {
 
 	return modelIndex
 }
+
+// SameIndex reports whether two indexes are identical using getIndexKey
+// semantics: __name__ is ignored and vector indexes are normalized to
+// COLLECTION scope, while field order is significant.
+func SameIndex(a, b model.Index) bool {
+	return getIndexKey(ConvertModelToFirestoreIndex(a)) == getIndexKey(ConvertModelToFirestoreIndex(b))
+}
+
+// NormalizeFirestoreIndex converts an index reported by Firestore into the
+// domain model in the shape used for configuration files: __name__ fields are
+// dropped and vector fields are moved last, as done on import.
+func NormalizeFirestoreIndex(idx interfaces.FirestoreIndex) model.Index {
+	return adjustFieldOrder(convertFirestoreToModelIndex(idx))
+}
@@ -407,3 +407,87 @@ func TestConvertFirestoreToModelIndex(t *testing.T) {
 		gt.Equal(t, modelIdx.Fields[1].Order, "ASCENDING")
 	})
 }
+
+func TestPairReplacements(t *testing.T) {
+	index := func(name string, paths ...string) interfaces.FirestoreIndex {
+		idx := interfaces.FirestoreIndex{Name: name, QueryScope: "COLLECTION"}
+		for _, p := range paths {
+			idx.Fields = append(idx.Fields, interfaces.FirestoreIndexField{FieldPath: p, Order: "ASCENDING"})
+		}
+		return idx
+	}
+
+	t.Run("Pair indexes on the same fields", func(t *testing.T) {
+		toCreate := []interfaces.FirestoreIndex{
+			index("", "email", "createdAt"),
+			index("", "status", "updatedAt"),
+		}
+		toDelete := []interfaces.FirestoreIndex{
+			index("idx1", "createdAt", "email", "__name__"),
+			index("idx2", "owner", "updatedAt"),
+		}
+
+		replacements, creates, deletes := usecase.PairReplacements(toCreate, toDelete)
+		gt.Equal(t, len(replacements), 1)
+		gt.Equal(t, replacements[0].Old.Name, "idx1")
+		gt.Equal(t, replacements[0].New.Fields[0].FieldPath, "email")
+		gt.Equal(t, len(creates), 1)
+		gt.Equal(t, creates[0].Fields[0].FieldPath, "status")
+		gt.Equal(t, len(deletes), 1)
+		gt.Equal(t, deletes[0].Name, "idx2")
+	})
+
+	t.Run("Each index is paired at most once", func(t *testing.T) {
+		toCreate := []interfaces.FirestoreIndex{index("", "a", "b")}
+		toDelete := []interfaces.FirestoreIndex{index("idx1", "b", "a"), index("idx2", "b", "a")}
+
+		replacements, creates, deletes := usecase.PairReplacements(toCreate, toDelete)
+		gt.Equal(t, len(replacements), 1)
+		gt.Equal(t, len(creates), 0)
+		gt.Equal(t, len(deletes), 1)
+		gt.Equal(t, deletes[0].Name, "idx2")
+	})
+}
+
+func TestFindUnhealthyIndexes(t *testing.T) {
+	desired := []model.Index{
+		{
+			Fields: []model.IndexField{
+				{Name: "email", Order: "ASCENDING"},
+				{Name: "createdAt", Order: "DESCENDING"},
+			},
+			QueryScope: "COLLECTION",
+		},
+	}
+	index := func(name, state string, paths ...string) interfaces.FirestoreIndex {
+		idx := interfaces.FirestoreIndex{Name: name, State: state, QueryScope: "COLLECTION"}
+		for _, p := range paths {
+			order := "ASCENDING"
+			if p == "createdAt" {
+				order = "DESCENDING"
+			}
+			idx.Fields = append(idx.Fields, interfaces.FirestoreIndexField{FieldPath: p, Order: order})
+		}
+		return idx
+	}
+
+	t.Run("Find desired index in ERROR state", func(t *testing.T) {
+		existing := []interfaces.FirestoreIndex{
+			index("idx1", "ERROR", "email", "createdAt", "__name__"),
+		}
+		repairs := usecase.FindUnhealthyIndexes(desired, existing)
+		gt.Equal(t, len(repairs), 1)
+		gt.Equal(t, repairs[0].Existing.Name, "idx1")
+		gt.Equal(t, repairs[0].Desired.Name, "")
+		gt.Equal(t, len(repairs[0].Desired.Fields), 2)
+	})
+
+	t.Run("Ignore healthy and undesired indexes", func(t *testing.T) {
+		existing := []interfaces.FirestoreIndex{
+			index("idx1", "READY", "email", "createdAt"),
+			index("idx2", "NEEDS_REPAIR", "status", "createdAt"),
+			index("idx3", "CREATING", "email", "createdAt"),
+		}
+		gt.Equal(t, len(usecase.FindUnhealthyIndexes(desired, existing)), 0)
+	})
+}
@@ -179,9 +179,24 @@ func (i *Import) importTTL(ctx context.Context, collectionName string) (*model.T
 			slog.String("collection", collectionName),
 			slog.String("field", ttlField))
 
-		return &model.TTL{
+		result := &model.TTL{
 			Field: ttlField,
-		}, nil
+		}
+
+		// Show a TTL field that is not exempt from indexing in the
+		// configuration, since the exemption is the default
+		indexConfig, err := i.client.GetFieldIndexConfig(ctx, collectionName, ttlField)
+		if err != nil {
+			i.logger.Debug("Failed to get field index config",
+				slog.String("collection", collectionName),
+				slog.String("field", ttlField),
+				slog.String("error", err.Error()))
+		} else if !isIndexExemption(indexConfig) {
+			exempt := false
+			result.IndexExemption = &exempt
+		}
+
+		return result, nil
 	}
 
 	// TTL field exists but not active
@@ -44,6 +44,9 @@ func TestImport_Execute(t *testing.T) {
 					State:     "ACTIVE",
 				}, nil
 			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{}, nil // exempt
+			},
 		}
 
 		imp := usecase.NewImport(mockClient, logger)
@@ -55,6 +58,7 @@ func TestImport_Execute(t *testing.T) {
 		gt.Equal(t, len(config.Collections[0].Indexes), 1)
 		gt.NotEqual(t, config.Collections[0].TTL, nil)
 		gt.Equal(t, config.Collections[0].TTL.Field, "expireAt")
+		gt.Nil(t, config.Collections[0].TTL.IndexExemption)
 	})
 
 	t.Run("Normal: import multiple collections", func(t *testing.T) {
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// Operations lists and cancels the long-running index and field operations
+// of a database
+type Operations struct {
+	client interfaces.FirestoreClient
+	logger *slog.Logger
+}
+
+// NewOperations creates a new Operations use case
+func NewOperations(client interfaces.FirestoreClient, logger *slog.Logger) *Operations {
+	return &Operations{
+		client: client,
+		logger: logger,
+	}
+}
+
+// List returns the index and field operations, in-progress operations first
+// and newest first within each group. Finished operations are included only
+// if all is true.
+func (o *Operations) List(ctx context.Context, all bool) ([]interfaces.FirestoreOperation, error) {
+	operations, err := o.client.ListOperations(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list operations")
+	}
+
+	result := make([]interfaces.FirestoreOperation, 0, len(operations))
+	for _, op := range operations {
+		if op.Done && !all {
+			continue
+		}
+		result = append(result, op)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Done != result[j].Done {
+			return !result[i].Done
+		}
+		return result[i].StartTime.After(result[j].StartTime)
+	})
+
+	return result, nil
+}
+
+// Cancel requests cancellation of an in-progress index or field operation.
+// id is the operation ID as shown by List or its full resource name.
+func (o *Operations) Cancel(ctx context.Context, id string) (*interfaces.FirestoreOperation, error) {
+	operations, err := o.client.ListOperations(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list operations")
+	}
+
+	var target *interfaces.FirestoreOperation
+	for i, op := range operations {
+		if op.Name == id || OperationID(op.Name) == id {
+			target = &operations[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, goerr.New("index or field operation not found", goerr.V("id", id))
+	}
+	if target.Done {
+		return nil, goerr.New("operation already finished",
+			goerr.V("id", id),
+			goerr.V("state", target.State))
+	}
+
+	o.logger.Info("Cancelling operation",
+		slog.String("operation", target.Name),
+		slog.String("kind", target.Kind),
+		slog.String("target", target.Target))
+	if err := o.client.CancelOperation(ctx, target.Name); err != nil {
+		return nil, goerr.Wrap(err, "failed to cancel operation", goerr.V("operation", target.Name))
+	}
+
+	return target, nil
+}
+
+// OperationID returns the short ID of an operation from its resource name,
+// e.g. "projects/p/databases/d/operations/ABC" becomes "ABC"
+func OperationID(name string) string {
+	if !strings.Contains(name, "/operations/") {
+		return name
+	}
+	return path.Base(name)
+}
@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/fireconf/internal/interfaces/mock"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/gt"
+)
+
+func TestOperations(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.Default()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	const prefix = "projects/p/databases/(default)/operations/"
+	listOperations := func(ctx context.Context) ([]interfaces.FirestoreOperation, error) {
+		return []interfaces.FirestoreOperation{
+			{Name: prefix + "old", Kind: "index", State: "SUCCESSFUL", Done: true, StartTime: base},
+			{Name: prefix + "building", Kind: "index", State: "PROCESSING", StartTime: base.Add(time.Hour)},
+			{Name: prefix + "ttl", Kind: "field", State: "PROCESSING", StartTime: base.Add(2 * time.Hour)},
+		}, nil
+	}
+
+	names := func(ops []interfaces.FirestoreOperation) []string {
+		var out []string
+		for _, op := range ops {
+			out = append(out, usecase.OperationID(op.Name))
+		}
+		return out
+	}
+
+	t.Run("Normal: list in-progress operations newest first", func(t *testing.T) {
+		client := &mock.FirestoreClientMock{ListOperationsFunc: listOperations}
+		ops := gt.R1(usecase.NewOperations(client, logger).List(ctx, false)).NoError(t)
+		gt.Equal(t, names(ops), []string{"ttl", "building"})
+	})
+
+	t.Run("Normal: list all operations with finished ones last", func(t *testing.T) {
+		client := &mock.FirestoreClientMock{ListOperationsFunc: listOperations}
+		ops := gt.R1(usecase.NewOperations(client, logger).List(ctx, true)).NoError(t)
+		gt.Equal(t, names(ops), []string{"ttl", "building", "old"})
+	})
+
+	t.Run("Normal: cancel by ID", func(t *testing.T) {
+		client := &mock.FirestoreClientMock{
+			ListOperationsFunc: listOperations,
+			CancelOperationFunc: func(ctx context.Context, operationName string) error {
+				return nil
+			},
+		}
+		op := gt.R1(usecase.NewOperations(client, logger).Cancel(ctx, "building")).NoError(t)
+		gt.Equal(t, op.Name, prefix+"building")
+		gt.Equal(t, len(client.CancelOperationCalls()), 1)
+		gt.Equal(t, client.CancelOperationCalls()[0].OperationName, prefix+"building")
+	})
+
+	t.Run("Normal: cancel by resource name", func(t *testing.T) {
+		client := &mock.FirestoreClientMock{
+			ListOperationsFunc: listOperations,
+			CancelOperationFunc: func(ctx context.Context, operationName string) error {
+				return nil
+			},
+		}
+		gt.R1(usecase.NewOperations(client, logger).Cancel(ctx, prefix+"ttl")).NoError(t)
+		gt.Equal(t, client.CancelOperationCalls()[0].OperationName, prefix+"ttl")
+	})
+
+	t.Run("Error: cancel unknown operation", func(t *testing.T) {
+		client := &mock.FirestoreClientMock{
+			ListOperationsFunc: listOperations,
+			CancelOperationFunc: func(ctx context.Context, operationName string) error {
+				return nil
+			},
+		}
+		_, err := usecase.NewOperations(client, logger).Cancel(ctx, "unknown")
+		gt.Error(t, err).Contains("not found")
+		gt.Equal(t, len(client.CancelOperationCalls()), 0)
+	})
+
+	t.Run("Error: cancel finished operation", func(t *testing.T) {
+		client := &mock.FirestoreClientMock{
+			ListOperationsFunc: listOperations,
+			CancelOperationFunc: func(ctx context.Context, operationName string) error {
+				return nil
+			},
+		}
+		_, err := usecase.NewOperations(client, logger).Cancel(ctx, "old")
+		gt.Error(t, err).Contains("already finished")
+		gt.Equal(t, len(client.CancelOperationCalls()), 0)
+	})
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+)
+
+// FormatProgress formats the progress of an operation, e.g. "43% (1.2M/2.8M
+// docs)". Documents are preferred over bytes; the state is returned when
+// there is no progress yet.
+func FormatProgress(p model.OperationProgress) string {
+	switch {
+	case p.DocumentsEstimated > 0:
+		return fmt.Sprintf("%d%% (%s/%s docs)", percent(p.DocumentsCompleted, p.DocumentsEstimated),
+			formatCount(p.DocumentsCompleted), formatCount(p.DocumentsEstimated))
+	case p.BytesEstimated > 0:
+		return fmt.Sprintf("%d%% (%s/%s)", percent(p.BytesCompleted, p.BytesEstimated),
+			formatBytes(p.BytesCompleted), formatBytes(p.BytesEstimated))
+	case p.DocumentsCompleted > 0:
+		return formatCount(p.DocumentsCompleted) + " docs"
+	default:
+		return p.State
+	}
+}
+
+// percent returns completed/estimated as a percentage capped at 100, since
+// estimates may be lower than the actual work
+func percent(completed, estimated int64) int64 {
+	p := completed * 100 / estimated
+	if p > 100 {
+		return 100
+	}
+	return p
+}
+
+// formatCount formats a count with a K, M or B suffix
+func formatCount(n int64) string {
+	return formatScaled(n, "", []string{"K", "M", "B"})
+}
+
+// formatBytes formats a byte size with a decimal unit
+func formatBytes(n int64) string {
+	return formatScaled(n, "B", []string{"KB", "MB", "GB", "TB"})
+}
+
+// formatScaled divides n by 1000 until it is below 1000 and appends the unit
+// of that scale with one decimal
+func formatScaled(n int64, unit string, scales []string) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d%s", n, unit)
+	}
+	v := float64(n)
+	scale := ""
+	for _, s := range scales {
+		v /= 1000
+		scale = s
+		if v < 1000 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f%s", v, scale)
+}
@@ -0,0 +1,54 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/gt"
+)
+
+func TestFormatProgress(t *testing.T) {
+	testCases := []struct {
+		name     string
+		progress model.OperationProgress
+		expected string
+	}{
+		{
+			name:     "Normal: documents",
+			progress: model.OperationProgress{DocumentsCompleted: 1_204_000, DocumentsEstimated: 2_800_000},
+			expected: "43% (1.2M/2.8M docs)",
+		},
+		{
+			name:     "Normal: documents preferred over bytes",
+			progress: model.OperationProgress{DocumentsCompleted: 500, DocumentsEstimated: 1000, BytesCompleted: 10, BytesEstimated: 100},
+			expected: "50% (500/1.0K docs)",
+		},
+		{
+			name:     "Normal: bytes without document estimate",
+			progress: model.OperationProgress{BytesCompleted: 1_500_000_000, BytesEstimated: 3_000_000_000},
+			expected: "50% (1.5GB/3.0GB)",
+		},
+		{
+			name:     "Normal: completed beyond estimate is capped",
+			progress: model.OperationProgress{DocumentsCompleted: 1200, DocumentsEstimated: 1000},
+			expected: "100% (1.2K/1.0K docs)",
+		},
+		{
+			name:     "Normal: documents without estimate",
+			progress: model.OperationProgress{DocumentsCompleted: 42_000},
+			expected: "42.0K docs",
+		},
+		{
+			name:     "Normal: no progress yet",
+			progress: model.OperationProgress{State: "INITIALIZING"},
+			expected: "INITIALIZING",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gt.Equal(t, usecase.FormatProgress(tc.progress), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// RequiredIndex returns the composite index that Firestore needs to serve the
+// query, or nil if the automatic single-field indexes are sufficient.
+//
+// The index is built following Firestore's index rules:
+//  1. Equality fields come first. Their relative order does not matter.
+//  2. The array-contains field follows the equality fields.
+//  3. Explicit orderBy fields follow in query order. orderBy on an equality
+//     field is a no-op and dropped.
+//  4. Range (inequality) fields that are not explicitly ordered are ordered
+//     implicitly, lexicographically, in the direction of the last orderBy.
+//  5. For vector queries the vector field is last and the orderBy clauses
+//     are ignored, since results are ordered by distance.
+//
+// Queries using only equality and array-contains filters are served by
+// merging single-field indexes, as are queries whose index would consist of
+// a single field. Firestore creates single-field indexes automatically only
+// for COLLECTION scope, so collection group queries always need an index:
+// a one-field requirement stands for a single-field index with
+// COLLECTION_GROUP scope, which is not a composite index.
+func RequiredIndex(q model.Query) *model.Index {
+	equality := uniqueFields(q.Equality)
+	equalitySet := make(map[string]bool, len(equality))
+	for _, field := range equality {
+		equalitySet[field] = true
+	}
+
+	index := &model.Index{QueryScope: q.GetQueryScope()}
+	for _, field := range equality {
+		index.Fields = append(index.Fields, model.IndexField{Name: field, Order: "ASCENDING"})
+	}
+	if q.ArrayContains != "" {
+		index.Fields = append(index.Fields, model.IndexField{Name: q.ArrayContains, ArrayConfig: "CONTAINS"})
+	}
+
+	if q.FindNearest != nil {
+		// Range filters act as pre-filters; orderBy is not applicable to vector queries
+		for _, field := range uniqueFields(q.Range) {
+			if !equalitySet[field] {
+				index.Fields = append(index.Fields, model.IndexField{Name: field, Order: "ASCENDING"})
+			}
+		}
+		index.Fields = append(index.Fields, model.IndexField{
+			Name:         q.FindNearest.Field,
+			VectorConfig: &model.VectorConfig{Dimension: q.FindNearest.Dimension},
+		})
+		return index
+	}
+
+	orders := orderFields(q, equalitySet)
+	index.Fields = append(index.Fields, orders...)
+
+	if index.QueryScope == "COLLECTION_GROUP" {
+		if len(index.Fields) == 0 {
+			return nil
+		}
+		return index
+	}
+	if len(orders) == 0 || len(index.Fields) < 2 {
+		return nil
+	}
+	return index
+}
+
+// isSingleFieldRequirement returns true if the required index is a
+// single-field index, which cannot be configured as a composite index.
+// Vector indexes are composite indexes even with a single field.
+func isSingleFieldRequirement(required model.Index) bool {
+	return len(required.Fields) == 1 && required.Fields[0].VectorConfig == nil
+}
+
+// orderFields returns the ordered (non-equality) part of the index required
+// by the query: explicit orderBy fields followed by implicitly ordered range
+// fields.
+func orderFields(q model.Query, equalitySet map[string]bool) []model.IndexField {
+	var fields []model.IndexField
+	seen := make(map[string]bool)
+	direction := "ASCENDING"
+
+	for _, order := range q.OrderBy {
+		if equalitySet[order.Field] || seen[order.Field] {
+			continue
+		}
+		seen[order.Field] = true
+		direction = order.Order
+		if direction == "" {
+			direction = "ASCENDING"
+		}
+		fields = append(fields, model.IndexField{Name: order.Field, Order: direction})
+	}
+
+	var implicit []string
+	for _, field := range uniqueFields(q.Range) {
+		if !equalitySet[field] && !seen[field] {
+			implicit = append(implicit, field)
+		}
+	}
+	sort.Strings(implicit)
+	for _, field := range implicit {
+		fields = append(fields, model.IndexField{Name: field, Order: direction})
+	}
+
+	return fields
+}
+
+// equalityPrefixLen returns how many leading fields of the required index
+// may appear in any order (equality and array-contains fields).
+func equalityPrefixLen(q model.Query) int {
+	n := len(uniqueFields(q.Equality))
+	if q.ArrayContains != "" {
+		n++
+	}
+	return n
+}
+
+// indexMismatch compares an index against the index required by the query.
+// It returns an empty string if the index serves the query, or a human
+// readable reason why it does not.
+func indexMismatch(index model.Index, q model.Query, required model.Index) string {
+	if normalizedScope(index) != normalizedScope(required) {
+		return fmt.Sprintf("query scope is %s, query needs %s", normalizedScope(index), normalizedScope(required))
+	}
+
+	fields := make([]model.IndexField, 0, len(index.Fields))
+	for _, field := range index.Fields {
+		if field.Name != "__name__" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) != len(required.Fields) {
+		return fmt.Sprintf("index has %d fields, query needs %d", len(fields), len(required.Fields))
+	}
+
+	// Equality and array-contains fields may appear in any order
+	prefix := equalityPrefixLen(q)
+	used := make([]bool, prefix)
+	for _, want := range required.Fields[:prefix] {
+		found := false
+		for i, have := range fields[:prefix] {
+			if used[i] || have.Name != want.Name || have.VectorConfig != nil {
+				continue
+			}
+			if (want.ArrayConfig != "") != (have.ArrayConfig != "") {
+				continue
+			}
+			used[i] = true
+			found = true
+			break
+		}
+		if !found {
+			if want.ArrayConfig != "" {
+				return fmt.Sprintf("field %s is not indexed with arrayConfig CONTAINS in the leading fields", want.Name)
+			}
+			return fmt.Sprintf("equality field %s is not in the leading fields", want.Name)
+		}
+	}
+
+	for i := prefix; i < len(required.Fields); i++ {
+		want, have := required.Fields[i], fields[i]
+		if have.Name != want.Name {
+			return fmt.Sprintf("field %d is %s, query needs %s", i+1, have.Name, want.Name)
+		}
+		if want.VectorConfig != nil {
+			if have.VectorConfig == nil {
+				return fmt.Sprintf("field %s has no vectorConfig", want.Name)
+			}
+			if want.VectorConfig.Dimension != 0 && have.VectorConfig.Dimension != want.VectorConfig.Dimension {
+				return fmt.Sprintf("field %s has vector dimension %d, query needs %d",
+					want.Name, have.VectorConfig.Dimension, want.VectorConfig.Dimension)
+			}
+			continue
+		}
+		if have.VectorConfig != nil || have.ArrayConfig != "" || have.Order != want.Order {
+			return fmt.Sprintf("field %s is not ordered %s", want.Name, want.Order)
+		}
+	}
+
+	return ""
+}
+
+// IndexServesQuery returns true if the composite index can serve the query.
+// Queries that need no composite index are never served by one.
+func IndexServesQuery(index model.Index, q model.Query) bool {
+	required := RequiredIndex(q)
+	if required == nil {
+		return false
+	}
+	return indexMismatch(index, q, *required) == ""
+}
+
+// DeriveIndexes computes the composite indexes required by the queries and
+// merges them into a copy of base (which may be nil). An index is only added
+// if no index already present in the collection serves the query, so the
+// result is the minimal extension of base that serves every query.
+// Collection group queries on a single field are skipped, since they need a
+// single-field index rather than a composite one.
+func DeriveIndexes(base *model.Config, queries []model.Query) (*model.Config, error) {
+	result := &model.Config{}
+	if base != nil {
+		for _, col := range base.Collections {
+			col.Indexes = append([]model.Index(nil), col.Indexes...)
+			result.Collections = append(result.Collections, col)
+		}
+	}
+
+	for i, q := range queries {
+		if err := q.Validate(); err != nil {
+			return nil, goerr.Wrap(err, "invalid query", goerr.V("index", i), goerr.V("name", q.Name))
+		}
+
+		required := RequiredIndex(q)
+		if required == nil || isSingleFieldRequirement(*required) {
+			continue
+		}
+		if q.FindNearest != nil && q.FindNearest.Dimension == 0 {
+			return nil, goerr.New("findNearest dimension is required to derive a vector index",
+				goerr.V("name", q.Name), goerr.V("collection", q.Collection))
+		}
+
+		col := findOrAddCollection(result, q.Collection)
+		served := false
+		for _, idx := range col.Indexes {
+			if IndexServesQuery(idx, q) {
+				served = true
+				break
+			}
+		}
+		if !served {
+			col.Indexes = append(col.Indexes, *required)
+		}
+	}
+
+	return result, nil
+}
+
+// findOrAddCollection returns the collection with the given name, appending
+// an empty one to the config if it does not exist yet.
+func findOrAddCollection(config *model.Config, name string) *model.Collection {
+	for i := range config.Collections {
+		if config.Collections[i].Name == name {
+			return &config.Collections[i]
+		}
+	}
+	config.Collections = append(config.Collections, model.Collection{Name: name})
+	return &config.Collections[len(config.Collections)-1]
+}
+
+// normalizedScope returns the query scope used for index identity. Vector
+// indexes are normalized to COLLECTION, matching getIndexKey.
+func normalizedScope(index model.Index) string {
+	for _, field := range index.Fields {
+		if field.VectorConfig != nil {
+			return "COLLECTION"
+		}
+	}
+	return index.GetQueryScope()
+}
+
+// uniqueFields removes duplicate field names while preserving order
+func uniqueFields(fields []string) []string {
+	seen := make(map[string]bool, len(fields))
+	result := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !seen[field] {
+			seen[field] = true
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// QueryExplanation describes how a query is served by a configuration
+type QueryExplanation struct {
+	// Served is true if Firestore can execute the query with the configuration
+	Served bool
+	// SingleField is true if the query needs no composite index
+	SingleField bool
+	// Index is the composite index serving the query, if any
+	Index *model.Index
+	// Required is the index the query needs, if any; a single field for
+	// collection group queries needing a single-field index
+	Required *model.Index
+	// Reason explains why no index serves the query
+	Reason string
+}
+
+// ExplainQuery checks a query against a configuration and reports which index
+// serves it or why none does.
+func ExplainQuery(config *model.Config, q model.Query) (*QueryExplanation, error) {
+	if err := q.Validate(); err != nil {
+		return nil, goerr.Wrap(err, "invalid query", goerr.V("name", q.Name))
+	}
+
+	required := RequiredIndex(q)
+	if required == nil {
+		return &QueryExplanation{Served: true, SingleField: true}, nil
+	}
+
+	explanation := &QueryExplanation{Required: required}
+	if isSingleFieldRequirement(*required) {
+		explanation.Reason = fmt.Sprintf("collection group query needs a single-field index on %s with COLLECTION_GROUP scope, which Firestore does not create automatically",
+			required.Fields[0].Name)
+		return explanation, nil
+	}
+
+	var collection *model.Collection
+	for i := range config.Collections {
+		if config.Collections[i].Name == q.Collection {
+			collection = &config.Collections[i]
+			break
+		}
+	}
+	if collection == nil {
+		explanation.Reason = fmt.Sprintf("collection %s is not configured", q.Collection)
+		return explanation, nil
+	}
+	if len(collection.Indexes) == 0 {
+		explanation.Reason = fmt.Sprintf("collection %s has no composite index", q.Collection)
+		return explanation, nil
+	}
+
+	// Report the mismatch of the candidate sharing the most fields with the
+	// required index, which is most likely the one intended for the query
+	bestScore := -1
+	for i, idx := range collection.Indexes {
+		mismatch := indexMismatch(idx, q, *required)
+		if mismatch == "" {
+			explanation.Served = true
+			explanation.Index = &collection.Indexes[i]
+			explanation.Reason = ""
+			return explanation, nil
+		}
+
+		if score := sharedFields(idx, *required); score > bestScore {
+			bestScore = score
+			explanation.Reason = fmt.Sprintf("closest index %s: %s", FormatIndex(idx), mismatch)
+		}
+	}
+
+	return explanation, nil
+}
+
+// sharedFields counts the fields of required that also appear in index
+func sharedFields(index, required model.Index) int {
+	names := make(map[string]bool, len(index.Fields))
+	for _, field := range index.Fields {
+		names[field.Name] = true
+	}
+	n := 0
+	for _, field := range required.Fields {
+		if names[field.Name] {
+			n++
+		}
+	}
+	return n
+}
+
+// FormatIndex returns a compact representation of an index such as
+// "(status ASC, tags CONTAINS, embedding VECTOR(768))"
+func FormatIndex(index model.Index) string {
+	parts := make([]string, 0, len(index.Fields))
+	for _, field := range index.Fields {
+		switch {
+		case field.VectorConfig != nil:
+			parts = append(parts, fmt.Sprintf("%s VECTOR(%d)", field.Name, field.VectorConfig.Dimension))
+		case field.ArrayConfig != "":
+			parts = append(parts, field.Name+" "+field.ArrayConfig)
+		case field.Order == "DESCENDING":
+			parts = append(parts, field.Name+" DESC")
+		default:
+			parts = append(parts, field.Name+" ASC")
+		}
+	}
+
+	s := "(" + strings.Join(parts, ", ") + ")"
+	if index.GetQueryScope() == "COLLECTION_GROUP" {
+		s += " COLLECTION_GROUP"
+	}
+	return s
+}
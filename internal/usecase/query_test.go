@@ -0,0 +1,390 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/gt"
+)
+
+func TestRequiredIndex(t *testing.T) {
+	t.Run("Equality only is served by single-field indexes", func(t *testing.T) {
+		q := model.Query{Collection: "orders", Equality: []string{"status", "customerId"}}
+		gt.V(t, usecase.RequiredIndex(q)).Nil()
+	})
+
+	t.Run("Single orderBy is served by single-field indexes", func(t *testing.T) {
+		q := model.Query{
+			Collection: "orders",
+			OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+		}
+		gt.V(t, usecase.RequiredIndex(q)).Nil()
+	})
+
+	t.Run("Range and orderBy on the same field is served by single-field indexes", func(t *testing.T) {
+		q := model.Query{
+			Collection: "orders",
+			Range:      []string{"total"},
+			OrderBy:    []model.QueryOrder{{Field: "total", Order: "ASCENDING"}},
+		}
+		gt.V(t, usecase.RequiredIndex(q)).Nil()
+	})
+
+	t.Run("Equality with orderBy", func(t *testing.T) {
+		q := model.Query{
+			Collection: "orders",
+			Equality:   []string{"customerId"},
+			OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+		}
+		idx := usecase.RequiredIndex(q)
+		gt.V(t, idx).NotNil()
+		gt.Equal(t, idx.Fields, []model.IndexField{
+			{Name: "customerId", Order: "ASCENDING"},
+			{Name: "createdAt", Order: "DESCENDING"},
+		})
+		gt.Equal(t, idx.QueryScope, "COLLECTION")
+	})
+
+	t.Run("orderBy on an equality field is dropped", func(t *testing.T) {
+		q := model.Query{
+			Collection: "orders",
+			Equality:   []string{"status"},
+			OrderBy:    []model.QueryOrder{{Field: "status", Order: "DESCENDING"}},
+		}
+		gt.V(t, usecase.RequiredIndex(q)).Nil()
+	})
+
+	t.Run("Implicit range ordering follows explicit orderBy", func(t *testing.T) {
+		q := model.Query{
+			Collection: "orders",
+			Equality:   []string{"status"},
+			Range:      []string{"total", "discount"},
+			OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+		}
+		idx := usecase.RequiredIndex(q)
+		gt.V(t, idx).NotNil()
+		gt.Equal(t, idx.Fields, []model.IndexField{
+			{Name: "status", Order: "ASCENDING"},
+			{Name: "createdAt", Order: "DESCENDING"},
+			{Name: "discount", Order: "DESCENDING"},
+			{Name: "total", Order: "DESCENDING"},
+		})
+	})
+
+	t.Run("Array-contains with orderBy", func(t *testing.T) {
+		q := model.Query{
+			Collection:    "posts",
+			QueryScope:    "COLLECTION_GROUP",
+			ArrayContains: "tags",
+			OrderBy:       []model.QueryOrder{{Field: "score", Order: "DESCENDING"}},
+		}
+		idx := usecase.RequiredIndex(q)
+		gt.V(t, idx).NotNil()
+		gt.Equal(t, idx.Fields, []model.IndexField{
+			{Name: "tags", ArrayConfig: "CONTAINS"},
+			{Name: "score", Order: "DESCENDING"},
+		})
+		gt.Equal(t, idx.QueryScope, "COLLECTION_GROUP")
+	})
+
+	t.Run("Single field collection group query needs a single-field index", func(t *testing.T) {
+		q := model.Query{
+			Collection: "posts",
+			QueryScope: "COLLECTION_GROUP",
+			OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+		}
+		idx := usecase.RequiredIndex(q)
+		gt.V(t, idx).NotNil()
+		gt.Equal(t, idx.Fields, []model.IndexField{{Name: "createdAt", Order: "DESCENDING"}})
+		gt.Equal(t, idx.QueryScope, "COLLECTION_GROUP")
+	})
+
+	t.Run("Equality only collection group query needs an index", func(t *testing.T) {
+		q := model.Query{Collection: "posts", QueryScope: "COLLECTION_GROUP", Equality: []string{"author", "status"}}
+		idx := usecase.RequiredIndex(q)
+		gt.V(t, idx).NotNil()
+		gt.Equal(t, idx.Fields, []model.IndexField{
+			{Name: "author", Order: "ASCENDING"},
+			{Name: "status", Order: "ASCENDING"},
+		})
+	})
+
+	t.Run("Collection group query without fields needs no index", func(t *testing.T) {
+		q := model.Query{Collection: "posts", QueryScope: "COLLECTION_GROUP"}
+		gt.V(t, usecase.RequiredIndex(q)).Nil()
+	})
+
+	t.Run("Vector search always needs an index", func(t *testing.T) {
+		q := model.Query{
+			Collection:  "documents",
+			Equality:    []string{"category"},
+			FindNearest: &model.FindNearest{Field: "embedding", Dimension: 768},
+		}
+		idx := usecase.RequiredIndex(q)
+		gt.V(t, idx).NotNil()
+		gt.Equal(t, len(idx.Fields), 2)
+		gt.Equal(t, idx.Fields[0].Name, "category")
+		gt.Equal(t, idx.Fields[1].Name, "embedding")
+		gt.Equal(t, idx.Fields[1].VectorConfig.Dimension, 768)
+	})
+}
+
+func TestIndexServesQuery(t *testing.T) {
+	q := model.Query{
+		Collection: "orders",
+		Equality:   []string{"customerId", "status"},
+		OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+	}
+
+	t.Run("Equality fields in any order", func(t *testing.T) {
+		idx := model.Index{Fields: []model.IndexField{
+			{Name: "status", Order: "DESCENDING"},
+			{Name: "customerId", Order: "ASCENDING"},
+			{Name: "createdAt", Order: "DESCENDING"},
+			{Name: "__name__", Order: "DESCENDING"},
+		}}
+		gt.True(t, usecase.IndexServesQuery(idx, q))
+	})
+
+	t.Run("Wrong order direction", func(t *testing.T) {
+		idx := model.Index{Fields: []model.IndexField{
+			{Name: "customerId", Order: "ASCENDING"},
+			{Name: "status", Order: "ASCENDING"},
+			{Name: "createdAt", Order: "ASCENDING"},
+		}}
+		gt.False(t, usecase.IndexServesQuery(idx, q))
+	})
+
+	t.Run("Ordered field before equality fields", func(t *testing.T) {
+		idx := model.Index{Fields: []model.IndexField{
+			{Name: "createdAt", Order: "DESCENDING"},
+			{Name: "customerId", Order: "ASCENDING"},
+			{Name: "status", Order: "ASCENDING"},
+		}}
+		gt.False(t, usecase.IndexServesQuery(idx, q))
+	})
+
+	t.Run("Wrong query scope", func(t *testing.T) {
+		idx := model.Index{
+			QueryScope: "COLLECTION_GROUP",
+			Fields: []model.IndexField{
+				{Name: "customerId", Order: "ASCENDING"},
+				{Name: "status", Order: "ASCENDING"},
+				{Name: "createdAt", Order: "DESCENDING"},
+			},
+		}
+		gt.False(t, usecase.IndexServesQuery(idx, q))
+	})
+
+	t.Run("Unknown vector dimension matches any dimension", func(t *testing.T) {
+		vq := model.Query{
+			Collection:  "documents",
+			FindNearest: &model.FindNearest{Field: "embedding"},
+		}
+		idx := model.Index{Fields: []model.IndexField{
+			{Name: "embedding", VectorConfig: &model.VectorConfig{Dimension: 256}},
+		}}
+		gt.True(t, usecase.IndexServesQuery(idx, vq))
+	})
+}
+
+func TestDeriveIndexes(t *testing.T) {
+	t.Run("Deduplicates queries served by the same index", func(t *testing.T) {
+		queries := []model.Query{
+			{
+				Collection: "orders",
+				Equality:   []string{"customerId", "status"},
+				OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+			},
+			{
+				Collection: "orders",
+				Equality:   []string{"status", "customerId"},
+				OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+			},
+			{
+				Collection: "orders",
+				Equality:   []string{"status"},
+			},
+			{
+				Collection: "users",
+				Range:      []string{"age"},
+				OrderBy:    []model.QueryOrder{{Field: "name"}},
+			},
+			{
+				Collection: "users",
+				QueryScope: "COLLECTION_GROUP",
+				OrderBy:    []model.QueryOrder{{Field: "name"}},
+			},
+		}
+
+		config, err := usecase.DeriveIndexes(nil, queries)
+		gt.NoError(t, err)
+		gt.Equal(t, len(config.Collections), 2)
+		gt.Equal(t, config.Collections[0].Name, "orders")
+		gt.Equal(t, len(config.Collections[0].Indexes), 1)
+		gt.Equal(t, config.Collections[1].Name, "users")
+		gt.Equal(t, len(config.Collections[1].Indexes), 1)
+		gt.Equal(t, config.Collections[1].Indexes[0].Fields, []model.IndexField{
+			{Name: "name", Order: "ASCENDING"},
+			{Name: "age", Order: "ASCENDING"},
+		})
+	})
+
+	t.Run("Merges into base config without duplicating served indexes", func(t *testing.T) {
+		base := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "orders",
+					Indexes: []model.Index{
+						{Fields: []model.IndexField{
+							{Name: "customerId", Order: "ASCENDING"},
+							{Name: "createdAt", Order: "DESCENDING"},
+						}},
+					},
+					TTL: &model.TTL{Field: "expireAt"},
+				},
+			},
+		}
+		queries := []model.Query{
+			{
+				Collection: "orders",
+				Equality:   []string{"customerId"},
+				OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+			},
+			{
+				Collection: "orders",
+				Equality:   []string{"status"},
+				OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+			},
+		}
+
+		config, err := usecase.DeriveIndexes(base, queries)
+		gt.NoError(t, err)
+		gt.Equal(t, len(config.Collections), 1)
+		gt.Equal(t, len(config.Collections[0].Indexes), 2)
+		gt.Equal(t, config.Collections[0].Indexes[1].Fields[0].Name, "status")
+		gt.Equal(t, config.Collections[0].TTL.Field, "expireAt")
+
+		// base must not be modified
+		gt.Equal(t, len(base.Collections[0].Indexes), 1)
+	})
+
+	t.Run("Vector query without dimension fails", func(t *testing.T) {
+		queries := []model.Query{
+			{Collection: "documents", FindNearest: &model.FindNearest{Field: "embedding"}},
+		}
+		_, err := usecase.DeriveIndexes(nil, queries)
+		gt.Error(t, err)
+	})
+
+	t.Run("Invalid query fails", func(t *testing.T) {
+		queries := []model.Query{
+			{Equality: []string{"status"}},
+		}
+		_, err := usecase.DeriveIndexes(nil, queries)
+		gt.Error(t, err)
+	})
+}
+
+func TestQuery_AddFilter(t *testing.T) {
+	var q model.Query
+	gt.NoError(t, q.AddFilter("status", "=="))
+	gt.NoError(t, q.AddFilter("region", "in"))
+	gt.NoError(t, q.AddFilter("total", ">="))
+	gt.NoError(t, q.AddFilter("tags", "array-contains"))
+	gt.Error(t, q.AddFilter("labels", "array-contains-any"))
+	gt.Error(t, q.AddFilter("x", "~="))
+
+	gt.Equal(t, q.Equality, []string{"status", "region"})
+	gt.Equal(t, q.Range, []string{"total"})
+	gt.Equal(t, q.ArrayContains, "tags")
+}
+
+func TestExplainQuery(t *testing.T) {
+	config := &model.Config{
+		Collections: []model.Collection{
+			{
+				Name: "orders",
+				Indexes: []model.Index{
+					{Fields: []model.IndexField{
+						{Name: "status", Order: "ASCENDING"},
+						{Name: "createdAt", Order: "DESCENDING"},
+					}},
+					{Fields: []model.IndexField{
+						{Name: "customerId", Order: "ASCENDING"},
+						{Name: "total", Order: "ASCENDING"},
+					}},
+				},
+			},
+			{Name: "users"},
+		},
+	}
+
+	t.Run("Served by configured index", func(t *testing.T) {
+		q := model.Query{
+			Collection: "orders",
+			Equality:   []string{"status"},
+			OrderBy:    []model.QueryOrder{{Field: "createdAt", Order: "DESCENDING"}},
+		}
+		explanation, err := usecase.ExplainQuery(config, q)
+		gt.NoError(t, err)
+		gt.True(t, explanation.Served)
+		gt.False(t, explanation.SingleField)
+		gt.Equal(t, explanation.Index, &config.Collections[0].Indexes[0])
+	})
+
+	t.Run("Served by single-field indexes", func(t *testing.T) {
+		q := model.Query{Collection: "unknown", Equality: []string{"status"}}
+		explanation, err := usecase.ExplainQuery(config, q)
+		gt.NoError(t, err)
+		gt.True(t, explanation.Served)
+		gt.True(t, explanation.SingleField)
+	})
+
+	t.Run("Collection group query on a single field is not served", func(t *testing.T) {
+		q := model.Query{Collection: "orders", QueryScope: "COLLECTION_GROUP", Equality: []string{"status"}}
+		explanation, err := usecase.ExplainQuery(config, q)
+		gt.NoError(t, err)
+		gt.False(t, explanation.Served)
+		gt.False(t, explanation.SingleField)
+		gt.Equal(t, explanation.Reason, "collection group query needs a single-field index on status with COLLECTION_GROUP scope, which Firestore does not create automatically")
+	})
+
+	t.Run("Closest index is reported", func(t *testing.T) {
+		q := model.Query{
+			Collection: "orders",
+			Equality:   []string{"customerId"},
+			OrderBy:    []model.QueryOrder{{Field: "total", Order: "DESCENDING"}},
+		}
+		explanation, err := usecase.ExplainQuery(config, q)
+		gt.NoError(t, err)
+		gt.False(t, explanation.Served)
+		gt.Equal(t, explanation.Reason, "closest index (customerId ASC, total ASC): field total is not ordered DESCENDING")
+		gt.Equal(t, usecase.FormatIndex(*explanation.Required), "(customerId ASC, total DESC)")
+	})
+
+	t.Run("Collection without indexes", func(t *testing.T) {
+		q := model.Query{
+			Collection: "users",
+			Equality:   []string{"team"},
+			OrderBy:    []model.QueryOrder{{Field: "name"}},
+		}
+		explanation, err := usecase.ExplainQuery(config, q)
+		gt.NoError(t, err)
+		gt.False(t, explanation.Served)
+		gt.Equal(t, explanation.Reason, "collection users has no composite index")
+	})
+
+	t.Run("Collection not configured", func(t *testing.T) {
+		q := model.Query{
+			Collection: "payments",
+			Equality:   []string{"team"},
+			OrderBy:    []model.QueryOrder{{Field: "name"}},
+		}
+		explanation, err := usecase.ExplainQuery(config, q)
+		gt.NoError(t, err)
+		gt.False(t, explanation.Served)
+		gt.Equal(t, explanation.Reason, "collection payments is not configured")
+	})
+}
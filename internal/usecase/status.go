@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// Presence tells whether an index or TTL policy is in the configuration,
+// in Firestore, or both
+type Presence string
+
+const (
+	// PresenceManaged is in the configuration and in Firestore
+	PresenceManaged Presence = "managed"
+	// PresenceMissing is in the configuration but not in Firestore
+	PresenceMissing Presence = "missing"
+	// PresenceUnmanaged is in Firestore but not in the configuration
+	PresenceUnmanaged Presence = "unmanaged"
+)
+
+// IndexStatus is the live state of a composite index
+type IndexStatus struct {
+	Index model.Index
+	// Name is the resource name, empty if the index is missing
+	Name     string
+	State    string
+	Presence Presence
+}
+
+// TTLStatus is the live state of a TTL policy
+type TTLStatus struct {
+	Field    string
+	State    string
+	Presence Presence
+}
+
+// CollectionStatus is the live state of the indexes and TTL policies of a
+// collection
+type CollectionStatus struct {
+	Name    string
+	Indexes []IndexStatus
+	// TTL has two entries when the configured TTL field differs from the
+	// live one
+	TTL []TTLStatus
+}
+
+// Status reports the live state of indexes and TTL policies compared with a
+// configuration
+type Status struct {
+	client interfaces.FirestoreClient
+	logger *slog.Logger
+}
+
+// NewStatus creates a new Status use case
+func NewStatus(client interfaces.FirestoreClient, logger *slog.Logger) *Status {
+	return &Status{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Execute reads the state of the given collections. Without collections, it
+// reads the collections of the configuration and those discovered in
+// Firestore. config may be nil, in which case everything is unmanaged.
+func (s *Status) Execute(ctx context.Context, config *model.Config, collections []string) ([]CollectionStatus, error) {
+	desired := make(map[string]model.Collection)
+	if config != nil {
+		for _, c := range config.Collections {
+			desired[c.Name] = c
+		}
+	}
+
+	if len(collections) == 0 {
+		seen := make(map[string]bool)
+		if config != nil {
+			for _, c := range config.Collections {
+				if !seen[c.Name] {
+					seen[c.Name] = true
+					collections = append(collections, c.Name)
+				}
+			}
+		}
+
+		discovered, err := s.client.ListCollections(ctx)
+		if err != nil {
+			if len(collections) == 0 {
+				return nil, goerr.Wrap(err, "failed to discover collections. Please specify collection names explicitly if discovery fails.")
+			}
+			s.logger.Warn("Failed to discover collections, showing configured collections only",
+				slog.Any("error", err))
+		}
+		for _, name := range discovered {
+			if !seen[name] {
+				seen[name] = true
+				collections = append(collections, name)
+			}
+		}
+		sort.Strings(collections)
+	}
+
+	result := make([]CollectionStatus, 0, len(collections))
+	for _, name := range collections {
+		status, err := s.collectionStatus(ctx, name, desired[name])
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to get collection status", goerr.V("collection", name))
+		}
+		result = append(result, *status)
+	}
+
+	return result, nil
+}
+
+// collectionStatus reads the state of a single collection. desired is the
+// zero value if the collection is not configured.
+func (s *Status) collectionStatus(ctx context.Context, name string, desired model.Collection) (*CollectionStatus, error) {
+	status := &CollectionStatus{Name: name}
+
+	existing, err := s.client.ListIndexes(ctx, name)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list indexes")
+	}
+
+	// Configured indexes first, in configuration order
+	matched := make(map[string]bool)
+	existingMap := make(map[string]interfaces.FirestoreIndex)
+	for _, idx := range existing {
+		existingMap[getIndexKey(idx)] = idx
+	}
+	for _, idx := range desired.Indexes {
+		key := getIndexKey(ConvertModelToFirestoreIndex(idx))
+		live, found := existingMap[key]
+		if !found {
+			status.Indexes = append(status.Indexes, IndexStatus{Index: idx, Presence: PresenceMissing})
+			continue
+		}
+		matched[key] = true
+		status.Indexes = append(status.Indexes, IndexStatus{
+			Index:    idx,
+			Name:     live.Name,
+			State:    live.State,
+			Presence: PresenceManaged,
+		})
+	}
+
+	for _, idx := range existing {
+		if matched[getIndexKey(idx)] {
+			continue
+		}
+		status.Indexes = append(status.Indexes, IndexStatus{
+			Index:    NormalizeFirestoreIndex(idx),
+			Name:     idx.Name,
+			State:    idx.State,
+			Presence: PresenceUnmanaged,
+		})
+	}
+
+	ttl, err := s.ttlStatus(ctx, name, desired.TTL)
+	if err != nil {
+		return nil, err
+	}
+	status.TTL = ttl
+
+	return status, nil
+}
+
+// ttlStatus reads the configured TTL policy and the TTL policy actually set
+// on the collection
+func (s *Status) ttlStatus(ctx context.Context, collectionName string, desired *model.TTL) ([]TTLStatus, error) {
+	var result []TTLStatus
+
+	if desired != nil {
+		ttl, err := s.client.GetTTLPolicy(ctx, collectionName, desired.Field)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to get TTL policy", goerr.V("field", desired.Field))
+		}
+		if ttl == nil {
+			result = append(result, TTLStatus{Field: desired.Field, Presence: PresenceMissing})
+		} else {
+			result = append(result, TTLStatus{Field: desired.Field, State: ttl.State, Presence: PresenceManaged})
+		}
+	}
+
+	liveField, err := s.client.FindTTLField(ctx, collectionName)
+	if err != nil {
+		// TTL is optional, as on import
+		s.logger.Debug("Failed to find TTL field",
+			slog.String("collection", collectionName),
+			slog.String("error", err.Error()))
+		return result, nil
+	}
+	if liveField == "" || (desired != nil && liveField == desired.Field) {
+		return result, nil
+	}
+
+	ttl, err := s.client.GetTTLPolicy(ctx, collectionName, liveField)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get TTL policy", goerr.V("field", liveField))
+	}
+	if ttl != nil {
+		result = append(result, TTLStatus{Field: liveField, State: ttl.State, Presence: PresenceUnmanaged})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package usecase_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/fireconf/internal/interfaces/mock"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/gt"
+)
+
+func TestStatus_Execute(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.Default()
+
+	config := &model.Config{
+		Collections: []model.Collection{
+			{
+				Name: "users",
+				Indexes: []model.Index{
+					{
+						Fields: []model.IndexField{
+							{Name: "email", Order: "ASCENDING"},
+							{Name: "createdAt", Order: "DESCENDING"},
+						},
+						QueryScope: "COLLECTION",
+					},
+					{
+						Fields: []model.IndexField{
+							{Name: "status", Order: "ASCENDING"},
+							{Name: "createdAt", Order: "DESCENDING"},
+						},
+						QueryScope: "COLLECTION",
+					},
+				},
+				TTL: &model.TTL{Field: "expireAt"},
+			},
+		},
+	}
+
+	listIndexes := func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+		if collectionID != "users" {
+			return nil, nil
+		}
+		return []interfaces.FirestoreIndex{
+			{
+				Name: "idx-unmanaged",
+				Fields: []interfaces.FirestoreIndexField{
+					{FieldPath: "owner", Order: "ASCENDING"},
+					{FieldPath: "createdAt", Order: "ASCENDING"},
+					{FieldPath: "__name__", Order: "ASCENDING"},
+				},
+				QueryScope: "COLLECTION",
+				State:      "READY",
+			},
+			{
+				Name: "idx-managed",
+				Fields: []interfaces.FirestoreIndexField{
+					{FieldPath: "email", Order: "ASCENDING"},
+					{FieldPath: "createdAt", Order: "DESCENDING"},
+					{FieldPath: "__name__", Order: "DESCENDING"},
+				},
+				QueryScope: "COLLECTION",
+				State:      "NEEDS_REPAIR",
+			},
+		}, nil
+	}
+	getTTLPolicy := func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+		if collectionID == "users" && fieldName == "deletedAt" {
+			return &interfaces.FirestoreTTL{FieldPath: fieldName, State: "ACTIVE"}, nil
+		}
+		return nil, nil
+	}
+	findTTLField := func(ctx context.Context, collectionID string) (string, error) {
+		if collectionID == "users" {
+			return "deletedAt", nil
+		}
+		return "", nil
+	}
+
+	t.Run("Normal: annotate configured, missing and unmanaged entries", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListCollectionsFunc: func(ctx context.Context) ([]string, error) {
+				return []string{"users", "logs"}, nil
+			},
+			ListIndexesFunc:  listIndexes,
+			GetTTLPolicyFunc: getTTLPolicy,
+			FindTTLFieldFunc: findTTLField,
+		}
+		result := gt.R1(usecase.NewStatus(mockClient, logger).Execute(ctx, config, nil)).NoError(t)
+
+		gt.Equal(t, len(result), 2)
+		gt.Equal(t, result[0].Name, "logs")
+		gt.Equal(t, len(result[0].Indexes), 0)
+		gt.Equal(t, len(result[0].TTL), 0)
+
+		users := result[1]
+		gt.Equal(t, users.Name, "users")
+		gt.Equal(t, len(users.Indexes), 3)
+		gt.Equal(t, users.Indexes[0].Name, "idx-managed")
+		gt.Equal(t, users.Indexes[0].State, "NEEDS_REPAIR")
+		gt.Equal(t, users.Indexes[0].Presence, usecase.PresenceManaged)
+		gt.Equal(t, users.Indexes[1].Presence, usecase.PresenceMissing)
+		gt.Equal(t, users.Indexes[1].State, "")
+		gt.Equal(t, users.Indexes[2].Name, "idx-unmanaged")
+		gt.Equal(t, users.Indexes[2].Presence, usecase.PresenceUnmanaged)
+		gt.Equal(t, len(users.Indexes[2].Index.Fields), 2)
+
+		gt.Equal(t, users.TTL, []usecase.TTLStatus{
+			{Field: "expireAt", Presence: usecase.PresenceMissing},
+			{Field: "deletedAt", State: "ACTIVE", Presence: usecase.PresenceUnmanaged},
+		})
+	})
+
+	t.Run("Normal: nil config shows everything as unmanaged", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc:  listIndexes,
+			GetTTLPolicyFunc: getTTLPolicy,
+			FindTTLFieldFunc: findTTLField,
+		}
+		result := gt.R1(usecase.NewStatus(mockClient, logger).Execute(ctx, nil, []string{"users"})).NoError(t)
+
+		gt.Equal(t, len(result), 1)
+		gt.Equal(t, len(mockClient.ListCollectionsCalls()), 0)
+		gt.Equal(t, len(result[0].Indexes), 2)
+		for _, idx := range result[0].Indexes {
+			gt.Equal(t, idx.Presence, usecase.PresenceUnmanaged)
+		}
+		gt.Equal(t, len(result[0].TTL), 1)
+		gt.Equal(t, result[0].TTL[0].Presence, usecase.PresenceUnmanaged)
+	})
+}
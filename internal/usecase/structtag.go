@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// fieldTag is a parsed fireconf struct tag. Directives are separated by
+// semicolons:
+//
+//	collection=NAME            the struct is stored in collection NAME
+//	index=NAME,POS[,OPTS...]   the field is at position POS of composite index NAME;
+//	                           OPTS are asc (default), desc, contains and group
+//	ttl                        the field is the TTL field of the collection
+//	vector=DIM                 the field holds vectors of dimension DIM
+type fieldTag struct {
+	collection string
+	ttl        bool
+	vector     int
+	indexes    []indexTag
+}
+
+// indexTag is an index=... directive
+type indexTag struct {
+	name     string
+	position int
+	order    string
+	contains bool
+	group    bool
+}
+
+// parseFieldTag parses the value of a fireconf struct tag
+func parseFieldTag(tag string) (*fieldTag, error) {
+	result := &fieldTag{}
+	for _, directive := range strings.Split(tag, ";") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		key, value, hasValue := strings.Cut(directive, "=")
+
+		switch key {
+		case "collection":
+			if value == "" {
+				return nil, goerr.New("collection name is required")
+			}
+			result.collection = value
+
+		case "ttl":
+			if hasValue {
+				return nil, goerr.New("ttl takes no value")
+			}
+			result.ttl = true
+
+		case "vector":
+			dimension, err := strconv.Atoi(value)
+			if err != nil || dimension <= 0 {
+				return nil, goerr.New("vector dimension must be a positive integer", goerr.V("value", value))
+			}
+			result.vector = dimension
+
+		case "index":
+			index, err := parseIndexTag(value)
+			if err != nil {
+				return nil, err
+			}
+			result.indexes = append(result.indexes, *index)
+
+		default:
+			return nil, goerr.New("unknown fireconf tag directive", goerr.V("directive", directive))
+		}
+	}
+	return result, nil
+}
+
+// parseIndexTag parses the value of an index=NAME,POS[,OPTS...] directive
+func parseIndexTag(value string) (*indexTag, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) < 2 || parts[0] == "" {
+		return nil, goerr.New("index directive must be index=NAME,POS[,OPTS...]", goerr.V("value", value))
+	}
+
+	position, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || position < 0 {
+		return nil, goerr.New("index position must be a non-negative integer", goerr.V("value", value))
+	}
+
+	index := &indexTag{name: strings.TrimSpace(parts[0]), position: position, order: "ASCENDING"}
+	for _, opt := range parts[2:] {
+		switch strings.TrimSpace(opt) {
+		case "asc":
+			index.order = "ASCENDING"
+		case "desc":
+			index.order = "DESCENDING"
+		case "contains":
+			index.contains = true
+		case "group":
+			index.group = true
+		default:
+			return nil, goerr.New("unknown index option", goerr.V("option", opt), goerr.V("value", value))
+		}
+	}
+	return index, nil
+}
+
+// ConfigFromStructs builds a configuration from tagged struct types. Structs
+// stored in the same collection are merged; duplicate indexes are dropped.
+func ConfigFromStructs(structs []model.TaggedStruct) (*model.Config, error) {
+	config := &model.Config{}
+
+	for _, s := range structs {
+		name, indexes, ttl, err := buildStruct(s)
+		if err != nil {
+			return nil, err
+		}
+
+		collection := findOrAddCollection(config, name)
+		for _, index := range indexes {
+			if !containsIndex(collection.Indexes, index) {
+				collection.Indexes = append(collection.Indexes, index)
+			}
+		}
+		if ttl != "" {
+			if collection.TTL != nil && collection.TTL.Field != ttl {
+				return nil, goerr.New("conflicting TTL fields for collection",
+					goerr.V("collection", name), goerr.V("fields", []string{collection.TTL.Field, ttl}), goerr.V("type", s.Name))
+			}
+			collection.TTL = &model.TTL{Field: ttl}
+		}
+	}
+
+	return config, nil
+}
+
+// buildStruct returns the collection, indexes and TTL field of a struct
+func buildStruct(s model.TaggedStruct) (string, []model.Index, string, error) {
+	type member struct {
+		position int
+		field    model.IndexField
+		group    bool
+		source   string
+	}
+
+	var collection, ttl string
+	var order []string
+	members := make(map[string][]member)
+	var indexes []model.Index
+
+	for _, f := range s.Fields {
+		tag, err := parseFieldTag(f.Tag)
+		if err != nil {
+			return "", nil, "", goerr.Wrap(err, "invalid fireconf tag", goerr.V("field", f.Source))
+		}
+
+		if tag.collection != "" {
+			if collection != "" && collection != tag.collection {
+				return "", nil, "", goerr.New("conflicting collection names",
+					goerr.V("type", s.Name), goerr.V("collections", []string{collection, tag.collection}))
+			}
+			collection = tag.collection
+		}
+
+		if (tag.ttl || tag.vector > 0 || len(tag.indexes) > 0) && f.Path == "" {
+			return "", nil, "", goerr.New("index and ttl directives require a document field", goerr.V("field", f.Source))
+		}
+
+		if tag.ttl {
+			if ttl != "" {
+				return "", nil, "", goerr.New("only one TTL field is allowed per collection",
+					goerr.V("type", s.Name), goerr.V("fields", []string{ttl, f.Path}))
+			}
+			ttl = f.Path
+		}
+
+		if tag.vector > 0 && len(tag.indexes) == 0 {
+			indexes = append(indexes, model.Index{
+				QueryScope: "COLLECTION",
+				Fields: []model.IndexField{
+					{Name: f.Path, VectorConfig: &model.VectorConfig{Dimension: tag.vector}},
+				},
+			})
+		}
+
+		for _, index := range tag.indexes {
+			field := model.IndexField{Name: f.Path}
+			switch {
+			case tag.vector > 0:
+				if index.contains {
+					return "", nil, "", goerr.New("vector field cannot be an array-contains field", goerr.V("field", f.Source))
+				}
+				field.VectorConfig = &model.VectorConfig{Dimension: tag.vector}
+			case index.contains:
+				field.ArrayConfig = "CONTAINS"
+			default:
+				field.Order = index.order
+			}
+
+			if _, ok := members[index.name]; !ok {
+				order = append(order, index.name)
+			}
+			members[index.name] = append(members[index.name], member{
+				position: index.position,
+				field:    field,
+				group:    index.group,
+				source:   f.Source,
+			})
+		}
+	}
+
+	if collection == "" {
+		return "", nil, "", goerr.New("struct has no collection directive, add a field such as `_ struct{} `fireconf:\"collection=NAME\"``",
+			goerr.V("type", s.Name))
+	}
+
+	for _, name := range order {
+		list := members[name]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].position < list[j].position })
+
+		index := model.Index{QueryScope: "COLLECTION"}
+		for i, m := range list {
+			if i > 0 && list[i-1].position == m.position {
+				return "", nil, "", goerr.New("duplicate index position",
+					goerr.V("index", name), goerr.V("position", m.position), goerr.V("field", m.source))
+			}
+			if m.group {
+				index.QueryScope = "COLLECTION_GROUP"
+			}
+			index.Fields = append(index.Fields, m.field)
+		}
+		if len(index.Fields) == 1 && index.Fields[0].VectorConfig == nil {
+			return "", nil, "", goerr.New("composite index needs at least two fields, single-field indexes are created automatically",
+				goerr.V("index", name), goerr.V("field", list[0].source))
+		}
+		indexes = append(indexes, index)
+	}
+
+	return collection, indexes, ttl, nil
+}
+
+// containsIndex reports whether the index is already in the list
+func containsIndex(indexes []model.Index, index model.Index) bool {
+	for _, existing := range indexes {
+		if SameIndex(existing, index) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDocumentStruct reports whether the struct has a collection directive.
+// Tagged structs without one are parts of documents, used as nested fields.
+func IsDocumentStruct(s model.TaggedStruct) bool {
+	for _, f := range s.Fields {
+		if tag, err := parseFieldTag(f.Tag); err == nil && tag.collection != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -26,12 +27,85 @@ func SyncWithAsync() SyncOption {
 	return func(s *Sync) { s.async = true }
 }
 
+// SyncWithCreateBeforeDelete creates new indexes and waits for them to
+// become READY before deleting stale indexes, so that queries served by a
+// replaced index keep working while its replacement is built
+func SyncWithCreateBeforeDelete() SyncOption {
+	return func(s *Sync) { s.createBeforeDelete = true }
+}
+
+// SyncWithRepair recreates existing indexes that match the configuration but
+// are in ERROR or NEEDS_REPAIR state. Each index is recreated at most
+// maxAttempts times while it keeps failing to build.
+func SyncWithRepair(maxAttempts int) SyncOption {
+	return func(s *Sync) { s.repairAttempts = maxAttempts }
+}
+
+// SyncWithWaitTTL waits for TTL policies to become ACTIVE after enabling
+// them, and to be cleared after disabling them, instead of returning once the
+// change is submitted
+func SyncWithWaitTTL() SyncOption {
+	return func(s *Sync) { s.waitTTL = true }
+}
+
+// SyncWithObserver delivers progress events to the observer. Calls are
+// serialized, so the observer does not need to be safe for concurrent use.
+func SyncWithObserver(observer func(model.Event)) SyncOption {
+	return func(s *Sync) { s.observer = observer }
+}
+
+// SyncWithContinueOnError processes every collection even if others fail,
+// instead of canceling the remaining collections on the first failure. The
+// failures are returned together as *SyncErrors.
+func SyncWithContinueOnError() SyncOption {
+	return func(s *Sync) { s.continueOnError = true }
+}
+
+// SyncWithClock replaces the clock used while waiting, e.g. in tests
+func SyncWithClock(clock Clock) SyncOption {
+	return func(s *Sync) { s.clock = clock }
+}
+
+// SyncWithWaitStrategy configures polling and timeouts while waiting for
+// indexes, index deletions and TTL policies
+func SyncWithWaitStrategy(strategy WaitStrategy) SyncOption {
+	return func(s *Sync) { s.waitStrategy = strategy }
+}
+
+// SyncWithFieldIndexStore records the single-field index configuration of
+// fields before they are exempted from indexing for a TTL policy, and
+// restores the recorded configuration instead of the database defaults when
+// the exemption is removed
+func SyncWithFieldIndexStore(store interfaces.FieldIndexStore) SyncOption {
+	return func(s *Sync) { s.fieldIndexStore = store }
+}
+
 // Sync handles synchronization of Firestore configuration
 type Sync struct {
-	client interfaces.FirestoreClient
-	logger *slog.Logger
-	dryRun bool
-	async  bool
+	client             interfaces.FirestoreClient
+	logger             *slog.Logger
+	dryRun             bool
+	async              bool
+	createBeforeDelete bool
+	repairAttempts     int
+	waitTTL            bool
+	continueOnError    bool
+	observer           func(model.Event)
+	observerMu         sync.Mutex
+	clock              Clock
+	waitStrategy       WaitStrategy
+	fieldIndexStore    interfaces.FieldIndexStore
+}
+
+// syncRun is the state of a single ExecuteWithResult. Helpers of a run are
+// its methods, so that concurrent runs of one Sync do not share state.
+type syncRun struct {
+	*Sync
+	// recorder collects the result of the run
+	recorder *syncRecorder
+	// deadline is the end of the WaitStrategy deadline of the run, zero if
+	// there is none
+	deadline time.Time
 }
 
 // NewSync creates a new Sync use case
@@ -39,77 +113,166 @@ func NewSync(client interfaces.FirestoreClient, logger *slog.Logger, opts ...Syn
 	s := &Sync{
 		client: client,
 		logger: logger,
+		clock:  realClock{},
 	}
 	for _, opt := range opts {
 		opt(s)
 	}
+	s.waitStrategy = s.waitStrategy.withDefaults(DefaultWaitStrategy())
 	return s
 }
 
+// emit delivers an event to the observer, if any
+func (s *Sync) emit(event model.Event) {
+	if s.observer == nil {
+		return
+	}
+	s.observerMu.Lock()
+	defer s.observerMu.Unlock()
+	s.observer(event)
+}
+
 // Execute synchronizes the configuration
 func (s *Sync) Execute(ctx context.Context, config *model.Config) error {
+	_, err := s.ExecuteWithResult(ctx, config)
+	return err
+}
+
+// ExecuteWithResult synchronizes the configuration and returns what was done
+// for each collection. The result is returned also on error and covers the
+// collections processed until then. A Sync may run several Executes
+// concurrently.
+func (s *Sync) ExecuteWithResult(ctx context.Context, config *model.Config) (*SyncResult, error) {
 	s.logger.Info("Starting sync operation", slog.Bool("dryRun", s.dryRun))
 
-	// Process collections in parallel
-	g, ctx := errgroup.WithContext(ctx)
+	start := s.clock.Now()
+	run := &syncRun{Sync: s, recorder: newSyncRecorder()}
+	if deadline := s.waitStrategy.Deadline; deadline > 0 {
+		run.deadline = start.Add(deadline)
+	}
+	result := func() *SyncResult {
+		return &SyncResult{
+			DryRun:      s.dryRun,
+			Collections: run.recorder.result(config),
+			Duration:    s.clock.Now().Sub(start),
+		}
+	}
 
-	// Limit concurrent collection processing
-	sem := make(chan struct{}, 10) // Process up to 10 collections concurrently
+	// Process collections in parallel. By default the first failure cancels
+	// the other collections.
+	g := new(errgroup.Group)
+	gctx := ctx
+	if !s.continueOnError {
+		g, gctx = errgroup.WithContext(ctx)
+	}
 
+	// Collections are not limited here: the concurrency and rate of their
+	// Firestore calls are bounded by the client, which shares one pool among
+	// all calls
 	for _, collection := range config.Collections {
 		collection := collection // capture
 
 		g.Go(func() error {
-			// Acquire semaphore
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
 			s.logger.Info("Processing collection", slog.String("name", collection.Name))
-
-			// Validate collection
-			if err := collection.Validate(); err != nil {
-				return goerr.Wrap(err, "invalid collection configuration", goerr.V("collection", collection.Name))
-			}
-
-			// Ensure collection exists before processing indexes/TTL
-			if err := s.ensureCollectionExists(ctx, collection.Name); err != nil {
-				return goerr.Wrap(err, "failed to ensure collection exists", goerr.V("collection", collection.Name))
-			}
-
-			// Sync indexes and TTL in parallel (they are independent)
-			cg, cctx := errgroup.WithContext(ctx)
-			cg.Go(func() error {
-				if err := s.syncIndexes(cctx, collection); err != nil {
-					return goerr.Wrap(err, "failed to sync indexes", goerr.V("collection", collection.Name))
-				}
-				return nil
-			})
-			cg.Go(func() error {
-				if err := s.syncTTL(cctx, collection); err != nil {
-					return goerr.Wrap(err, "failed to sync TTL", goerr.V("collection", collection.Name))
+			s.emit(model.CollectionStarted{Collection: collection.Name})
+			collectionStart := s.clock.Now()
+
+			if err := run.syncCollection(gctx, collection); err != nil {
+				run.recorder.finished(collection.Name, s.clock.Now().Sub(collectionStart), err)
+				s.emit(model.ErrorOccurred{Collection: collection.Name, Err: err})
+				s.emit(model.CollectionFinished{Collection: collection.Name, Err: err})
+				if s.continueOnError {
+					s.logger.Error("Collection processing failed",
+						slog.String("name", collection.Name),
+						slog.Any("error", err))
+					return nil
 				}
-				return nil
-			})
-			if err := cg.Wait(); err != nil {
 				return err
 			}
 
+			run.recorder.finished(collection.Name, s.clock.Now().Sub(collectionStart), nil)
 			s.logger.Info("Collection processing completed", slog.String("name", collection.Name))
+			s.emit(model.CollectionFinished{Collection: collection.Name})
 			return nil
 		})
 	}
 
 	// Wait for all collections to complete
 	if err := g.Wait(); err != nil {
-		return err
+		return result(), err
+	}
+
+	if s.continueOnError {
+		res := result()
+		var errs []*CollectionError
+		for _, col := range res.Collections {
+			if col.Err == nil {
+				continue
+			}
+			var collErr *CollectionError
+			if !errors.As(col.Err, &collErr) {
+				collErr = &CollectionError{Collection: col.Name, Operation: "sync", Err: col.Err}
+			}
+			errs = append(errs, collErr)
+		}
+		if len(errs) > 0 {
+			return res, &SyncErrors{Errors: errs}
+		}
+		return res, nil
 	}
 
 	s.logger.Info("Sync operation completed successfully")
-	return nil
+	return result(), nil
+}
+
+// syncCollection validates a collection, ensures it exists and synchronizes
+// its indexes and TTL policy
+func (s *syncRun) syncCollection(ctx context.Context, collection model.Collection) error {
+	// Validate collection
+	if err := collection.Validate(); err != nil {
+		return &CollectionError{
+			Collection: collection.Name,
+			Operation:  "validate",
+			Err:        goerr.Wrap(err, "invalid collection configuration", goerr.V("collection", collection.Name)),
+		}
+	}
+
+	// Ensure collection exists before processing indexes/TTL
+	if err := s.ensureCollectionExists(ctx, collection.Name); err != nil {
+		return &CollectionError{
+			Collection: collection.Name,
+			Operation:  "ensure collection",
+			Err:        goerr.Wrap(err, "failed to ensure collection exists", goerr.V("collection", collection.Name)),
+		}
+	}
+
+	// Sync indexes and TTL in parallel (they are independent)
+	cg, cctx := errgroup.WithContext(ctx)
+	cg.Go(func() error {
+		if err := s.syncIndexes(cctx, collection); err != nil {
+			return &CollectionError{
+				Collection: collection.Name,
+				Operation:  "sync indexes",
+				Err:        goerr.Wrap(err, "failed to sync indexes", goerr.V("collection", collection.Name)),
+			}
+		}
+		return nil
+	})
+	cg.Go(func() error {
+		if err := s.syncTTL(cctx, collection); err != nil {
+			return &CollectionError{
+				Collection: collection.Name,
+				Operation:  "sync TTL",
+				Err:        goerr.Wrap(err, "failed to sync TTL", goerr.V("collection", collection.Name)),
+			}
+		}
+		return nil
+	})
+	return cg.Wait()
 }
 
 // syncIndexes synchronizes indexes for a collection
-func (s *Sync) syncIndexes(ctx context.Context, collection model.Collection) error {
+func (s *syncRun) syncIndexes(ctx context.Context, collection model.Collection) error {
 	// Get existing indexes
 	existing, err := s.client.ListIndexes(ctx, collection.Name)
 	if err != nil {
@@ -122,13 +285,15 @@ func (s *Sync) syncIndexes(ctx context.Context, collection model.Collection) err
 
 	// Calculate diff
 	toCreate, toDelete := DiffIndexes(collection.Indexes, existing)
+	unhealthy := FindUnhealthyIndexes(collection.Indexes, existing)
 
 	s.logger.Info("Index diff calculated",
 		slog.String("collection", collection.Name),
 		slog.Int("desired", len(collection.Indexes)),
 		slog.Int("existing", len(existing)),
 		slog.Int("toCreate", len(toCreate)),
-		slog.Int("toDelete", len(toDelete)))
+		slog.Int("toDelete", len(toDelete)),
+		slog.Int("unhealthy", len(unhealthy)))
 
 	// Debug: Log detailed index information
 	if s.logger.Enabled(context.Background(), slog.LevelDebug) {
@@ -176,71 +341,307 @@ func (s *Sync) syncIndexes(ctx context.Context, collection model.Collection) err
 		}
 	}
 
-	// Delete indexes that are no longer needed
-	for _, idx := range toDelete {
+	// Report indexes replaced by an index on the same fields as modifications
+	replacements, _, _ := PairReplacements(toCreate, toDelete)
+	for _, r := range replacements {
+		msg := "Replacing index"
 		if s.dryRun {
-			s.logger.Info("Would delete index",
-				slog.String("collection", collection.Name),
-				slog.String("index", idx.Name))
-			continue
+			msg = "Would replace index"
 		}
-
-		s.logger.Info("Deleting index",
+		s.logger.Info(msg,
 			slog.String("collection", collection.Name),
-			slog.String("index", idx.Name))
+			slog.String("index", r.Old.Name),
+			slog.Any("oldFields", r.Old.Fields),
+			slog.String("oldQueryScope", r.Old.QueryScope),
+			slog.Any("newFields", r.New.Fields),
+			slog.String("newQueryScope", r.New.QueryScope),
+			slog.Bool("createBeforeDelete", s.createBeforeDelete))
+	}
 
-		op, err := s.client.DeleteIndex(ctx, idx.Name)
-		if err != nil {
-			return goerr.Wrap(err, "failed to delete index", goerr.V("index", idx.Name))
+	for _, idx := range existing {
+		if containsFirestoreIndex(toDelete, idx) || (s.repairAttempts > 0 && containsRepair(unhealthy, idx)) {
+			continue
 		}
+		s.recorder.unchanged(collection.Name, IndexResult{
+			Index: NormalizeFirestoreIndex(idx),
+			Name:  idx.Name,
+			State: idx.State,
+		})
+	}
 
-		if !s.async && op != nil {
-			s.logger.Info("Waiting for index deletion to complete",
-				slog.String("collection", collection.Name),
-				slog.String("index", idx.Name))
-
-			progressLogger := func(elapsed time.Duration) {
-				s.logger.Info("Still waiting for index deletion...",
-					slog.String("collection", collection.Name),
-					slog.String("index", idx.Name),
-					slog.Duration("elapsed", elapsed))
-			}
+	// Repairs are independent of the other changes; run them alongside so
+	// that a slow rebuild does not hold back the rest of the collection
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return s.applyIndexChanges(gctx, collection.Name, existing, toCreate, toDelete)
+	})
+	g.Go(func() error {
+		return s.repairIndexes(gctx, collection.Name, unhealthy)
+	})
+	return g.Wait()
+}
 
-			if err := s.waitForOperationWithProgress(ctx, op, progressLogger); err != nil {
-				return goerr.Wrap(err, "failed to wait for index deletion", goerr.V("index", idx.Name))
-			}
+// applyIndexChanges deletes stale indexes and creates missing ones, in the
+// order selected by the createBeforeDelete option
+func (s *syncRun) applyIndexChanges(ctx context.Context, collectionName string, existing, toCreate, toDelete []interfaces.FirestoreIndex) error {
+	if !s.createBeforeDelete {
+		// Delete indexes that are no longer needed
+		if err := s.deleteIndexes(ctx, collectionName, toDelete); err != nil {
+			return err
 		}
 	}
 
 	// Create new indexes and collect the created index names
 	var createdIndexNames []string
 	if len(toCreate) > 0 {
-		names, err := s.createIndexesConcurrently(ctx, collection.Name, toCreate)
+		names, err := s.createIndexesConcurrently(ctx, collectionName, toCreate)
 		if err != nil {
 			return err
 		}
 		createdIndexNames = names
 	}
 
-	// Wait for the newly created indexes to reach READY state by polling each by name
-	if err := s.waitForIndexesReady(ctx, createdIndexNames); err != nil {
+	if !s.createBeforeDelete {
+		// Wait for the newly created indexes to reach READY state by polling each by name
+		if err := s.waitForIndexesReady(ctx, collectionName, createdIndexNames); err != nil {
+			return goerr.Wrap(err, "failed to wait for indexes to become ready")
+		}
+		return nil
+	}
+
+	// Desired indexes that already exist but are still being built (e.g.
+	// submitted by a previous async run) must be READY before the indexes
+	// they replace are deleted
+	for _, idx := range existing {
+		if len(toDelete) > 0 && idx.State == "CREATING" && !containsFirestoreIndex(toDelete, idx) {
+			createdIndexNames = append(createdIndexNames, idx.Name)
+		}
+	}
+
+	if s.async && !s.dryRun && len(createdIndexNames) > 0 && len(toDelete) > 0 {
+		s.logger.Warn("Not deleting stale indexes until their replacements are READY; run sync again once they are",
+			slog.String("collection", collectionName),
+			slog.Int("building", len(createdIndexNames)),
+			slog.Int("toDelete", len(toDelete)))
+		return nil
+	}
+
+	if err := s.waitForIndexesReady(ctx, collectionName, createdIndexNames); err != nil {
 		return goerr.Wrap(err, "failed to wait for indexes to become ready")
 	}
 
+	return s.deleteIndexes(ctx, collectionName, toDelete)
+}
+
+// deleteIndexes deletes stale indexes, waiting for each deletion to complete
+// unless running asynchronously
+func (s *syncRun) deleteIndexes(ctx context.Context, collectionName string, indexes []interfaces.FirestoreIndex) error {
+	for _, idx := range indexes {
+		if err := s.deleteIndex(ctx, collectionName, idx); err != nil {
+			return err
+		}
+		s.recorder.deleted(collectionName, IndexResult{
+			Index: NormalizeFirestoreIndex(idx),
+			Name:  idx.Name,
+			State: idx.State,
+		})
+	}
+	return nil
+}
+
+// deleteIndex deletes an index, waiting for the deletion to complete unless
+// running asynchronously
+func (s *syncRun) deleteIndex(ctx context.Context, collectionName string, idx interfaces.FirestoreIndex) error {
+	if s.dryRun {
+		s.logger.Info("Would delete index",
+			slog.String("collection", collectionName),
+			slog.String("index", idx.Name))
+		return nil
+	}
+
+	s.logger.Info("Deleting index",
+		slog.String("collection", collectionName),
+		slog.String("index", idx.Name))
+
+	submitCtx, cancel, err := s.submitContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	op, err := s.client.DeleteIndex(submitCtx, idx.Name)
+	if err != nil {
+		return goerr.Wrap(err, "failed to delete index", goerr.V("index", idx.Name))
+	}
+	s.emit(model.IndexDeleteSubmitted{
+		Collection: collectionName,
+		Index:      NormalizeFirestoreIndex(idx),
+		Name:       idx.Name,
+	})
+
+	if !s.async && op != nil {
+		s.logger.Info("Waiting for index deletion to complete",
+			slog.String("collection", collectionName),
+			slog.String("index", idx.Name))
+
+		progressLogger := func(elapsed time.Duration) {
+			s.logger.Info("Still waiting for index deletion...",
+				slog.String("collection", collectionName),
+				slog.String("index", idx.Name),
+				slog.Duration("elapsed", elapsed))
+			s.emit(model.WaitingTick{
+				Collection: collectionName,
+				Kind:       model.WaitIndexDeletion,
+				Target:     idx.Name,
+				Elapsed:    elapsed,
+			})
+		}
+
+		if err := s.waitForOperationWithProgress(ctx, op, progressLogger); err != nil {
+			return goerr.Wrap(err, "failed to wait for index deletion", goerr.V("index", idx.Name))
+		}
+	}
 	return nil
 }
 
+// repairIndexes recreates unhealthy indexes when repair is enabled, and
+// otherwise only reports them
+func (s *syncRun) repairIndexes(ctx context.Context, collectionName string, repairs []IndexRepair) error {
+	if s.repairAttempts <= 0 {
+		for _, r := range repairs {
+			s.logger.Warn("Index is unhealthy and will not serve queries; enable repair to recreate it",
+				slog.String("collection", collectionName),
+				slog.String("index", r.Existing.Name),
+				slog.String("state", r.Existing.State))
+		}
+		return nil
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, r := range repairs {
+		r := r
+		g.Go(func() error {
+			return s.repairIndex(ctx, collectionName, r)
+		})
+	}
+	return g.Wait()
+}
+
+// repairIndex deletes an unhealthy index and recreates it, retrying while the
+// recreated index fails to build
+func (s *syncRun) repairIndex(ctx context.Context, collectionName string, repair IndexRepair) error {
+	if s.dryRun {
+		s.logger.Info("Would repair index",
+			slog.String("collection", collectionName),
+			slog.String("index", repair.Existing.Name),
+			slog.String("state", repair.Existing.State))
+		s.recorder.repaired(collectionName, IndexResult{Index: NormalizeFirestoreIndex(repair.Desired)})
+		return nil
+	}
+
+	current := repair.Existing
+	var lastErr error
+	for attempt := 1; attempt <= s.repairAttempts; attempt++ {
+		s.logger.Info("Repairing index",
+			slog.String("collection", collectionName),
+			slog.String("index", current.Name),
+			slog.String("state", current.State),
+			slog.Int("attempt", attempt),
+			slog.Int("maxAttempts", s.repairAttempts))
+
+		if err := s.deleteIndex(ctx, collectionName, current); err != nil {
+			return goerr.Wrap(err, "failed to delete unhealthy index", goerr.V("index", current.Name))
+		}
+
+		name, err := s.createIndex(ctx, collectionName, repair.Desired)
+		if err != nil {
+			return goerr.Wrap(err, "failed to recreate unhealthy index",
+				goerr.V("collection", collectionName),
+				goerr.V("fields", repair.Desired.Fields))
+		}
+		s.emit(model.IndexCreateSubmitted{
+			Collection: collectionName,
+			Index:      NormalizeFirestoreIndex(repair.Desired),
+			Name:       name,
+		})
+
+		if s.async {
+			s.logger.Info("Recreated unhealthy index without waiting for it to build",
+				slog.String("collection", collectionName),
+				slog.String("index", name),
+				slog.String("previous", repair.Existing.Name))
+			s.recorder.repaired(collectionName, IndexResult{
+				Index: NormalizeFirestoreIndex(repair.Desired),
+				Name:  name,
+				State: "CREATING",
+			})
+			return nil
+		}
+
+		if err := s.waitForIndexesReady(ctx, collectionName, []string{name}); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, model.ErrWaitTimeout) {
+				// Still building, recreating it would start over
+				return err
+			}
+			lastErr = err
+			s.logger.Warn("Recreated index failed to build",
+				slog.String("collection", collectionName),
+				slog.String("index", name),
+				slog.Int("attempt", attempt),
+				slog.Any("error", err))
+			current = interfaces.FirestoreIndex{Name: name}
+			continue
+		}
+
+		s.logger.Info("Index repaired",
+			slog.String("collection", collectionName),
+			slog.String("index", name),
+			slog.String("previous", repair.Existing.Name),
+			slog.Int("attempts", attempt))
+		s.recorder.repaired(collectionName, IndexResult{
+			Index: NormalizeFirestoreIndex(repair.Desired),
+			Name:  name,
+			State: "READY",
+		})
+		return nil
+	}
+
+	return goerr.Wrap(lastErr, "failed to repair index",
+		goerr.V("collection", collectionName),
+		goerr.V("index", repair.Existing.Name),
+		goerr.V("attempts", s.repairAttempts))
+}
+
+// containsRepair reports whether the index is the existing index of a repair
+func containsRepair(repairs []IndexRepair, idx interfaces.FirestoreIndex) bool {
+	for _, r := range repairs {
+		if r.Existing.Name == idx.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFirestoreIndex reports whether the list contains the index by name
+func containsFirestoreIndex(indexes []interfaces.FirestoreIndex, idx interfaces.FirestoreIndex) bool {
+	for _, i := range indexes {
+		if i.Name == idx.Name {
+			return true
+		}
+	}
+	return false
+}
+
 // createIndexesConcurrently creates multiple indexes in parallel and returns their resource names.
 // Index creation requests are submitted concurrently without waiting for each
 // individual LRO to complete. The caller is responsible for waiting via
 // waitForIndexesReady, which polls each index by name directly.
 // This avoids potential hangs in the Firestore LRO polling mechanism.
-func (s *Sync) createIndexesConcurrently(ctx context.Context, collectionName string, indexes []interfaces.FirestoreIndex) ([]string, error) {
+func (s *syncRun) createIndexesConcurrently(ctx context.Context, collectionName string, indexes []interfaces.FirestoreIndex) ([]string, error) {
 	g, ctx := errgroup.WithContext(ctx)
 
-	// Limit concurrent operations
-	sem := make(chan struct{}, 5)
-
 	var mu sync.Mutex
 	var createdNames []string
 
@@ -248,14 +649,12 @@ func (s *Sync) createIndexesConcurrently(ctx context.Context, collectionName str
 		idx := idx // capture
 
 		g.Go(func() error {
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
 			if s.dryRun {
 				s.logger.Info("Would create index",
 					slog.String("collection", collectionName),
 					slog.Any("fields", idx.Fields),
 					slog.String("queryScope", idx.QueryScope))
+				s.recorder.created(collectionName, IndexResult{Index: NormalizeFirestoreIndex(idx)})
 				return nil
 			}
 
@@ -264,12 +663,22 @@ func (s *Sync) createIndexesConcurrently(ctx context.Context, collectionName str
 				slog.Any("fields", idx.Fields),
 				slog.String("queryScope", idx.QueryScope))
 
-			name, err := s.client.CreateIndex(ctx, collectionName, idx)
+			name, err := s.createIndex(ctx, collectionName, idx)
 			if err != nil {
 				return goerr.Wrap(err, "failed to create index",
 					goerr.V("collection", collectionName),
 					goerr.V("fields", idx.Fields))
 			}
+			s.emit(model.IndexCreateSubmitted{
+				Collection: collectionName,
+				Index:      NormalizeFirestoreIndex(idx),
+				Name:       name,
+			})
+			s.recorder.created(collectionName, IndexResult{
+				Index: NormalizeFirestoreIndex(idx),
+				Name:  name,
+				State: "CREATING",
+			})
 
 			if name != "" {
 				mu.Lock()
@@ -287,72 +696,426 @@ func (s *Sync) createIndexesConcurrently(ctx context.Context, collectionName str
 	return createdNames, nil
 }
 
+// createIndex submits the creation of an index and returns its resource
+// name. An identical index that already exists, e.g. created by a concurrent
+// run, is resolved so that it is waited for like a new one.
+func (s *syncRun) createIndex(ctx context.Context, collectionName string, idx interfaces.FirestoreIndex) (string, error) {
+	submitCtx, cancel, err := s.submitContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cancel()
+
+	name, err := s.client.CreateIndex(submitCtx, collectionName, idx)
+	if err == nil || !errors.Is(err, model.ErrIndexAlreadyExists) {
+		return name, err
+	}
+
+	existing, listErr := s.client.ListIndexes(ctx, collectionName)
+	if listErr != nil {
+		return "", goerr.Wrap(listErr, "failed to resolve existing index", goerr.V("collection", collectionName))
+	}
+	key := getIndexKey(idx)
+	for _, e := range existing {
+		if getIndexKey(e) == key {
+			s.logger.Info("Index already exists, waiting for it",
+				slog.String("collection", collectionName),
+				slog.String("index", e.Name),
+				slog.String("state", e.State))
+			return e.Name, nil
+		}
+	}
+	return "", goerr.Wrap(err, "index already exists but was not found", goerr.V("collection", collectionName))
+}
+
 // syncTTL synchronizes TTL policy for a collection.
 // TTL operations are submitted as fire-and-forget since op.Wait() hangs indefinitely
-// for Firestore UpdateField LROs. TTL changes are applied asynchronously by Firestore.
-func (s *Sync) syncTTL(ctx context.Context, collection model.Collection) error {
+// for Firestore UpdateField LROs. TTL changes are applied asynchronously by Firestore
+// unless waitTTL is set, in which case the TTL policy is polled until it is in effect.
+//
+// The TTL field is exempted from single-field indexing unless configured
+// otherwise, and indexing is restored on a field whose TTL policy is removed.
+// Fields recorded in the field index store are restored even if their
+// policy was removed earlier or outside fireconf; without a record, an
+// exemption left by such a policy cannot be told apart from one configured
+// by hand and is left as it is.
+func (s *syncRun) syncTTL(ctx context.Context, collection model.Collection) error {
+	liveField, err := s.client.FindTTLField(ctx, collection.Name)
+	if err != nil {
+		return goerr.Wrap(err, "failed to find TTL field")
+	}
+
+	if err := s.restoreStaleExemptions(ctx, collection, liveField); err != nil {
+		return err
+	}
+
 	if collection.TTL == nil {
-		if s.dryRun {
-			s.logger.Info("Would check and disable TTL if exists",
-				slog.String("collection", collection.Name))
+		if liveField == "" {
+			s.logger.Debug("No TTL policy to disable", slog.String("collection", collection.Name))
 			return nil
 		}
-		if _, err := s.client.DisableTTLPolicy(ctx, collection.Name); err != nil {
-			return goerr.Wrap(err, "failed to disable TTL policy")
-		}
-		return nil
+		return s.disableTTL(ctx, collection.Name, liveField)
 	}
 
 	existing, err := s.client.GetTTLPolicy(ctx, collection.Name, collection.TTL.Field)
 	if err != nil {
 		return goerr.Wrap(err, "failed to get TTL policy")
 	}
+	if liveField != "" && liveField != collection.TTL.Field {
+		// The policy is on another field and has to be moved
+		existing, err = s.client.GetTTLPolicy(ctx, collection.Name, liveField)
+		if err != nil {
+			return goerr.Wrap(err, "failed to get TTL policy", goerr.V("field", liveField))
+		}
+	}
 
 	needsUpdate, action := DiffTTL(collection.TTL, existing)
 	if !needsUpdate {
 		s.logger.Debug("TTL policy is up to date",
 			slog.String("collection", collection.Name),
 			slog.String("field", collection.TTL.Field))
+		if existing != nil {
+			s.recorder.ttl(collection.Name, "", collection.TTL.Field, existing.State)
+		}
+		if err := s.syncTTLIndexExemption(ctx, collection.Name, collection.TTL); err != nil {
+			return err
+		}
+		// A policy enabled by a previous run may still be being applied
+		if s.shouldWaitTTL() && existing != nil && existing.State != "ACTIVE" {
+			return s.waitForTTLPolicy(ctx, collection.Name, collection.TTL.Field, true)
+		}
 		return nil
 	}
 
+	if action == "change" {
+		if err := s.disableTTL(ctx, collection.Name, existing.FieldPath); err != nil {
+			return err
+		}
+	}
+
+	if err := s.syncTTLIndexExemption(ctx, collection.Name, collection.TTL); err != nil {
+		return err
+	}
+
 	if s.dryRun {
-		s.logger.Info(fmt.Sprintf("Would %s TTL policy", action),
+		s.logger.Info("Would enable TTL policy",
 			slog.String("collection", collection.Name),
 			slog.String("field", collection.TTL.Field))
+		s.recorder.ttl(collection.Name, "enable", collection.TTL.Field, "")
 		return nil
 	}
 
-	switch action {
-	case "enable":
-		s.logger.Info("Enabling TTL policy",
-			slog.String("collection", collection.Name),
-			slog.String("field", collection.TTL.Field))
-		if _, err := s.client.EnableTTLPolicy(ctx, collection.Name, collection.TTL.Field); err != nil {
-			return goerr.Wrap(err, "failed to enable TTL policy")
+	s.logger.Info("Enabling TTL policy",
+		slog.String("collection", collection.Name),
+		slog.String("field", collection.TTL.Field))
+	submitCtx, cancel, err := s.submitContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	if _, err := s.client.EnableTTLPolicy(submitCtx, collection.Name, collection.TTL.Field); err != nil {
+		return goerr.Wrap(err, "failed to enable TTL policy")
+	}
+	s.emit(model.TTLChanged{Collection: collection.Name, Field: collection.TTL.Field, Action: "enable"})
+	s.recorder.ttl(collection.Name, "enable", collection.TTL.Field, "CREATING")
+
+	if s.shouldWaitTTL() {
+		return s.waitForTTLPolicy(ctx, collection.Name, collection.TTL.Field, true)
+	}
+	return nil
+}
+
+// disableTTL disables the TTL policy on a field and restores single-field
+// indexing on it
+func (s *syncRun) disableTTL(ctx context.Context, collectionName, field string) error {
+	if s.dryRun {
+		s.logger.Info("Would disable TTL policy",
+			slog.String("collection", collectionName),
+			slog.String("field", field))
+		s.recorder.ttl(collectionName, "disable", field, "")
+		return s.restoreFieldIndex(ctx, collectionName, field)
+	}
+
+	s.logger.Info("Disabling TTL policy",
+		slog.String("collection", collectionName),
+		slog.String("field", field))
+	submitCtx, cancel, err := s.submitContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	if _, err := s.client.DisableTTLPolicy(submitCtx, collectionName); err != nil {
+		return goerr.Wrap(err, "failed to disable TTL policy", goerr.V("field", field))
+	}
+	s.emit(model.TTLChanged{Collection: collectionName, Field: field, Action: "disable"})
+	s.recorder.ttl(collectionName, "disable", field, "")
+
+	if err := s.restoreFieldIndex(ctx, collectionName, field); err != nil {
+		return err
+	}
+
+	if s.shouldWaitTTL() {
+		return s.waitForTTLPolicy(ctx, collectionName, field, false)
+	}
+	return nil
+}
+
+// syncTTLIndexExemption exempts the TTL field from single-field indexing or
+// restores its indexing, as configured
+func (s *syncRun) syncTTLIndexExemption(ctx context.Context, collectionName string, ttl *model.TTL) error {
+	current, err := s.client.GetFieldIndexConfig(ctx, collectionName, ttl.Field)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get field index config", goerr.V("field", ttl.Field))
+	}
+
+	if !ttl.ExemptsIndex() {
+		if isIndexExemption(current) {
+			return s.restoreFieldIndex(ctx, collectionName, ttl.Field)
 		}
+		return nil
+	}
 
-	case "change":
-		s.logger.Info("Changing TTL field, disabling old policy",
-			slog.String("collection", collection.Name))
-		if _, err := s.client.DisableTTLPolicy(ctx, collection.Name); err != nil {
-			return goerr.Wrap(err, "failed to disable old TTL policy")
+	if isIndexExemption(current) {
+		return nil
+	}
+
+	if s.dryRun {
+		s.logger.Info("Would exempt TTL field from single-field indexing",
+			slog.String("collection", collectionName),
+			slog.String("field", ttl.Field),
+			slog.Any("previous", current))
+		return nil
+	}
+
+	// The previous configuration is logged since it is replaced, and
+	// recorded in the field index store, if any, to be restored later; it
+	// is the database defaults unless the field was configured by hand
+	s.logger.Info("Exempting TTL field from single-field indexing",
+		slog.String("collection", collectionName),
+		slog.String("field", ttl.Field),
+		slog.Any("previous", current))
+
+	// Fire-and-forget: the exemption prevents hotspots and does not need
+	// to be in effect before the TTL policy is enabled
+	submitCtx, cancel, err := s.submitContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	if _, err := s.client.UpdateFieldIndexConfig(submitCtx, collectionName, ttl.Field, &interfaces.FirestoreFieldIndexConfig{}); err != nil {
+		return goerr.Wrap(err, "failed to exempt TTL field from indexing", goerr.V("field", ttl.Field))
+	}
+	if s.fieldIndexStore != nil {
+		s.fieldIndexStore.RecordFieldIndexConfig(collectionName, ttl.Field, current)
+	}
+	return nil
+}
+
+// restoreStaleExemptions restores the fields of the collection recorded in
+// the field index store that are neither the live nor the configured TTL
+// field, e.g. because their policy was removed outside fireconf
+func (s *syncRun) restoreStaleExemptions(ctx context.Context, collection model.Collection, liveField string) error {
+	if s.fieldIndexStore == nil {
+		return nil
+	}
+	for _, field := range s.fieldIndexStore.RecordedFields(collection.Name) {
+		if field == liveField || (collection.TTL != nil && field == collection.TTL.Field) {
+			continue
 		}
-		s.logger.Info("Enabling new TTL policy",
-			slog.String("collection", collection.Name),
-			slog.String("field", collection.TTL.Field))
-		if _, err := s.client.EnableTTLPolicy(ctx, collection.Name, collection.TTL.Field); err != nil {
-			return goerr.Wrap(err, "failed to enable new TTL policy")
+		if err := s.restoreFieldIndex(ctx, collection.Name, field); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// restoreFieldIndex reverts a field exempted from single-field indexing to
+// the configuration recorded before the exemption, or to the database
+// defaults if none is recorded. Fields with a configuration of their own
+// other than the exemption are left as they are.
+func (s *syncRun) restoreFieldIndex(ctx context.Context, collectionName, field string) error {
+	current, err := s.client.GetFieldIndexConfig(ctx, collectionName, field)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get field index config", goerr.V("field", field))
+	}
+
+	var previous *interfaces.FirestoreFieldIndexConfig
+	if s.fieldIndexStore != nil {
+		previous = s.fieldIndexStore.PreviousFieldIndexConfig(collectionName, field)
+	}
+	// The database defaults are restored by clearing the configuration
+	restore := previous
+	if restore != nil && restore.UsesAncestorConfig {
+		restore = nil
+	}
+
+	if !isIndexExemption(current) {
+		s.logger.Debug("Field is not exempt from indexing, nothing to restore",
+			slog.String("collection", collectionName),
+			slog.String("field", field))
+		if previous != nil && !s.dryRun {
+			s.fieldIndexStore.ForgetFieldIndexConfig(collectionName, field)
+		}
+		return nil
+	}
+
+	if s.dryRun {
+		s.logger.Info("Would restore single-field indexing",
+			slog.String("collection", collectionName),
+			slog.String("field", field),
+			slog.Any("config", restore))
+		return nil
+	}
 
+	s.logger.Info("Restoring single-field indexing",
+		slog.String("collection", collectionName),
+		slog.String("field", field),
+		slog.Any("config", restore))
+	submitCtx, cancel, err := s.submitContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	if _, err := s.client.UpdateFieldIndexConfig(submitCtx, collectionName, field, restore); err != nil {
+		return goerr.Wrap(err, "failed to restore field indexing", goerr.V("field", field))
+	}
+	if previous != nil {
+		s.fieldIndexStore.ForgetFieldIndexConfig(collectionName, field)
+	}
 	return nil
 }
 
+// isIndexExemption reports whether a field index configuration exempts the
+// field from single-field indexing
+func isIndexExemption(config *interfaces.FirestoreFieldIndexConfig) bool {
+	return config != nil && !config.UsesAncestorConfig && len(config.Indexes) == 0
+}
+
+// submitGrace is how long a submission may continue once ctx is done
+const submitGrace = 10 * time.Second
+
+// submitContext returns the context for submitting an operation and its
+// cancel function. A submission is not sent at all if ctx is already done.
+// Once sent, it is canceled only submitGrace after ctx, so that the request
+// in flight completes and an interrupted sync knows every operation it
+// started, while retries and waits for the call pool do not outlive the
+// interruption for long.
+func (s *Sync) submitContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, goerr.Wrap(err, "stopped before submitting operation")
+	}
+
+	submitCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		select {
+		case <-s.clock.After(submitGrace):
+			cancel()
+		case <-submitCtx.Done():
+		}
+	})
+	return submitCtx, func() {
+		stop()
+		cancel()
+	}, nil
+}
+
+// shouldWaitTTL reports whether TTL changes are waited for
+func (s *Sync) shouldWaitTTL() bool {
+	return s.waitTTL && !s.async && !s.dryRun
+}
+
+// indexProgress returns the build progress of an index, or nil if it is
+// unknown. Failures are only logged since the progress is informational.
+func (s *Sync) indexProgress(ctx context.Context, indexName string) *model.OperationProgress {
+	op, err := s.client.GetIndexOperation(ctx, indexName)
+	if err != nil {
+		s.logger.Debug("Failed to get index build progress",
+			slog.String("index", indexName),
+			slog.Any("error", err))
+		return nil
+	}
+	if op == nil || op.Done {
+		return nil
+	}
+	return &model.OperationProgress{
+		State:              op.State,
+		DocumentsCompleted: op.DocumentsCompleted,
+		DocumentsEstimated: op.DocumentsEstimated,
+		BytesCompleted:     op.BytesCompleted,
+		BytesEstimated:     op.BytesEstimated,
+	}
+}
+
+// waitForTTLPolicy polls the TTL policy of a field until it is ACTIVE, or
+// until it is cleared if active is false. A policy in NEEDS_REPAIR state is
+// an error.
+func (s *syncRun) waitForTTLPolicy(ctx context.Context, collectionName, field string, active bool) error {
+	wait := newWaiter(s.waitStrategy, s.clock, s.deadline)
+	kind := model.WaitTTLActive
+	if !active {
+		kind = model.WaitTTLCleared
+	}
+
+	for {
+		ttl, err := s.client.GetTTLPolicy(ctx, collectionName, field)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.Warn("Failed to get TTL policy while waiting, retrying",
+				slog.String("collection", collectionName),
+				slog.String("field", field),
+				slog.Any("error", err))
+		} else {
+			switch {
+			case ttl != nil && ttl.State == "NEEDS_REPAIR":
+				return goerr.New("TTL policy entered NEEDS_REPAIR state",
+					goerr.V("collection", collectionName),
+					goerr.V("field", field))
+			case active && ttl != nil && ttl.State == "ACTIVE":
+				s.logger.Info("TTL policy is active",
+					slog.String("collection", collectionName),
+					slog.String("field", field))
+				s.recorder.ttlState(collectionName, ttl.State)
+				return nil
+			case !active && ttl == nil:
+				s.logger.Info("TTL policy is cleared",
+					slog.String("collection", collectionName),
+					slog.String("field", field))
+				return nil
+			default:
+				// Not applied yet, keep waiting
+			}
+		}
+
+		if wait.logDue() {
+			msg := "Waiting for TTL policy to become ACTIVE"
+			if !active {
+				msg = "Waiting for TTL policy to be cleared"
+			}
+			s.logger.Info(msg,
+				slog.String("collection", collectionName),
+				slog.String("field", field))
+			s.emit(model.WaitingTick{
+				Collection: collectionName,
+				Kind:       kind,
+				Target:     field,
+				Elapsed:    wait.elapsed(),
+			})
+		}
+
+		if err := wait.pause(ctx); err != nil {
+			return goerr.Wrap(err, "TTL policy change is not in effect",
+				goerr.V("collection", collectionName),
+				goerr.V("field", field))
+		}
+	}
+}
+
 // waitForIndexesReady waits for each created index to reach a stable state in parallel.
 // Each index is polled individually via GetIndex, which avoids being blocked by
 // unrelated indexes in the same collection.
-func (s *Sync) waitForIndexesReady(ctx context.Context, indexNames []string) error {
+func (s *syncRun) waitForIndexesReady(ctx context.Context, collectionName string, indexNames []string) error {
 	if s.async || s.dryRun || len(indexNames) == 0 {
 		return nil
 	}
@@ -361,7 +1124,7 @@ func (s *Sync) waitForIndexesReady(ctx context.Context, indexNames []string) err
 	for _, name := range indexNames {
 		name := name
 		g.Go(func() error {
-			return s.waitForSingleIndexReady(ctx, name)
+			return s.waitForSingleIndexReady(ctx, collectionName, name)
 		})
 	}
 
@@ -374,11 +1137,9 @@ func (s *Sync) waitForIndexesReady(ctx context.Context, indexNames []string) err
 }
 
 // waitForSingleIndexReady polls a single index by name until it reaches READY state.
-func (s *Sync) waitForSingleIndexReady(ctx context.Context, indexName string) error {
-	backoff := time.Second
-	maxBackoff := 10 * time.Second
-	lastLog := time.Now()
-	logInterval := 10 * time.Second
+func (s *syncRun) waitForSingleIndexReady(ctx context.Context, collectionName, indexName string) error {
+	wait := newWaiter(s.waitStrategy, s.clock, s.deadline)
+	var lastState string
 
 	for {
 		idx, err := s.client.GetIndex(ctx, indexName)
@@ -390,44 +1151,61 @@ func (s *Sync) waitForSingleIndexReady(ctx context.Context, indexName string) er
 				slog.String("index", indexName),
 				slog.Any("error", err))
 		} else {
+			if idx.State != lastState {
+				s.recorder.indexState(collectionName, indexName, idx.State)
+				s.emit(model.IndexStateChanged{
+					Collection: collectionName,
+					Name:       indexName,
+					Previous:   lastState,
+					State:      idx.State,
+				})
+				lastState = idx.State
+			}
+
 			switch idx.State {
 			case "READY":
 				s.logger.Info("Index is ready", slog.String("index", indexName))
 				return nil
 			case "ERROR", "NEEDS_REPAIR":
-				return goerr.New(fmt.Sprintf("index entered %s state", idx.State),
+				return goerr.Wrap(model.ErrIndexBuildFailed, fmt.Sprintf("index entered %s state", idx.State),
 					goerr.V("index", indexName))
 			default:
 				// CREATING or other transitional state — keep waiting
 			}
 		}
 
-		if time.Since(lastLog) >= logInterval {
-			s.logger.Info("Waiting for index to become READY",
-				slog.String("index", indexName))
-			lastLog = time.Now()
-		}
-
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(backoff):
+		if wait.logDue() {
+			progress := s.indexProgress(ctx, indexName)
+			attrs := []any{slog.String("index", indexName)}
+			if progress != nil {
+				attrs = append(attrs, slog.String("progress", FormatProgress(*progress)))
+			}
+			s.logger.Info("Waiting for index to become READY", attrs...)
+			s.emit(model.WaitingTick{
+				Collection: collectionName,
+				Kind:       model.WaitIndexReady,
+				Target:     indexName,
+				Elapsed:    wait.elapsed(),
+				Progress:   progress,
+			})
 		}
 
-		backoff *= 2
-		if backoff > maxBackoff {
-			backoff = maxBackoff
+		if err := wait.pause(ctx); err != nil {
+			return goerr.Wrap(err, "index is not READY",
+				goerr.V("index", indexName),
+				goerr.V("state", lastState))
 		}
 	}
 }
 
-// waitForOperationWithProgress is a helper method that wraps client wait with progress logging
-func (s *Sync) waitForOperationWithProgress(ctx context.Context, operation interface{}, progressFunc func(time.Duration)) error {
-	// Use custom wait logic with progress reporting
-	start := time.Now()
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+// waitForOperationWithProgress is a helper method that wraps client wait with
+// progress logging every log interval of the wait strategy. The wait is
+// abandoned with an error wrapping model.ErrWaitTimeout at its deadline.
+func (s *syncRun) waitForOperationWithProgress(ctx context.Context, operation interface{}, progressFunc func(time.Duration)) error {
+	wait := newWaiter(s.waitStrategy, s.clock, s.deadline)
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	done := make(chan error, 1)
 
 	// Start the wait operation in a goroutine
@@ -435,14 +1213,16 @@ func (s *Sync) waitForOperationWithProgress(ctx context.Context, operation inter
 		done <- s.client.WaitForOperation(ctx, operation)
 	}()
 
-	// Log progress every 10 seconds
 	for {
 		select {
 		case err := <-done:
 			return err
-		case <-ticker.C:
+		case <-wait.tick():
+			if wait.expired() {
+				return goerr.Wrap(wait.timeout(), "operation is not done")
+			}
 			if progressFunc != nil {
-				progressFunc(time.Since(start))
+				progressFunc(wait.elapsed())
 			}
 		case <-ctx.Done():
 			return ctx.Err()
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CollectionError is the failure of a collection during a sync
+type CollectionError struct {
+	Collection string
+	// Operation is the step that failed: "validate", "ensure collection",
+	// "sync indexes" or "sync TTL"
+	Operation string
+	Err       error
+}
+
+func (e *CollectionError) Error() string {
+	return fmt.Sprintf("collection %s: %v", e.Collection, e.Err)
+}
+
+func (e *CollectionError) Unwrap() error {
+	return e.Err
+}
+
+// SyncErrors is returned by a sync with SyncWithContinueOnError when one or
+// more collections failed. Errors are in configuration order.
+type SyncErrors struct {
+	Errors []*CollectionError
+}
+
+func (e *SyncErrors) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("sync failed for %d collection(s):", len(e.Errors)))
+	for _, err := range e.Errors {
+		lines = append(lines, fmt.Sprintf("  %s (%s): %v", err.Collection, err.Operation, err.Err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *SyncErrors) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+)
+
+// SyncResult is the outcome of a sync
+type SyncResult struct {
+	// DryRun is true if the changes were only reported
+	DryRun bool
+	// Collections are in configuration order. A collection is missing if
+	// the sync stopped before processing it.
+	Collections []CollectionResult
+	Duration    time.Duration
+}
+
+// CollectionResult is the outcome of synchronizing a collection
+type CollectionResult struct {
+	Name      string
+	Created   []IndexResult
+	Deleted   []IndexResult
+	Repaired  []IndexResult
+	Unchanged []IndexResult
+	// TTLAction is "enable", "disable", "move" (disabled on one field and
+	// enabled on another), or empty if the TTL policy was not changed
+	TTLAction string
+	// TTLField is the configured TTL field, or the disabled one if the TTL
+	// policy was removed
+	TTLField string
+	// TTLState is the last known state of the TTL policy of TTLField,
+	// empty if there is none
+	TTLState string
+	Duration time.Duration
+	Err      error
+}
+
+// IndexResult is an index of a CollectionResult
+type IndexResult struct {
+	Index model.Index
+	// Name is the resource name, empty in dry run mode for new indexes
+	Name string
+	// State is the last observed state, e.g. CREATING if the index was not
+	// waited for
+	State string
+}
+
+// syncRecorder collects the result of a sync. Methods may be called from
+// several goroutines, and do nothing on a nil recorder.
+type syncRecorder struct {
+	mu          sync.Mutex
+	collections map[string]*CollectionResult
+}
+
+func newSyncRecorder() *syncRecorder {
+	return &syncRecorder{collections: make(map[string]*CollectionResult)}
+}
+
+// update calls f with the result of a collection under the lock
+func (r *syncRecorder) update(collectionName string, f func(*CollectionResult)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.collections[collectionName]
+	if !ok {
+		c = &CollectionResult{Name: collectionName}
+		r.collections[collectionName] = c
+	}
+	f(c)
+}
+
+func (r *syncRecorder) created(collectionName string, idx IndexResult) {
+	r.update(collectionName, func(c *CollectionResult) { c.Created = append(c.Created, idx) })
+}
+
+func (r *syncRecorder) deleted(collectionName string, idx IndexResult) {
+	r.update(collectionName, func(c *CollectionResult) { c.Deleted = append(c.Deleted, idx) })
+}
+
+func (r *syncRecorder) repaired(collectionName string, idx IndexResult) {
+	r.update(collectionName, func(c *CollectionResult) { c.Repaired = append(c.Repaired, idx) })
+}
+
+func (r *syncRecorder) unchanged(collectionName string, idx IndexResult) {
+	r.update(collectionName, func(c *CollectionResult) { c.Unchanged = append(c.Unchanged, idx) })
+}
+
+// indexState updates the state of a created, repaired or unchanged index
+func (r *syncRecorder) indexState(collectionName, indexName, state string) {
+	r.update(collectionName, func(c *CollectionResult) {
+		for _, list := range [][]IndexResult{c.Created, c.Repaired, c.Unchanged} {
+			for i := range list {
+				if list[i].Name == indexName {
+					list[i].State = state
+				}
+			}
+		}
+	})
+}
+
+// ttl records a TTL change. A disable followed by an enable is a move.
+func (r *syncRecorder) ttl(collectionName, action, field, state string) {
+	r.update(collectionName, func(c *CollectionResult) {
+		if c.TTLAction == "disable" && action == "enable" {
+			action = "move"
+		}
+		c.TTLAction = action
+		c.TTLField = field
+		c.TTLState = state
+	})
+}
+
+// ttlState updates the state of the TTL policy
+func (r *syncRecorder) ttlState(collectionName, state string) {
+	r.update(collectionName, func(c *CollectionResult) { c.TTLState = state })
+}
+
+// finished records the duration and error of a collection
+func (r *syncRecorder) finished(collectionName string, duration time.Duration, err error) {
+	r.update(collectionName, func(c *CollectionResult) {
+		c.Duration = duration
+		c.Err = err
+	})
+}
+
+// result returns the results of the processed collections in configuration
+// order
+func (r *syncRecorder) result(config *model.Config) []CollectionResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []CollectionResult
+	for _, collection := range config.Collections {
+		if c, ok := r.collections[collection.Name]; ok {
+			result = append(result, *c)
+		}
+	}
+	return result
+}
@@ -2,9 +2,12 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/m-mizutani/fireconf/internal/interfaces"
 	"github.com/m-mizutani/fireconf/internal/interfaces/mock"
@@ -45,9 +48,12 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
-		sync := usecase.NewSync(mockClient, logger)
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithClock(newFakeClock()))
 
 		config := &model.Config{
 			Collections: []model.Collection{
@@ -93,6 +99,9 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger)
@@ -139,6 +148,9 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger)
@@ -173,6 +185,15 @@ func TestSync_Execute(t *testing.T) {
 			EnableTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				return nil, nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger)
@@ -194,6 +215,244 @@ func TestSync_Execute(t *testing.T) {
 
 		gt.Equal(t, len(mockClient.EnableTTLPolicyCalls()), 1)
 		gt.Equal(t, mockClient.EnableTTLPolicyCalls()[0].FieldName, "expireAt")
+		// The TTL field is exempted from single-field indexing by default
+		gt.Equal(t, len(mockClient.UpdateFieldIndexConfigCalls()), 1)
+		gt.NotNil(t, mockClient.UpdateFieldIndexConfigCalls()[0].Config)
+	})
+
+	t.Run("Normal: wait for enabled TTL policy to become ACTIVE", func(t *testing.T) {
+		var states []string
+		enabled := false
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				if !enabled {
+					return nil, nil
+				}
+				state := "CREATING"
+				if len(states) >= 1 {
+					state = "ACTIVE"
+				}
+				states = append(states, state)
+				return &interfaces.FirestoreTTL{FieldPath: fieldName, State: state}, nil
+			},
+			EnableTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
+				enabled = true
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				return nil, nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithClock(newFakeClock()), usecase.SyncWithWaitTTL())
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					TTL:  &model.TTL{Field: "expireAt"},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, states, []string{"CREATING", "ACTIVE"})
+	})
+
+	t.Run("Normal: wait for disabled TTL policy to be cleared", func(t *testing.T) {
+		disabled := false
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "expireAt", nil
+			},
+			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+				disabled = true
+				return nil, nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				if disabled {
+					return nil, nil
+				}
+				return &interfaces.FirestoreTTL{FieldPath: fieldName, State: "ACTIVE"}, nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithWaitTTL())
+
+		config := &model.Config{
+			Collections: []model.Collection{{Name: "users"}},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, len(mockClient.GetTTLPolicyCalls()), 1)
+		gt.Equal(t, mockClient.GetTTLPolicyCalls()[0].FieldName, "expireAt")
+	})
+
+	t.Run("Error: TTL policy enters NEEDS_REPAIR while waiting", func(t *testing.T) {
+		enabled := false
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				if !enabled {
+					return nil, nil
+				}
+				return &interfaces.FirestoreTTL{FieldPath: fieldName, State: "NEEDS_REPAIR"}, nil
+			},
+			EnableTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
+				enabled = true
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				return nil, nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithWaitTTL())
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					TTL:  &model.TTL{Field: "expireAt"},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.Error(t, err).Contains("NEEDS_REPAIR")
+	})
+
+	t.Run("Normal: moving TTL restores indexing on the old field", func(t *testing.T) {
+		var calls []string
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "deletedAt", nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				if fieldName == "deletedAt" {
+					return &interfaces.FirestoreTTL{FieldPath: fieldName, State: "ACTIVE"}, nil
+				}
+				return nil, nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				if fieldName == "deletedAt" {
+					return &interfaces.FirestoreFieldIndexConfig{}, nil // exempt
+				}
+				return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				if config == nil {
+					calls = append(calls, "restore:"+fieldName)
+				} else {
+					calls = append(calls, "exempt:"+fieldName)
+				}
+				return nil, nil
+			},
+			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+				calls = append(calls, "disable")
+				return nil, nil
+			},
+			EnableTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
+				calls = append(calls, "enable:"+fieldName)
+				return nil, nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger)
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					TTL:  &model.TTL{Field: "expireAt"},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, calls, []string{"disable", "restore:deletedAt", "exempt:expireAt", "enable:expireAt"})
+	})
+
+	t.Run("Normal: indexExemption false restores indexing on the TTL field", func(t *testing.T) {
+		exempt := false
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "expireAt", nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				return &interfaces.FirestoreTTL{FieldPath: fieldName, State: "ACTIVE"}, nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{}, nil // exempt
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				return nil, nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger)
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					TTL:  &model.TTL{Field: "expireAt", IndexExemption: &exempt},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, len(mockClient.UpdateFieldIndexConfigCalls()), 1)
+		gt.Equal(t, mockClient.UpdateFieldIndexConfigCalls()[0].FieldName, "expireAt")
+		gt.Nil(t, mockClient.UpdateFieldIndexConfigCalls()[0].Config)
 	})
 
 	t.Run("Normal: dry run mode", func(t *testing.T) {
@@ -207,6 +466,9 @@ func TestSync_Execute(t *testing.T) {
 			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithDryRun())
@@ -269,6 +531,9 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger)
@@ -303,6 +568,9 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger)
@@ -340,6 +608,15 @@ func TestSync_Execute(t *testing.T) {
 			EnableTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
 				return nil, fmt.Errorf("TTL field must be a timestamp")
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				return nil, nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger)
@@ -360,6 +637,131 @@ func TestSync_Execute(t *testing.T) {
 		gt.Error(t, err).Contains("TTL field must be a timestamp")
 	})
 
+	t.Run("Normal: observer receives progress events", func(t *testing.T) {
+		const idxName = "projects/test/databases/default/collectionGroups/users/indexes/idx1"
+		getCallCount := 0
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			CreateIndexFunc: func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+				return idxName, nil
+			},
+			GetIndexFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
+				getCallCount++
+				state := "CREATING"
+				if getCallCount >= 2 {
+					state = "READY"
+				}
+				return &interfaces.FirestoreIndex{Name: indexName, State: state}, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				return nil, nil
+			},
+			EnableTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{}, nil
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				return nil, nil
+			},
+		}
+
+		var events []model.Event
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithClock(newFakeClock()), usecase.SyncWithObserver(func(e model.Event) {
+			events = append(events, e)
+		}))
+
+		index := model.Index{
+			Fields: []model.IndexField{
+				{Name: "email", Order: "ASCENDING"},
+				{Name: "createdAt", Order: "DESCENDING"},
+			},
+			QueryScope: "COLLECTION",
+		}
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name:    "users",
+					Indexes: []model.Index{index},
+					TTL:     &model.TTL{Field: "expireAt"},
+				},
+			},
+		}
+
+		gt.NoError(t, sync.Execute(ctx, config))
+
+		gt.Equal(t, events[0], model.Event(model.CollectionStarted{Collection: "users"}))
+		gt.Equal(t, events[len(events)-1], model.Event(model.CollectionFinished{Collection: "users"}))
+
+		var created []model.IndexCreateSubmitted
+		var states []string
+		var ttl []model.TTLChanged
+		for _, e := range events {
+			switch e := e.(type) {
+			case model.IndexCreateSubmitted:
+				created = append(created, e)
+			case model.IndexStateChanged:
+				gt.Equal(t, e.Name, idxName)
+				states = append(states, e.Previous+"->"+e.State)
+			case model.TTLChanged:
+				ttl = append(ttl, e)
+			}
+		}
+		gt.Equal(t, created, []model.IndexCreateSubmitted{{Collection: "users", Index: index, Name: idxName}})
+		gt.Equal(t, states, []string{"->CREATING", "CREATING->READY"})
+		gt.Equal(t, ttl, []model.TTLChanged{{Collection: "users", Field: "expireAt", Action: "enable"}})
+	})
+
+	t.Run("Error: observer receives error events", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return nil, fmt.Errorf("permission denied")
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+		}
+
+		var events []model.Event
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithObserver(func(e model.Event) {
+			events = append(events, e)
+		}))
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					Indexes: []model.Index{
+						{Fields: []model.IndexField{{Name: "email", Order: "ASCENDING"}}},
+					},
+				},
+			},
+		}
+
+		gt.Error(t, sync.Execute(ctx, config))
+		gt.Equal(t, len(events), 3)
+		gt.Equal(t, events[0], model.Event(model.CollectionStarted{Collection: "users"}))
+		failed, ok := events[1].(model.ErrorOccurred)
+		gt.True(t, ok)
+		gt.Equal(t, failed.Collection, "users")
+		gt.NotNil(t, failed.Err)
+		finished, ok := events[2].(model.CollectionFinished)
+		gt.True(t, ok)
+		gt.Equal(t, finished.Err, failed.Err)
+	})
+
 	t.Run("Normal: wait for externally CREATING index to become READY", func(t *testing.T) {
 		// An external process created an index that is already in CREATING state.
 		// DiffIndexes detects it as existing, so CreateIndex is NOT called.
@@ -387,6 +789,9 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger)
@@ -440,6 +845,9 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger)
@@ -493,9 +901,12 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
-		sync := usecase.NewSync(mockClient, logger)
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithClock(newFakeClock()))
 
 		config := &model.Config{
 			Collections: []model.Collection{
@@ -515,33 +926,360 @@ func TestSync_Execute(t *testing.T) {
 
 		err := sync.Execute(ctx, config)
 		gt.Error(t, err).Contains("ERROR state")
+		gt.True(t, errors.Is(err, model.ErrIndexBuildFailed))
 	})
 
-	t.Run("Normal: skipWait=true does not poll for READY state", func(t *testing.T) {
-		listCallCount := 0
+	t.Run("Normal: create before delete waits for READY before deleting", func(t *testing.T) {
+		const oldName = "projects/test/databases/default/collectionGroups/users/indexes/old"
+		const newName = "projects/test/databases/default/collectionGroups/users/indexes/new"
+		var calls []string
+		getCallCount := 0
 		mockClient := &mock.FirestoreClientMock{
 			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
 				return true, nil
 			},
 			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
-				listCallCount++
 				return []interfaces.FirestoreIndex{
 					{
-						Name: "projects/test/databases/default/collectionGroups/users/indexes/idx1",
+						Name: oldName,
 						Fields: []interfaces.FirestoreIndexField{
+							{FieldPath: "createdAt", Order: "DESCENDING"},
 							{FieldPath: "email", Order: "ASCENDING"},
 						},
 						QueryScope: "COLLECTION",
-						State:      "CREATING",
+						State:      "READY",
 					},
 				}, nil
 			},
-			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
-				return nil, nil
+			CreateIndexFunc: func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+				calls = append(calls, "create")
+				return newName, nil
+			},
+			GetIndexFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
+				getCallCount++
+				state := "CREATING"
+				if getCallCount >= 2 {
+					state = "READY"
+				}
+				calls = append(calls, "get:"+state)
+				return &interfaces.FirestoreIndex{Name: indexName, State: state}, nil
+			},
+			DeleteIndexFunc: func(ctx context.Context, indexName string) (interface{}, error) {
+				calls = append(calls, "delete")
+				return nil, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				return nil, nil
+			},
+			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithClock(newFakeClock()), usecase.SyncWithCreateBeforeDelete())
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					Indexes: []model.Index{
+						{
+							Fields: []model.IndexField{
+								{Name: "email", Order: "ASCENDING"},
+								{Name: "createdAt", Order: "DESCENDING"},
+							},
+							QueryScope: "COLLECTION",
+						},
+					},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, calls, []string{"create", "get:CREATING", "get:READY", "delete"})
+		gt.Equal(t, mockClient.DeleteIndexCalls()[0].IndexName, oldName)
+	})
+
+	t.Run("Normal: create before delete with async keeps old indexes", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{
+					{
+						Name: "projects/test/databases/default/collectionGroups/users/indexes/old",
+						Fields: []interfaces.FirestoreIndexField{
+							{FieldPath: "createdAt", Order: "DESCENDING"},
+							{FieldPath: "email", Order: "ASCENDING"},
+						},
+						QueryScope: "COLLECTION",
+						State:      "READY",
+					},
+				}, nil
+			},
+			CreateIndexFunc: func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+				return "projects/test/databases/default/collectionGroups/users/indexes/new", nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				return nil, nil
+			},
+			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithCreateBeforeDelete(), usecase.SyncWithAsync())
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					Indexes: []model.Index{
+						{
+							Fields: []model.IndexField{
+								{Name: "email", Order: "ASCENDING"},
+								{Name: "createdAt", Order: "DESCENDING"},
+							},
+							QueryScope: "COLLECTION",
+						},
+					},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, len(mockClient.CreateIndexCalls()), 1)
+		gt.Equal(t, len(mockClient.DeleteIndexCalls()), 0)
+		gt.Equal(t, len(mockClient.GetIndexCalls()), 0)
+	})
+
+	t.Run("Normal: repair recreates index in ERROR state", func(t *testing.T) {
+		const brokenName = "projects/test/databases/default/collectionGroups/users/indexes/broken"
+		var created []string
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{
+					{
+						Name: brokenName,
+						Fields: []interfaces.FirestoreIndexField{
+							{FieldPath: "email", Order: "ASCENDING"},
+							{FieldPath: "createdAt", Order: "DESCENDING"},
+						},
+						QueryScope: "COLLECTION",
+						State:      "ERROR",
+					},
+				}, nil
+			},
+			DeleteIndexFunc: func(ctx context.Context, indexName string) (interface{}, error) {
+				return nil, nil
+			},
+			CreateIndexFunc: func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+				name := fmt.Sprintf("projects/test/databases/default/collectionGroups/users/indexes/new%d", len(created))
+				created = append(created, name)
+				return name, nil
+			},
+			GetIndexFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
+				// The first recreated index fails to build again
+				state := "READY"
+				if indexName == created[0] {
+					state = "ERROR"
+				}
+				return &interfaces.FirestoreIndex{Name: indexName, State: state}, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				return nil, nil
+			},
+			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithRepair(3))
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					Indexes: []model.Index{
+						{
+							Fields: []model.IndexField{
+								{Name: "email", Order: "ASCENDING"},
+								{Name: "createdAt", Order: "DESCENDING"},
+							},
+							QueryScope: "COLLECTION",
+						},
+					},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, len(mockClient.CreateIndexCalls()), 2)
+		gt.Equal(t, len(mockClient.DeleteIndexCalls()), 2)
+		gt.Equal(t, mockClient.DeleteIndexCalls()[0].IndexName, brokenName)
+		gt.Equal(t, mockClient.DeleteIndexCalls()[1].IndexName, created[0])
+	})
+
+	t.Run("Error: repair gives up after max attempts", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{
+					{
+						Name: "projects/test/databases/default/collectionGroups/users/indexes/broken",
+						Fields: []interfaces.FirestoreIndexField{
+							{FieldPath: "email", Order: "ASCENDING"},
+							{FieldPath: "createdAt", Order: "DESCENDING"},
+						},
+						QueryScope: "COLLECTION",
+						State:      "NEEDS_REPAIR",
+					},
+				}, nil
+			},
+			DeleteIndexFunc: func(ctx context.Context, indexName string) (interface{}, error) {
+				return nil, nil
+			},
+			CreateIndexFunc: func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+				return "projects/test/databases/default/collectionGroups/users/indexes/new", nil
+			},
+			GetIndexFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
+				return &interfaces.FirestoreIndex{Name: indexName, State: "ERROR"}, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				return nil, nil
+			},
+			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithRepair(2))
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					Indexes: []model.Index{
+						{
+							Fields: []model.IndexField{
+								{Name: "email", Order: "ASCENDING"},
+								{Name: "createdAt", Order: "DESCENDING"},
+							},
+							QueryScope: "COLLECTION",
+						},
+					},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.Error(t, err).Contains("failed to repair index")
+		gt.Equal(t, len(mockClient.CreateIndexCalls()), 2)
+	})
+
+	t.Run("Normal: unhealthy index is left alone without repair", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{
+					{
+						Name: "projects/test/databases/default/collectionGroups/users/indexes/broken",
+						Fields: []interfaces.FirestoreIndexField{
+							{FieldPath: "email", Order: "ASCENDING"},
+							{FieldPath: "createdAt", Order: "DESCENDING"},
+						},
+						QueryScope: "COLLECTION",
+						State:      "ERROR",
+					},
+				}, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				return nil, nil
+			},
+			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger)
+
+		config := &model.Config{
+			Collections: []model.Collection{
+				{
+					Name: "users",
+					Indexes: []model.Index{
+						{
+							Fields: []model.IndexField{
+								{Name: "email", Order: "ASCENDING"},
+								{Name: "createdAt", Order: "DESCENDING"},
+							},
+							QueryScope: "COLLECTION",
+						},
+					},
+				},
+			},
+		}
+
+		err := sync.Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, len(mockClient.DeleteIndexCalls()), 0)
+		gt.Equal(t, len(mockClient.CreateIndexCalls()), 0)
+	})
+
+	t.Run("Normal: skipWait=true does not poll for READY state", func(t *testing.T) {
+		listCallCount := 0
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				listCallCount++
+				return []interfaces.FirestoreIndex{
+					{
+						Name: "projects/test/databases/default/collectionGroups/users/indexes/idx1",
+						Fields: []interfaces.FirestoreIndexField{
+							{FieldPath: "email", Order: "ASCENDING"},
+						},
+						QueryScope: "COLLECTION",
+						State:      "CREATING",
+					},
+				}, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				return nil, nil
 			},
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
 		}
 
 		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithAsync()) // skipWait=true
@@ -589,6 +1327,15 @@ func TestSync_Execute(t *testing.T) {
 			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
 				return nil, nil
 			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				return nil, nil
+			},
 		}
 
 		// skipWait=true: this test verifies operation counts, not wait behavior
@@ -604,3 +1351,574 @@ func TestSync_Execute(t *testing.T) {
 		gt.Equal(t, len(mockClient.EnableTTLPolicyCalls()), 1) // TTL for users only
 	})
 }
+
+func TestSync_ExecuteWithResult(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.Default()
+
+	const (
+		newName   = "projects/test/databases/default/collectionGroups/users/indexes/new"
+		keptName  = "projects/test/databases/default/collectionGroups/users/indexes/kept"
+		staleName = "projects/test/databases/default/collectionGroups/users/indexes/stale"
+	)
+	kept := model.Index{
+		Fields:     []model.IndexField{{Name: "email", Order: "ASCENDING"}, {Name: "createdAt", Order: "DESCENDING"}},
+		QueryScope: "COLLECTION",
+	}
+	added := model.Index{
+		Fields:     []model.IndexField{{Name: "status", Order: "ASCENDING"}, {Name: "createdAt", Order: "DESCENDING"}},
+		QueryScope: "COLLECTION",
+	}
+	stale := model.Index{
+		Fields:     []model.IndexField{{Name: "oldField", Order: "ASCENDING"}},
+		QueryScope: "COLLECTION",
+	}
+	config := &model.Config{
+		Collections: []model.Collection{
+			{
+				Name:    "users",
+				Indexes: []model.Index{kept, added},
+				TTL:     &model.TTL{Field: "expireAt"},
+			},
+		},
+	}
+
+	listIndexes := func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+		keptIdx := usecase.ConvertModelToFirestoreIndex(kept)
+		keptIdx.Name, keptIdx.State = keptName, "READY"
+		staleIdx := usecase.ConvertModelToFirestoreIndex(stale)
+		staleIdx.Name, staleIdx.State = staleName, "READY"
+		return []interfaces.FirestoreIndex{keptIdx, staleIdx}, nil
+	}
+
+	t.Run("Normal: result lists created, deleted and unchanged indexes", func(t *testing.T) {
+		client := newSyncMock()
+		client.ListIndexesFunc = listIndexes
+
+		result := gt.R1(usecase.NewSync(client, logger).ExecuteWithResult(ctx, config)).NoError(t)
+
+		gt.False(t, result.DryRun)
+		gt.Equal(t, len(result.Collections), 1)
+		users := result.Collections[0]
+		gt.Equal(t, users.Name, "users")
+		gt.NoError(t, users.Err)
+		gt.Equal(t, users.Created, []usecase.IndexResult{{Index: added, Name: newName, State: "READY"}})
+		gt.Equal(t, users.Deleted, []usecase.IndexResult{{Index: stale, Name: staleName, State: "READY"}})
+		gt.Equal(t, users.Unchanged, []usecase.IndexResult{{Index: kept, Name: keptName, State: "READY"}})
+		gt.Equal(t, len(users.Repaired), 0)
+		gt.Equal(t, users.TTLAction, "enable")
+		gt.Equal(t, users.TTLField, "expireAt")
+		gt.Equal(t, users.TTLState, "CREATING")
+	})
+
+	t.Run("Normal: dry run result lists planned changes", func(t *testing.T) {
+		client := newSyncMock()
+		client.ListIndexesFunc = listIndexes
+		result := gt.R1(usecase.NewSync(client, logger, usecase.SyncWithDryRun()).ExecuteWithResult(ctx, config)).NoError(t)
+
+		gt.True(t, result.DryRun)
+		users := result.Collections[0]
+		gt.Equal(t, users.Created, []usecase.IndexResult{{Index: added}})
+		gt.Equal(t, len(users.Deleted), 1)
+		gt.Equal(t, users.TTLAction, "enable")
+		gt.Equal(t, len(client.CreateIndexCalls()), 0)
+	})
+
+	t.Run("Error: result records the failed collection", func(t *testing.T) {
+		client := newSyncMock()
+		client.ListIndexesFunc = listIndexes
+		client.CreateIndexFunc = func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+			return "", fmt.Errorf("quota exceeded")
+		}
+
+		result, err := usecase.NewSync(client, logger).ExecuteWithResult(ctx, config)
+		gt.Error(t, err)
+		gt.NotNil(t, result)
+		gt.Equal(t, len(result.Collections), 1)
+		gt.Error(t, result.Collections[0].Err)
+	})
+
+	t.Run("Normal: concurrent runs of one Sync keep their own results", func(t *testing.T) {
+		client := newSyncMock()
+		client.ListIndexesFunc = listIndexes
+		// Both runs submit their index before either goes on
+		var arrived sync.WaitGroup
+		arrived.Add(2)
+		client.CreateIndexFunc = func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+			arrived.Done()
+			arrived.Wait()
+			return newName, nil
+		}
+
+		s := usecase.NewSync(client, logger, usecase.SyncWithWaitStrategy(usecase.WaitStrategy{Deadline: time.Hour}))
+		results := make([]*usecase.SyncResult, 2)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := s.ExecuteWithResult(ctx, config)
+				if err != nil {
+					t.Error(err)
+				}
+				results[i] = result
+			}()
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			gt.Equal(t, len(result.Collections), 1)
+			gt.Equal(t, result.Collections[0].Created, []usecase.IndexResult{{Index: added, Name: newName, State: "READY"}})
+			gt.Equal(t, len(result.Collections[0].Deleted), 1)
+		}
+	})
+}
+
+func TestSync_ContinueOnError(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.Default()
+
+	index := model.Index{
+		Fields:     []model.IndexField{{Name: "status", Order: "ASCENDING"}, {Name: "createdAt", Order: "DESCENDING"}},
+		QueryScope: "COLLECTION",
+	}
+	config := &model.Config{
+		Collections: []model.Collection{
+			{Name: "users", Indexes: []model.Index{index}},
+			{Name: "posts", Indexes: []model.Index{index}},
+			{Name: "comments", Indexes: []model.Index{index}},
+		},
+	}
+
+	// Creating indexes fails except in posts
+	createIndex := func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+		if collectionID != "posts" {
+			return "", fmt.Errorf("invalid vector index on %s", collectionID)
+		}
+		return "projects/test/databases/default/collectionGroups/posts/indexes/new", nil
+	}
+
+	t.Run("Normal: failures do not abort other collections", func(t *testing.T) {
+		client := newSyncMock()
+		client.CreateIndexFunc = createIndex
+		sync := usecase.NewSync(client, logger, usecase.SyncWithContinueOnError())
+
+		result, err := sync.ExecuteWithResult(ctx, config)
+		gt.Error(t, err)
+
+		var syncErrs *usecase.SyncErrors
+		gt.True(t, errors.As(err, &syncErrs))
+		gt.Equal(t, len(syncErrs.Errors), 2)
+		gt.Equal(t, syncErrs.Errors[0].Collection, "users")
+		gt.Equal(t, syncErrs.Errors[0].Operation, "sync indexes")
+		gt.Equal(t, syncErrs.Errors[1].Collection, "comments")
+		gt.Error(t, err).Contains("invalid vector index on comments")
+
+		gt.Equal(t, len(client.CreateIndexCalls()), 3)
+		gt.Equal(t, len(result.Collections), 3)
+		gt.NoError(t, result.Collections[1].Err)
+		gt.Equal(t, result.Collections[1].Created[0].State, "READY")
+	})
+
+	t.Run("Error: first failure is returned without continue on error", func(t *testing.T) {
+		client := newSyncMock()
+		client.CreateIndexFunc = createIndex
+
+		err := usecase.NewSync(client, logger).Execute(ctx, config)
+		gt.Error(t, err)
+
+		var syncErrs *usecase.SyncErrors
+		gt.False(t, errors.As(err, &syncErrs))
+		var collErr *usecase.CollectionError
+		gt.True(t, errors.As(err, &collErr))
+		gt.Equal(t, collErr.Operation, "sync indexes")
+	})
+}
+
+func TestSync_Interrupted(t *testing.T) {
+	logger := slog.Default()
+
+	const newName = "projects/test/databases/default/collectionGroups/users/indexes/new"
+	added := model.Index{
+		Fields:     []model.IndexField{{Name: "status", Order: "ASCENDING"}, {Name: "createdAt", Order: "DESCENDING"}},
+		QueryScope: "COLLECTION",
+	}
+	config := &model.Config{
+		Collections: []model.Collection{{Name: "users", Indexes: []model.Index{added}}},
+	}
+
+	t.Run("Error: interruption stops submitting but completes the submission in flight", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var submitErr error
+		client := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				stale := usecase.ConvertModelToFirestoreIndex(model.Index{
+					Fields:     []model.IndexField{{Name: "oldField", Order: "ASCENDING"}},
+					QueryScope: "COLLECTION",
+				})
+				stale.Name, stale.State = "projects/test/databases/default/collectionGroups/users/indexes/stale", "READY"
+				return []interfaces.FirestoreIndex{stale}, nil
+			},
+			CreateIndexFunc: func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+				// Interrupted while the request is in flight
+				cancel()
+				submitErr = ctx.Err()
+				return newName, nil
+			},
+			GetIndexFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
+				return &interfaces.FirestoreIndex{Name: indexName, State: "CREATING"}, nil
+			},
+			DeleteIndexFunc: func(ctx context.Context, indexName string) (interface{}, error) {
+				return nil, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+		}
+
+		sync := usecase.NewSync(client, logger, usecase.SyncWithCreateBeforeDelete())
+		result, err := sync.ExecuteWithResult(ctx, config)
+		gt.Error(t, err)
+		gt.True(t, errors.Is(err, context.Canceled))
+		gt.NoError(t, submitErr)
+
+		// The stale index is not deleted after the interruption
+		gt.Equal(t, len(client.DeleteIndexCalls()), 0)
+		gt.Equal(t, result.Collections[0].Created, []usecase.IndexResult{{Index: added, Name: newName, State: "CREATING"}})
+	})
+
+	t.Run("Error: submission still pending after the grace period is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return nil, nil
+			},
+			CreateIndexFunc: func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+				// Interrupted while e.g. retrying or waiting for the call pool
+				cancel()
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+		}
+
+		clock := newFakeClock()
+		sync := usecase.NewSync(client, logger, usecase.SyncWithClock(clock))
+		err := sync.Execute(ctx, config)
+		gt.True(t, errors.Is(err, context.Canceled))
+		gt.Equal(t, clock.Waits(), []time.Duration{10 * time.Second})
+	})
+}
+
+func TestSync_IndexAlreadyExists(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.Default()
+
+	const existingName = "projects/test/databases/default/collectionGroups/users/indexes/raced"
+	added := model.Index{
+		Fields:     []model.IndexField{{Name: "status", Order: "ASCENDING"}, {Name: "createdAt", Order: "DESCENDING"}},
+		QueryScope: "COLLECTION",
+	}
+	config := &model.Config{
+		Collections: []model.Collection{{Name: "users", Indexes: []model.Index{added}}},
+	}
+
+	t.Run("Normal: existing index is resolved and waited for", func(t *testing.T) {
+		raced := usecase.ConvertModelToFirestoreIndex(added)
+		raced.Name, raced.State = existingName, "CREATING"
+		raced.Fields = append(raced.Fields, interfaces.FirestoreIndexField{FieldPath: "__name__", Order: "DESCENDING"})
+		client := newSyncMock()
+		created := false
+		client.ListIndexesFunc = func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+			if !created {
+				return nil, nil
+			}
+			return []interfaces.FirestoreIndex{raced}, nil
+		}
+		client.CreateIndexFunc = func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+			// Another run created the index in the meantime
+			created = true
+			return "", fmt.Errorf("failed to create index: %w", model.ErrIndexAlreadyExists)
+		}
+
+		result := gt.R1(usecase.NewSync(client, logger).ExecuteWithResult(ctx, config)).NoError(t)
+
+		gt.Equal(t, len(client.GetIndexCalls()), 1)
+		gt.Equal(t, client.GetIndexCalls()[0].IndexName, existingName)
+		gt.Equal(t, result.Collections[0].Created, []usecase.IndexResult{{Index: added, Name: existingName, State: "READY"}})
+	})
+
+	t.Run("Error: existing index cannot be resolved", func(t *testing.T) {
+		client := newSyncMock()
+		client.CreateIndexFunc = func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+			return "", fmt.Errorf("failed to create index: %w", model.ErrIndexAlreadyExists)
+		}
+
+		err := usecase.NewSync(client, logger).Execute(ctx, config)
+		gt.Error(t, err).Contains("index already exists but was not found")
+		gt.True(t, errors.Is(err, model.ErrIndexAlreadyExists))
+	})
+}
+
+func TestSync_WaitStrategy(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.Default()
+
+	config := &model.Config{
+		Collections: []model.Collection{{
+			Name: "users",
+			Indexes: []model.Index{{
+				Fields:     []model.IndexField{{Name: "status", Order: "ASCENDING"}, {Name: "createdAt", Order: "DESCENDING"}},
+				QueryScope: "COLLECTION",
+			}},
+		}},
+	}
+
+	// getIndexReadyAfter returns a GetIndexFunc whose index becomes READY
+	// after the given number of polls, never if 0
+	getIndexReadyAfter := func(readyAfter int) func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
+		polls := 0
+		return func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
+			polls++
+			if readyAfter > 0 && polls >= readyAfter {
+				return &interfaces.FirestoreIndex{Name: indexName, State: "READY"}, nil
+			}
+			return &interfaces.FirestoreIndex{Name: indexName, State: "CREATING"}, nil
+		}
+	}
+
+	t.Run("Normal: polls back off up to the max backoff", func(t *testing.T) {
+		client := newSyncMock()
+		client.GetIndexFunc = getIndexReadyAfter(6)
+
+		clock := newFakeClock()
+		sync := usecase.NewSync(client, logger,
+			usecase.SyncWithClock(clock),
+			usecase.SyncWithWaitStrategy(usecase.WaitStrategy{
+				InitialBackoff: 2 * time.Second,
+				MaxBackoff:     5 * time.Second,
+			}))
+
+		gt.NoError(t, sync.Execute(ctx, config))
+		gt.Equal(t, clock.Waits(), []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second, 5 * time.Second})
+	})
+
+	t.Run("Normal: jitter shortens delays", func(t *testing.T) {
+		client := newSyncMock()
+		client.GetIndexFunc = getIndexReadyAfter(4)
+
+		clock := newFakeClock()
+		sync := usecase.NewSync(client, logger,
+			usecase.SyncWithClock(clock),
+			usecase.SyncWithWaitStrategy(usecase.WaitStrategy{
+				InitialBackoff: 10 * time.Second,
+				MaxBackoff:     10 * time.Second,
+				Jitter:         0.5,
+			}))
+
+		gt.NoError(t, sync.Execute(ctx, config))
+		for _, d := range clock.Waits() {
+			gt.True(t, d >= 5*time.Second && d <= 10*time.Second)
+		}
+	})
+
+	t.Run("Error: index timeout is a distinct error", func(t *testing.T) {
+		client := newSyncMock()
+		client.GetIndexFunc = getIndexReadyAfter(0)
+
+		clock := newFakeClock()
+		sync := usecase.NewSync(client, logger,
+			usecase.SyncWithClock(clock),
+			usecase.SyncWithWaitStrategy(usecase.WaitStrategy{IndexTimeout: time.Minute}))
+
+		err := sync.Execute(ctx, config)
+		gt.Error(t, err).Contains("index is not READY")
+		gt.True(t, errors.Is(err, model.ErrWaitTimeout))
+		gt.False(t, errors.Is(err, context.DeadlineExceeded))
+
+		var total time.Duration
+		for _, d := range clock.Waits() {
+			total += d
+		}
+		gt.Equal(t, total, time.Minute)
+	})
+
+	t.Run("Error: overall deadline", func(t *testing.T) {
+		client := newSyncMock()
+		client.GetIndexFunc = getIndexReadyAfter(0)
+
+		clock := newFakeClock()
+		sync := usecase.NewSync(client, logger,
+			usecase.SyncWithClock(clock),
+			usecase.SyncWithWaitStrategy(usecase.WaitStrategy{
+				IndexTimeout: time.Hour,
+				Deadline:     30 * time.Second,
+			}))
+
+		result, err := sync.ExecuteWithResult(ctx, config)
+		gt.True(t, errors.Is(err, model.ErrWaitTimeout))
+		gt.Equal(t, result.Duration, 30*time.Second)
+		gt.Equal(t, result.Collections[0].Created[0].State, "CREATING")
+	})
+
+	t.Run("Error: index deletion times out", func(t *testing.T) {
+		client := newSyncMock()
+		client.GetIndexFunc = getIndexReadyAfter(1)
+		client.ListIndexesFunc = func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+			stale := interfaces.FirestoreIndex{
+				Name:       "projects/test/databases/default/collectionGroups/users/indexes/stale",
+				Fields:     []interfaces.FirestoreIndexField{{FieldPath: "oldField", Order: "ASCENDING"}},
+				QueryScope: "COLLECTION",
+				State:      "READY",
+			}
+			return []interfaces.FirestoreIndex{stale}, nil
+		}
+		client.DeleteIndexFunc = func(ctx context.Context, indexName string) (interface{}, error) {
+			return "operation", nil
+		}
+		client.WaitForOperationFunc = func(ctx context.Context, operation interface{}) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		clock := newFakeClock()
+		sync := usecase.NewSync(client, logger,
+			usecase.SyncWithClock(clock),
+			usecase.SyncWithWaitStrategy(usecase.WaitStrategy{IndexTimeout: 25 * time.Second}))
+
+		err := sync.Execute(ctx, config)
+		gt.Error(t, err).Contains("operation is not done")
+		gt.True(t, errors.Is(err, model.ErrWaitTimeout))
+		gt.Equal(t, clock.Waits(), []time.Duration{10 * time.Second, 10 * time.Second, 5 * time.Second})
+	})
+}
+
+func TestSync_FieldIndexStore(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.DiscardHandler)
+
+	custom := &interfaces.FirestoreFieldIndexConfig{
+		Indexes: []interfaces.FirestoreFieldIndex{
+			{QueryScope: "COLLECTION", Order: "DESCENDING"},
+			{QueryScope: "COLLECTION_GROUP", Order: "ASCENDING"},
+		},
+	}
+
+	t.Run("Normal: configuration from before the exemption is restored", func(t *testing.T) {
+		store := newMemoryFieldIndexStore()
+		fieldConfig := custom
+		ttlField := ""
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return ttlField, nil
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				if fieldName != ttlField {
+					return nil, nil
+				}
+				return &interfaces.FirestoreTTL{FieldPath: fieldName, State: "ACTIVE"}, nil
+			},
+			EnableTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
+				ttlField = fieldName
+				return nil, nil
+			},
+			DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+				ttlField = ""
+				return nil, nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return fieldConfig, nil
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				fieldConfig = config
+				return nil, nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithFieldIndexStore(store))
+
+		withTTL := &model.Config{Collections: []model.Collection{{Name: "users", TTL: &model.TTL{Field: "expireAt"}}}}
+		gt.NoError(t, sync.Execute(ctx, withTTL))
+		gt.Equal(t, fieldConfig, &interfaces.FirestoreFieldIndexConfig{})
+		gt.Equal(t, store.PreviousFieldIndexConfig("users", "expireAt"), custom)
+
+		withoutTTL := &model.Config{Collections: []model.Collection{{Name: "users"}}}
+		gt.NoError(t, sync.Execute(ctx, withoutTTL))
+		gt.Equal(t, fieldConfig, custom)
+		gt.Equal(t, len(store.RecordedFields("users")), 0)
+	})
+
+	t.Run("Normal: recorded field is restored after its policy was removed earlier", func(t *testing.T) {
+		store := newMemoryFieldIndexStore()
+		store.RecordFieldIndexConfig("users", "expireAt", custom)
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{}, nil // exempt
+			},
+			UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+				return nil, nil
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithFieldIndexStore(store))
+
+		err := sync.Execute(ctx, &model.Config{Collections: []model.Collection{{Name: "users"}}})
+		gt.NoError(t, err)
+		gt.Equal(t, len(mockClient.UpdateFieldIndexConfigCalls()), 1)
+		gt.Equal(t, mockClient.UpdateFieldIndexConfigCalls()[0].FieldName, "expireAt")
+		gt.Equal(t, mockClient.UpdateFieldIndexConfigCalls()[0].Config, custom)
+		gt.Equal(t, len(store.RecordedFields("users")), 0)
+	})
+
+	t.Run("Normal: dry run keeps the record", func(t *testing.T) {
+		store := newMemoryFieldIndexStore()
+		store.RecordFieldIndexConfig("users", "expireAt", custom)
+		mockClient := &mock.FirestoreClientMock{
+			CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+				return true, nil
+			},
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{}, nil
+			},
+			FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+				return "", nil
+			},
+			GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+				return &interfaces.FirestoreFieldIndexConfig{}, nil // exempt
+			},
+		}
+
+		sync := usecase.NewSync(mockClient, logger, usecase.SyncWithDryRun(), usecase.SyncWithFieldIndexStore(store))
+
+		err := sync.Execute(ctx, &model.Config{Collections: []model.Collection{{Name: "users"}}})
+		gt.NoError(t, err)
+		gt.Equal(t, store.RecordedFields("users"), []string{"expireAt"})
+	})
+}
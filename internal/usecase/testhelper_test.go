@@ -1,9 +1,15 @@
 package usecase_test
 
 import (
+	"context"
+	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/fireconf/internal/interfaces/mock"
 	"github.com/m-mizutani/fireconf/internal/model"
 	"github.com/m-mizutani/fireconf/internal/usecase"
 	"github.com/m-mizutani/gt"
@@ -34,3 +40,123 @@ func LoadVectorTestConfig(t *testing.T) *model.Config {
 func LoadArrayTestConfig(t *testing.T) *model.Config {
 	return LoadTestConfig(t, usecase.TestDataArray)
 }
+
+// fakeClock is a Clock whose After fires at once, advancing the time by the
+// duration waited for
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Waits returns the durations waited for
+func (c *fakeClock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.waits...)
+}
+
+// memoryFieldIndexStore is an in-memory interfaces.FieldIndexStore
+type memoryFieldIndexStore struct {
+	mu      sync.Mutex
+	configs map[[2]string]*interfaces.FirestoreFieldIndexConfig
+}
+
+func newMemoryFieldIndexStore() *memoryFieldIndexStore {
+	return &memoryFieldIndexStore{configs: make(map[[2]string]*interfaces.FirestoreFieldIndexConfig)}
+}
+
+func (s *memoryFieldIndexStore) PreviousFieldIndexConfig(collectionID, fieldName string) *interfaces.FirestoreFieldIndexConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.configs[[2]string{collectionID, fieldName}]
+}
+
+func (s *memoryFieldIndexStore) RecordFieldIndexConfig(collectionID, fieldName string, config *interfaces.FirestoreFieldIndexConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[[2]string{collectionID, fieldName}] = config
+}
+
+func (s *memoryFieldIndexStore) ForgetFieldIndexConfig(collectionID, fieldName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.configs, [2]string{collectionID, fieldName})
+}
+
+func (s *memoryFieldIndexStore) RecordedFields(collectionID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var fields []string
+	for key := range s.configs {
+		if key[0] == collectionID {
+			fields = append(fields, key[1])
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// newSyncMock returns a client of existing collections without indexes or
+// TTL policies, whose created indexes are READY at once. Tests replace the
+// functions of the behavior they exercise.
+func newSyncMock() *mock.FirestoreClientMock {
+	return &mock.FirestoreClientMock{
+		CollectionExistsFunc: func(ctx context.Context, collectionID string) (bool, error) {
+			return true, nil
+		},
+		ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+			return nil, nil
+		},
+		CreateIndexFunc: func(ctx context.Context, collectionID string, index interfaces.FirestoreIndex) (string, error) {
+			return "projects/test/databases/default/collectionGroups/" + collectionID + "/indexes/new", nil
+		},
+		GetIndexFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreIndex, error) {
+			return &interfaces.FirestoreIndex{Name: indexName, State: "READY"}, nil
+		},
+		GetIndexOperationFunc: func(ctx context.Context, indexName string) (*interfaces.FirestoreOperation, error) {
+			return nil, nil
+		},
+		DeleteIndexFunc: func(ctx context.Context, indexName string) (interface{}, error) {
+			return nil, nil
+		},
+		GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+			return nil, nil
+		},
+		FindTTLFieldFunc: func(ctx context.Context, collectionID string) (string, error) {
+			return "", nil
+		},
+		EnableTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (interface{}, error) {
+			return nil, nil
+		},
+		DisableTTLPolicyFunc: func(ctx context.Context, collectionID string) (interface{}, error) {
+			return nil, nil
+		},
+		GetFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreFieldIndexConfig, error) {
+			return &interfaces.FirestoreFieldIndexConfig{UsesAncestorConfig: true}, nil
+		},
+		UpdateFieldIndexConfigFunc: func(ctx context.Context, collectionID string, fieldName string, config *interfaces.FirestoreFieldIndexConfig) (interface{}, error) {
+			return nil, nil
+		},
+	}
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// IndexUsage reports how many logged queries a configured composite index
+// served
+type IndexUsage struct {
+	Collection string
+	Index      model.Index
+	Count      int
+}
+
+// UsageReport is the result of matching executed queries to the indexes of
+// a configuration
+type UsageReport struct {
+	// Indexes lists every composite index of the configuration in order
+	Indexes []IndexUsage
+	// SingleField counts queries served by single-field indexes
+	SingleField int
+	// Unmatched counts queries whose index is not in the configuration
+	Unmatched int
+}
+
+// Unused returns the composite indexes that served no query
+func (r *UsageReport) Unused() []IndexUsage {
+	var result []IndexUsage
+	for _, u := range r.Indexes {
+		if u.Count == 0 {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// AnalyzeIndexUsage maps each executed query to the configured index that
+// served it. An index reported by query explain without a collection is
+// counted for every collection having that index, so that an index is never
+// reported as unused because of missing information.
+func AnalyzeIndexUsage(config *model.Config, usages []model.QueryUsage) (*UsageReport, error) {
+	report := &UsageReport{}
+	for _, collection := range config.Collections {
+		for _, index := range collection.Indexes {
+			report.Indexes = append(report.Indexes, IndexUsage{Collection: collection.Name, Index: index})
+		}
+	}
+
+	for _, usage := range usages {
+		switch {
+		case usage.Index != nil:
+			if isSingleFieldIndex(*usage.Index) {
+				report.SingleField++
+				continue
+			}
+			matched := false
+			for i := range report.Indexes {
+				u := &report.Indexes[i]
+				if (usage.Collection == "" || usage.Collection == u.Collection) && usedIndexMatches(u.Index, *usage.Index) {
+					u.Count++
+					matched = true
+				}
+			}
+			if !matched {
+				report.Unmatched++
+			}
+
+		case usage.Query != nil:
+			explanation, err := ExplainQuery(config, *usage.Query)
+			if err != nil {
+				return nil, goerr.Wrap(err, "invalid query in log", goerr.V("line", usage.Line))
+			}
+			switch {
+			case explanation.SingleField:
+				report.SingleField++
+			case explanation.Served:
+				for i := range report.Indexes {
+					u := &report.Indexes[i]
+					if u.Collection == usage.Query.Collection && SameIndex(u.Index, *explanation.Index) {
+						u.Count++
+						break
+					}
+				}
+			default:
+				report.Unmatched++
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// isSingleFieldIndex reports whether an index used by a query is one of the
+// automatic single-field indexes
+func isSingleFieldIndex(index model.Index) bool {
+	fields := 0
+	for _, field := range index.Fields {
+		if field.VectorConfig != nil {
+			return false
+		}
+		if field.Name != "__name__" {
+			fields++
+		}
+	}
+	return fields <= 1
+}
+
+// usedIndexMatches reports whether a configured index is the one reported
+// by query explain. __name__ fields are ignored, and a used vector field
+// without dimension matches any dimension.
+func usedIndexMatches(configured, used model.Index) bool {
+	if normalizedScope(configured) != normalizedScope(used) {
+		return false
+	}
+
+	a := withoutName(configured.Fields)
+	b := withoutName(used.Fields)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return false
+		}
+		switch {
+		case b[i].VectorConfig != nil:
+			if a[i].VectorConfig == nil ||
+				(b[i].VectorConfig.Dimension != 0 && b[i].VectorConfig.Dimension != a[i].VectorConfig.Dimension) {
+				return false
+			}
+		case b[i].ArrayConfig != "":
+			if a[i].ArrayConfig != b[i].ArrayConfig {
+				return false
+			}
+		default:
+			if a[i].VectorConfig != nil || a[i].ArrayConfig != "" || orderOf(a[i]) != orderOf(b[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// withoutName drops the __name__ fields that Firestore appends to indexes
+func withoutName(fields []model.IndexField) []model.IndexField {
+	result := make([]model.IndexField, 0, len(fields))
+	for _, field := range fields {
+		if field.Name != "__name__" {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// orderOf returns the order of a field, defaulting to ASCENDING
+func orderOf(field model.IndexField) string {
+	if field.Order == "" {
+		return "ASCENDING"
+	}
+	return field.Order
+}
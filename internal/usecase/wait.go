@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// Wait waits for the configured indexes to become READY and TTL policies to
+// become ACTIVE, e.g. after a sync that did not wait for them
+type Wait struct {
+	client   interfaces.FirestoreClient
+	logger   *slog.Logger
+	clock    Clock
+	strategy WaitStrategy
+}
+
+// WaitOption configures the Wait use case
+type WaitOption func(*Wait)
+
+// WaitWithClock replaces the clock used while waiting, e.g. in tests
+func WaitWithClock(clock Clock) WaitOption {
+	return func(w *Wait) { w.clock = clock }
+}
+
+// WaitWithStrategy configures polling and timeouts. IndexTimeout and
+// Deadline both bound the whole wait.
+func WaitWithStrategy(strategy WaitStrategy) WaitOption {
+	return func(w *Wait) { w.strategy = strategy }
+}
+
+// defaultPollStrategy is the wait strategy of Wait unless configured. Each
+// poll checks every index and TTL policy, so polls back off further than
+// those of Sync.
+func defaultPollStrategy() WaitStrategy {
+	return WaitStrategy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+		LogInterval:    30 * time.Second,
+	}
+}
+
+// NewWait creates a new Wait use case
+func NewWait(client interfaces.FirestoreClient, logger *slog.Logger, opts ...WaitOption) *Wait {
+	w := &Wait{
+		client: client,
+		logger: logger,
+		clock:  realClock{},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.strategy = w.strategy.withDefaults(defaultPollStrategy())
+	return w
+}
+
+// waitProgress is the result of checking every configured index and TTL
+// policy once
+type waitProgress struct {
+	total   int
+	ready   int
+	pending []string
+}
+
+// Execute polls until every index in the configuration is READY and every TTL
+// policy is ACTIVE. It fails as soon as one of them is missing or enters an
+// ERROR or NEEDS_REPAIR state, and when the context is done.
+func (w *Wait) Execute(ctx context.Context, config *model.Config) error {
+	return w.poll(ctx, func(ctx context.Context) (*waitProgress, error) {
+		return w.check(ctx, config)
+	})
+}
+
+// ExecuteIndexes polls until the given indexes are READY, e.g. the indexes
+// submitted by an interrupted sync. indexes maps collection names to index
+// resource names. It fails as soon as an index is missing or enters an ERROR
+// or NEEDS_REPAIR state, and when the context is done.
+func (w *Wait) ExecuteIndexes(ctx context.Context, indexes map[string][]string) error {
+	collections := make([]string, 0, len(indexes))
+	for collection := range indexes {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+
+	return w.poll(ctx, func(ctx context.Context) (*waitProgress, error) {
+		progress := &waitProgress{}
+		for _, collection := range collections {
+			if err := w.checkIndexNames(ctx, collection, indexes[collection], progress); err != nil {
+				return nil, err
+			}
+		}
+		return progress, nil
+	})
+}
+
+// poll calls check with backoff until nothing is pending. Waiting beyond the
+// timeouts of the wait strategy is an error wrapping model.ErrWaitTimeout.
+func (w *Wait) poll(ctx context.Context, check func(context.Context) (*waitProgress, error)) error {
+	var deadline time.Time
+	if w.strategy.Deadline > 0 {
+		deadline = w.clock.Now().Add(w.strategy.Deadline)
+	}
+	wait := newWaiter(w.strategy, w.clock, deadline)
+	lastReady := -1
+
+	for {
+		progress, err := check(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(progress.pending) == 0 {
+			w.logger.Info("All indexes and TTL policies are ready", slog.Int("total", progress.total))
+			return nil
+		}
+
+		if logDue := wait.logDue(); logDue || progress.ready != lastReady {
+			w.logger.Info("Waiting for indexes and TTL policies",
+				slog.Int("ready", progress.ready),
+				slog.Int("total", progress.total),
+				slog.Any("pending", progress.pending))
+			lastReady = progress.ready
+		}
+
+		if err := wait.pause(ctx); err != nil {
+			return goerr.Wrap(err, "stopped waiting for indexes and TTL policies",
+				goerr.V("ready", progress.ready),
+				goerr.V("total", progress.total),
+				goerr.V("pending", progress.pending))
+		}
+	}
+}
+
+// check reads the state of every configured index and TTL policy once.
+// Failures to read a state are logged and counted as pending.
+func (w *Wait) check(ctx context.Context, config *model.Config) (*waitProgress, error) {
+	progress := &waitProgress{}
+
+	for _, collection := range config.Collections {
+		if len(collection.Indexes) > 0 {
+			if err := w.checkIndexes(ctx, collection, progress); err != nil {
+				return nil, err
+			}
+		}
+		if collection.TTL != nil {
+			if err := w.checkTTL(ctx, collection, progress); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return progress, nil
+}
+
+// checkIndexes adds the states of the indexes of a collection to progress
+func (w *Wait) checkIndexes(ctx context.Context, collection model.Collection, progress *waitProgress) error {
+	progress.total += len(collection.Indexes)
+
+	existing, err := w.client.ListIndexes(ctx, collection.Name)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil // reported by the caller
+		}
+		w.logger.Warn("Failed to list indexes while waiting, retrying",
+			slog.String("collection", collection.Name),
+			slog.Any("error", err))
+		for _, idx := range collection.Indexes {
+			progress.pending = append(progress.pending, collection.Name+": "+FormatIndex(idx))
+		}
+		return nil
+	}
+
+	existingMap := make(map[string]interfaces.FirestoreIndex)
+	for _, idx := range existing {
+		existingMap[getIndexKey(idx)] = idx
+	}
+
+	for _, idx := range collection.Indexes {
+		label := collection.Name + ": " + FormatIndex(idx)
+		found, ok := existingMap[getIndexKey(ConvertModelToFirestoreIndex(idx))]
+		if !ok {
+			return goerr.New("index does not exist, run sync first",
+				goerr.V("collection", collection.Name),
+				goerr.V("index", FormatIndex(idx)))
+		}
+
+		switch {
+		case found.State == "READY":
+			progress.ready++
+		case IsUnhealthyIndexState(found.State):
+			return goerr.Wrap(model.ErrIndexBuildFailed, fmt.Sprintf("index entered %s state", found.State),
+				goerr.V("collection", collection.Name),
+				goerr.V("index", found.Name))
+		default:
+			progress.pending = append(progress.pending, label)
+		}
+	}
+	return nil
+}
+
+// checkIndexNames adds the states of the named indexes of a collection to
+// progress
+func (w *Wait) checkIndexNames(ctx context.Context, collection string, names []string, progress *waitProgress) error {
+	progress.total += len(names)
+
+	existing, err := w.client.ListIndexes(ctx, collection)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil // reported by the caller
+		}
+		w.logger.Warn("Failed to list indexes while waiting, retrying",
+			slog.String("collection", collection),
+			slog.Any("error", err))
+		for _, name := range names {
+			progress.pending = append(progress.pending, collection+": "+name)
+		}
+		return nil
+	}
+
+	existingMap := make(map[string]interfaces.FirestoreIndex)
+	for _, idx := range existing {
+		existingMap[idx.Name] = idx
+	}
+
+	for _, name := range names {
+		found, ok := existingMap[name]
+		if !ok {
+			return goerr.New("index does not exist",
+				goerr.V("collection", collection),
+				goerr.V("index", name))
+		}
+
+		switch {
+		case found.State == "READY":
+			progress.ready++
+		case IsUnhealthyIndexState(found.State):
+			return goerr.Wrap(model.ErrIndexBuildFailed, fmt.Sprintf("index entered %s state", found.State),
+				goerr.V("collection", collection),
+				goerr.V("index", name))
+		default:
+			progress.pending = append(progress.pending, collection+": "+FormatIndex(NormalizeFirestoreIndex(found)))
+		}
+	}
+	return nil
+}
+
+// checkTTL adds the state of the TTL policy of a collection to progress
+func (w *Wait) checkTTL(ctx context.Context, collection model.Collection, progress *waitProgress) error {
+	progress.total++
+	label := collection.Name + ": TTL " + collection.TTL.Field
+
+	ttl, err := w.client.GetTTLPolicy(ctx, collection.Name, collection.TTL.Field)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil // reported by the caller
+		}
+		w.logger.Warn("Failed to get TTL policy while waiting, retrying",
+			slog.String("collection", collection.Name),
+			slog.Any("error", err))
+		progress.pending = append(progress.pending, label)
+		return nil
+	}
+
+	switch {
+	case ttl == nil:
+		return goerr.New("TTL policy does not exist, run sync first",
+			goerr.V("collection", collection.Name),
+			goerr.V("field", collection.TTL.Field))
+	case ttl.State == "ACTIVE":
+		progress.ready++
+	case ttl.State == "NEEDS_REPAIR":
+		return goerr.New("TTL policy entered NEEDS_REPAIR state",
+			goerr.V("collection", collection.Name),
+			goerr.V("field", collection.TTL.Field))
+	default:
+		progress.pending = append(progress.pending, label)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// Clock provides the time for waiting, so that tests can replace it
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock of the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WaitStrategy configures polling while waiting for indexes, index deletions
+// and TTL policies. Zero fields take the values of DefaultWaitStrategy.
+type WaitStrategy struct {
+	// InitialBackoff is the delay before the second poll
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between polls, which doubles per poll
+	MaxBackoff time.Duration
+	// Jitter is the fraction of each delay, between 0 and 1, that is
+	// randomly subtracted so that concurrent waits do not poll in lockstep
+	Jitter float64
+	// LogInterval is the interval of progress logs and WaitingTick events
+	LogInterval time.Duration
+	// IndexTimeout bounds waiting for a single index build or deletion or
+	// TTL policy change, 0 for no limit
+	IndexTimeout time.Duration
+	// Deadline bounds the whole sync measured from its start and is
+	// checked while waiting, 0 for no limit
+	Deadline time.Duration
+}
+
+// DefaultWaitStrategy returns the wait strategy used unless configured
+func DefaultWaitStrategy() WaitStrategy {
+	return WaitStrategy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     10 * time.Second,
+		LogInterval:    10 * time.Second,
+	}
+}
+
+// withDefaults fills the zero fields of the strategy with those of def
+func (w WaitStrategy) withDefaults(def WaitStrategy) WaitStrategy {
+	if w.InitialBackoff == 0 {
+		w.InitialBackoff = def.InitialBackoff
+	}
+	if w.MaxBackoff == 0 {
+		w.MaxBackoff = def.MaxBackoff
+	}
+	if w.LogInterval == 0 {
+		w.LogInterval = def.LogInterval
+	}
+	w.InitialBackoff = min(w.InitialBackoff, w.MaxBackoff)
+	return w
+}
+
+// waiter paces the polls of waiting for a single target
+type waiter struct {
+	strategy WaitStrategy
+	clock    Clock
+	start    time.Time
+	lastLog  time.Time
+	backoff  time.Duration
+	// deadline is the earlier of the index timeout and the sync deadline,
+	// zero if there is none
+	deadline time.Time
+}
+
+// newWaiter starts waiting for a target. deadline is the end of the overall
+// deadline, zero if there is none.
+func newWaiter(strategy WaitStrategy, clock Clock, deadline time.Time) *waiter {
+	now := clock.Now()
+	w := &waiter{
+		strategy: strategy,
+		clock:    clock,
+		start:    now,
+		lastLog:  now,
+		backoff:  strategy.InitialBackoff,
+		deadline: deadline,
+	}
+	if timeout := strategy.IndexTimeout; timeout > 0 {
+		if d := now.Add(timeout); w.deadline.IsZero() || d.Before(w.deadline) {
+			w.deadline = d
+		}
+	}
+	return w
+}
+
+// elapsed returns the time since waiting started
+func (w *waiter) elapsed() time.Duration {
+	return w.clock.Now().Sub(w.start)
+}
+
+// logDue reports whether progress should be logged, once per log interval
+func (w *waiter) logDue() bool {
+	now := w.clock.Now()
+	if now.Sub(w.lastLog) < w.strategy.LogInterval {
+		return false
+	}
+	w.lastLog = now
+	return true
+}
+
+// timeout returns the error of waiting beyond the deadline
+func (w *waiter) timeout() error {
+	return goerr.Wrap(model.ErrWaitTimeout, "timed out waiting",
+		goerr.V("elapsed", w.elapsed().Round(time.Second)))
+}
+
+// pause sleeps until the next poll. The sleep is cut short at the deadline so
+// that the target is polled a last time; once the deadline has passed an
+// error wrapping model.ErrWaitTimeout is returned.
+func (w *waiter) pause(ctx context.Context) error {
+	delay := w.backoff
+	if w.strategy.Jitter > 0 {
+		delay -= time.Duration(w.strategy.Jitter * rand.Float64() * float64(delay))
+	}
+	w.backoff = min(w.backoff*2, w.strategy.MaxBackoff)
+
+	if !w.deadline.IsZero() {
+		remaining := w.deadline.Sub(w.clock.Now())
+		if remaining <= 0 {
+			return w.timeout()
+		}
+		delay = min(delay, remaining)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.clock.After(delay):
+		return nil
+	}
+}
+
+// tick returns a channel firing at the next log interval, or at the deadline
+// if it comes first
+func (w *waiter) tick() <-chan time.Time {
+	delay := w.strategy.LogInterval
+	if !w.deadline.IsZero() {
+		delay = min(delay, max(w.deadline.Sub(w.clock.Now()), 0))
+	}
+	return w.clock.After(delay)
+}
+
+// expired reports whether the deadline has passed
+func (w *waiter) expired() bool {
+	return !w.deadline.IsZero() && !w.clock.Now().Before(w.deadline)
+}
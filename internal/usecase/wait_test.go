@@ -0,0 +1,228 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/fireconf/internal/interfaces/mock"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/gt"
+)
+
+func TestWait_Execute(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.Default()
+
+	config := &model.Config{
+		Collections: []model.Collection{
+			{
+				Name: "users",
+				Indexes: []model.Index{
+					{
+						Fields: []model.IndexField{
+							{Name: "email", Order: "ASCENDING"},
+							{Name: "createdAt", Order: "DESCENDING"},
+						},
+						QueryScope: "COLLECTION",
+					},
+				},
+				TTL: &model.TTL{Field: "expireAt"},
+			},
+		},
+	}
+	listIndexes := func(state string) func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+		return func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+			return []interfaces.FirestoreIndex{
+				{
+					Name: "projects/test/databases/default/collectionGroups/users/indexes/idx1",
+					Fields: []interfaces.FirestoreIndexField{
+						{FieldPath: "email", Order: "ASCENDING"},
+						{FieldPath: "createdAt", Order: "DESCENDING"},
+						{FieldPath: "__name__", Order: "DESCENDING"},
+					},
+					QueryScope: "COLLECTION",
+					State:      state,
+				},
+			}, nil
+		}
+	}
+	getTTL := func(state string) func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+		return func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+			return &interfaces.FirestoreTTL{FieldPath: fieldName, State: state}, nil
+		}
+	}
+
+	t.Run("Normal: everything is already ready", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc:  listIndexes("READY"),
+			GetTTLPolicyFunc: getTTL("ACTIVE"),
+		}
+
+		err := usecase.NewWait(mockClient, logger).Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, len(mockClient.ListIndexesCalls()), 1)
+		gt.Equal(t, len(mockClient.GetTTLPolicyCalls()), 1)
+	})
+
+	t.Run("Normal: poll until index and TTL are ready", func(t *testing.T) {
+		polls := 0
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				polls++
+				if polls < 2 {
+					return listIndexes("CREATING")(ctx, collectionID)
+				}
+				return listIndexes("READY")(ctx, collectionID)
+			},
+			GetTTLPolicyFunc: func(ctx context.Context, collectionID string, fieldName string) (*interfaces.FirestoreTTL, error) {
+				if polls < 2 {
+					return getTTL("CREATING")(ctx, collectionID, fieldName)
+				}
+				return getTTL("ACTIVE")(ctx, collectionID, fieldName)
+			},
+		}
+
+		clock := newFakeClock()
+		err := usecase.NewWait(mockClient, logger, usecase.WaitWithClock(clock)).Execute(ctx, config)
+		gt.NoError(t, err)
+		gt.Equal(t, polls, 2)
+		gt.Equal(t, clock.Waits(), []time.Duration{time.Second})
+	})
+
+	t.Run("Error: index enters ERROR state", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc:  listIndexes("ERROR"),
+			GetTTLPolicyFunc: getTTL("ACTIVE"),
+		}
+
+		err := usecase.NewWait(mockClient, logger).Execute(ctx, config)
+		gt.Error(t, err).Contains("ERROR state")
+		gt.True(t, errors.Is(err, model.ErrIndexBuildFailed))
+	})
+
+	t.Run("Error: TTL policy needs repair", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc:  listIndexes("READY"),
+			GetTTLPolicyFunc: getTTL("NEEDS_REPAIR"),
+		}
+
+		err := usecase.NewWait(mockClient, logger).Execute(ctx, config)
+		gt.Error(t, err).Contains("NEEDS_REPAIR")
+	})
+
+	t.Run("Error: index does not exist", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return nil, nil
+			},
+			GetTTLPolicyFunc: getTTL("ACTIVE"),
+		}
+
+		err := usecase.NewWait(mockClient, logger).Execute(ctx, config)
+		gt.Error(t, err).Contains("run sync first")
+	})
+
+	t.Run("Error: context canceled while indexes are building", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				cancel()
+				return listIndexes("CREATING")(ctx, collectionID)
+			},
+			GetTTLPolicyFunc: getTTL("ACTIVE"),
+		}
+
+		err := usecase.NewWait(mockClient, logger, usecase.WaitWithClock(newFakeClock())).Execute(cctx, config)
+		gt.Error(t, err).Contains("stopped waiting")
+		gt.True(t, errors.Is(err, context.Canceled))
+		gt.False(t, errors.Is(err, model.ErrWaitTimeout))
+	})
+
+	t.Run("Error: deadline while indexes are building", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc:  listIndexes("CREATING"),
+			GetTTLPolicyFunc: getTTL("ACTIVE"),
+		}
+
+		clock := newFakeClock()
+		err := usecase.NewWait(mockClient, logger,
+			usecase.WaitWithClock(clock),
+			usecase.WaitWithStrategy(usecase.WaitStrategy{Deadline: time.Minute}),
+		).Execute(ctx, config)
+		gt.Error(t, err).Contains("stopped waiting")
+		gt.True(t, errors.Is(err, model.ErrWaitTimeout))
+		gt.False(t, errors.Is(err, context.DeadlineExceeded))
+
+		// 1s, 2s, 4s, 8s, 16s and 29s up to the deadline
+		var total time.Duration
+		for _, d := range clock.Waits() {
+			total += d
+		}
+		gt.Equal(t, total, time.Minute)
+		gt.Equal(t, len(clock.Waits()), 6)
+	})
+}
+
+func TestWait_ExecuteIndexes(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.Default()
+
+	const (
+		idx1 = "projects/test/databases/default/collectionGroups/users/indexes/idx1"
+		idx2 = "projects/test/databases/default/collectionGroups/users/indexes/idx2"
+	)
+	indexes := map[string][]string{"users": {idx1}}
+
+	t.Run("Normal: poll until the named index is ready", func(t *testing.T) {
+		polls := 0
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				polls++
+				state := "CREATING"
+				if polls >= 2 {
+					state = "READY"
+				}
+				// Indexes not in the journal are ignored whatever their state
+				return []interfaces.FirestoreIndex{
+					{Name: idx1, State: state},
+					{Name: idx2, State: "ERROR"},
+				}, nil
+			},
+		}
+
+		clock := newFakeClock()
+		err := usecase.NewWait(mockClient, logger, usecase.WaitWithClock(clock)).ExecuteIndexes(ctx, indexes)
+		gt.NoError(t, err)
+		gt.Equal(t, clock.Waits(), []time.Duration{time.Second})
+		gt.Equal(t, len(mockClient.ListIndexesCalls()), 2)
+		gt.Equal(t, mockClient.ListIndexesCalls()[0].CollectionID, "users")
+	})
+
+	t.Run("Error: named index enters ERROR state", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{{Name: idx1, State: "ERROR"}}, nil
+			},
+		}
+
+		err := usecase.NewWait(mockClient, logger).ExecuteIndexes(ctx, indexes)
+		gt.Error(t, err).Contains("ERROR")
+	})
+
+	t.Run("Error: named index does not exist", func(t *testing.T) {
+		mockClient := &mock.FirestoreClientMock{
+			ListIndexesFunc: func(ctx context.Context, collectionID string) ([]interfaces.FirestoreIndex, error) {
+				return []interfaces.FirestoreIndex{{Name: idx2, State: "READY"}}, nil
+			},
+		}
+
+		err := usecase.NewWait(mockClient, logger).ExecuteIndexes(ctx, indexes)
+		gt.Error(t, err).Contains("index does not exist")
+	})
+}
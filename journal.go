@@ -0,0 +1,199 @@
+package fireconf
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// Kinds of JournalOperation
+const (
+	JournalCreateIndex = "create_index"
+	JournalDeleteIndex = "delete_index"
+	JournalEnableTTL   = "enable_ttl"
+	JournalDisableTTL  = "disable_ttl"
+)
+
+// Journal records the operations submitted by Migrate, so that a migration
+// interrupted while indexes are building can be resumed with Client.Resume.
+// A Journal is an Observer; pass it to WithObserver to record a migration.
+type Journal struct {
+	Project  string `json:"project"`
+	Database string `json:"database"`
+	// SavedAt is the time the journal was last saved
+	SavedAt    time.Time          `json:"savedAt"`
+	Operations []JournalOperation `json:"operations"`
+
+	mu sync.Mutex
+}
+
+// JournalOperation is an operation submitted by Migrate
+type JournalOperation struct {
+	// Kind is JournalCreateIndex, JournalDeleteIndex, JournalEnableTTL or
+	// JournalDisableTTL
+	Kind       string `json:"kind"`
+	Collection string `json:"collection"`
+	// Index is the definition of a created or deleted index
+	Index string `json:"index,omitempty"`
+	// Name is the resource name of a created or deleted index
+	Name string `json:"name,omitempty"`
+	// Field is the field of a TTL change
+	Field string `json:"field,omitempty"`
+	// State is the last observed state of a created index
+	State string `json:"state,omitempty"`
+}
+
+// NewJournal creates an empty journal for a database
+func NewJournal(projectID, databaseID string) *Journal {
+	return &Journal{Project: projectID, Database: databaseID}
+}
+
+// LoadJournal loads a journal saved by Journal.Save
+func LoadJournal(path string) (*Journal, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is provided by user as CLI argument
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read journal file")
+	}
+
+	var journal Journal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, goerr.Wrap(err, "failed to parse journal", goerr.V("path", path))
+	}
+
+	return &journal, nil
+}
+
+// Save writes the journal to a JSON file
+func (j *Journal) Save(path string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.SavedAt = time.Now()
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal journal")
+	}
+
+	// #nosec G306 - the journal holds no secrets
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return goerr.Wrap(err, "failed to write journal file")
+	}
+
+	return nil
+}
+
+// Merge adds the operations of a previous journal of the same database that
+// are not recorded in j, e.g. so that a sync interrupted again keeps the
+// indexes left building by the first interruption. The operations of
+// previous come first, and j keeps its own record of an operation in both.
+func (j *Journal) Merge(previous *Journal) error {
+	if previous.Project != j.Project || previous.Database != j.Database {
+		return goerr.New("journal was written for another database",
+			goerr.V("project", previous.Project),
+			goerr.V("database", previous.Database))
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	recorded := make(map[JournalOperation]bool, len(j.Operations))
+	for _, op := range j.Operations {
+		recorded[op.key()] = true
+	}
+
+	var merged []JournalOperation
+	for _, op := range previous.Operations {
+		if !recorded[op.key()] {
+			merged = append(merged, op)
+		}
+	}
+	j.Operations = append(merged, j.Operations...)
+	return nil
+}
+
+// key identifies an operation regardless of the observed state
+func (op JournalOperation) key() JournalOperation {
+	op.State = ""
+	return op
+}
+
+// OnEvent records submitted operations and the states of created indexes
+func (j *Journal) OnEvent(event Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	switch e := event.(type) {
+	case IndexCreateSubmitted:
+		j.Operations = append(j.Operations, JournalOperation{
+			Kind:       JournalCreateIndex,
+			Collection: e.Collection,
+			Index:      e.Index.String(),
+			Name:       e.Name,
+			State:      "CREATING",
+		})
+	case IndexDeleteSubmitted:
+		j.Operations = append(j.Operations, JournalOperation{
+			Kind:       JournalDeleteIndex,
+			Collection: e.Collection,
+			Index:      e.Index.String(),
+			Name:       e.Name,
+		})
+	case IndexStateChanged:
+		for i := range j.Operations {
+			if j.Operations[i].Kind == JournalCreateIndex && j.Operations[i].Name == e.Name {
+				j.Operations[i].State = e.State
+			}
+		}
+	case TTLChanged:
+		kind := JournalEnableTTL
+		if e.Action == "disable" {
+			kind = JournalDisableTTL
+		}
+		j.Operations = append(j.Operations, JournalOperation{
+			Kind:       kind,
+			Collection: e.Collection,
+			Field:      e.Field,
+		})
+	}
+}
+
+// Building returns the created indexes that were not observed READY
+func (j *Journal) Building() []JournalOperation {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var result []JournalOperation
+	for _, op := range j.Operations {
+		if op.Kind == JournalCreateIndex && op.Name != "" && op.State != "READY" {
+			result = append(result, op)
+		}
+	}
+	return result
+}
+
+// Resume waits until the indexes that were still building when the journal
+// was saved are READY. It fails as soon as one of them is missing or fails
+// to build, when ctx is done, and with an error wrapping ErrWaitTimeout
+// beyond the timeouts of WithWaitStrategy. Run Migrate afterwards to apply
+// the changes that were not submitted before the interruption.
+func (c *Client) Resume(ctx context.Context, journal *Journal) error {
+	indexes := make(map[string][]string)
+	for _, op := range journal.Building() {
+		indexes[op.Collection] = append(indexes[op.Collection], op.Name)
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	wait := usecase.NewWait(c.client, c.logger, c.waitOptions()...)
+	if err := wait.ExecuteIndexes(ctx, indexes); err != nil {
+		return goerr.Wrap(err, "resume failed")
+	}
+
+	return nil
+}
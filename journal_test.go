@@ -0,0 +1,90 @@
+package fireconf_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/gt"
+)
+
+func TestJournal(t *testing.T) {
+	index := fireconf.Index{
+		Fields: []fireconf.IndexField{
+			{Path: "status", Order: fireconf.OrderAscending},
+			{Path: "createdAt", Order: fireconf.OrderDescending},
+		},
+		QueryScope: fireconf.QueryScopeCollection,
+	}
+
+	newJournal := func() *fireconf.Journal {
+		journal := fireconf.NewJournal("test-project", "(default)")
+		journal.OnEvent(fireconf.IndexCreateSubmitted{Collection: "users", Index: index, Name: "indexes/building"})
+		journal.OnEvent(fireconf.IndexCreateSubmitted{Collection: "posts", Index: index, Name: "indexes/ready"})
+		journal.OnEvent(fireconf.IndexStateChanged{Collection: "posts", Name: "indexes/ready", Previous: "CREATING", State: "READY"})
+		journal.OnEvent(fireconf.IndexDeleteSubmitted{Collection: "users", Index: index, Name: "indexes/stale"})
+		journal.OnEvent(fireconf.TTLChanged{Collection: "users", Field: "expireAt", Action: "enable"})
+		journal.OnEvent(fireconf.CollectionStarted{Collection: "users"})
+		return journal
+	}
+
+	t.Run("Normal: records submitted operations", func(t *testing.T) {
+		journal := newJournal()
+
+		gt.Equal(t, len(journal.Operations), 4)
+		gt.Equal(t, journal.Operations[2].Kind, fireconf.JournalDeleteIndex)
+		gt.Equal(t, journal.Operations[3], fireconf.JournalOperation{
+			Kind:       fireconf.JournalEnableTTL,
+			Collection: "users",
+			Field:      "expireAt",
+		})
+
+		building := journal.Building()
+		gt.Equal(t, len(building), 1)
+		gt.Equal(t, building[0].Collection, "users")
+		gt.Equal(t, building[0].Name, "indexes/building")
+		gt.Equal(t, building[0].State, "CREATING")
+		gt.Equal(t, building[0].Index, index.String())
+	})
+
+	t.Run("Normal: save and load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.json")
+		gt.NoError(t, newJournal().Save(path))
+
+		loaded := gt.R1(fireconf.LoadJournal(path)).NoError(t)
+		gt.Equal(t, loaded.Project, "test-project")
+		gt.Equal(t, loaded.Database, "(default)")
+		gt.False(t, loaded.SavedAt.IsZero())
+		gt.Equal(t, loaded.Operations, newJournal().Operations)
+	})
+
+	t.Run("Normal: merge keeps operations of a previous interruption", func(t *testing.T) {
+		previous := newJournal()
+
+		journal := fireconf.NewJournal("test-project", "(default)")
+		journal.OnEvent(fireconf.IndexCreateSubmitted{Collection: "tasks", Index: index, Name: "indexes/next"})
+		// Recorded by both, e.g. an index created again after being deleted
+		journal.OnEvent(fireconf.IndexCreateSubmitted{Collection: "users", Index: index, Name: "indexes/building"})
+		journal.OnEvent(fireconf.IndexStateChanged{Collection: "users", Name: "indexes/building", Previous: "CREATING", State: "READY"})
+
+		gt.NoError(t, journal.Merge(previous))
+		gt.Equal(t, len(journal.Operations), 5)
+		gt.Equal(t, journal.Operations[0].Name, "indexes/ready")
+		gt.Equal(t, journal.Operations[3].Name, "indexes/next")
+
+		building := journal.Building()
+		gt.Equal(t, len(building), 1)
+		gt.Equal(t, building[0].Name, "indexes/next")
+	})
+
+	t.Run("Error: merge journal of another database", func(t *testing.T) {
+		journal := fireconf.NewJournal("test-project", "other")
+		gt.Error(t, journal.Merge(newJournal()))
+		gt.Equal(t, len(journal.Operations), 0)
+	})
+
+	t.Run("Error: load missing journal", func(t *testing.T) {
+		_, err := fireconf.LoadJournal(filepath.Join(t.TempDir(), "missing.json"))
+		gt.Error(t, err)
+	})
+}
@@ -1 +1,96 @@
 package fireconf
+
+import (
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+)
+
+// SyncResult is the outcome of MigrateWithResult
+type SyncResult struct {
+	// DryRun is true if the changes were only reported, see WithDryRun
+	DryRun bool
+	// Collections are in configuration order. A collection is missing if
+	// the migration stopped before processing it.
+	Collections []CollectionResult
+	Duration    time.Duration
+}
+
+// CollectionResult is the outcome of migrating a collection
+type CollectionResult struct {
+	Name string
+	// Created are the indexes submitted for creation
+	Created []IndexResult
+	// Deleted are the indexes that are not in the configuration anymore
+	Deleted []IndexResult
+	// Repaired are the indexes recreated because of an ERROR or
+	// NEEDS_REPAIR state, see WithRepair
+	Repaired []IndexResult
+	// Unchanged are the existing indexes left as they are
+	Unchanged []IndexResult
+	// TTLAction is "enable", "disable", "move" (disabled on one field and
+	// enabled on another), or empty if the TTL policy was not changed
+	TTLAction string
+	// TTLField is the configured TTL field, or the disabled one if the TTL
+	// policy was removed
+	TTLField string
+	// TTLState is the last known state of the TTL policy of TTLField:
+	// CREATING, ACTIVE or NEEDS_REPAIR, empty if there is none
+	TTLState string
+	Duration time.Duration
+	// Err is the error that stopped the migration of the collection
+	Err error
+}
+
+// IndexResult is an index of a CollectionResult
+type IndexResult struct {
+	Index Index
+	// Name is the resource name, empty in dry run mode for new indexes
+	Name string
+	// State is the last observed state, e.g. CREATING when the index was not
+	// waited for with WithNoWait, or READY once built
+	State string
+}
+
+func convertSyncResultToPublic(result *usecase.SyncResult) *SyncResult {
+	if result == nil {
+		return nil
+	}
+
+	out := &SyncResult{
+		DryRun:      result.DryRun,
+		Collections: make([]CollectionResult, 0, len(result.Collections)),
+		Duration:    result.Duration,
+	}
+	for _, c := range result.Collections {
+		out.Collections = append(out.Collections, CollectionResult{
+			Name:      c.Name,
+			Created:   convertIndexResultsToPublic(c.Created),
+			Deleted:   convertIndexResultsToPublic(c.Deleted),
+			Repaired:  convertIndexResultsToPublic(c.Repaired),
+			Unchanged: convertIndexResultsToPublic(c.Unchanged),
+			TTLAction: c.TTLAction,
+			TTLField:  c.TTLField,
+			TTLState:  c.TTLState,
+			Duration:  c.Duration,
+			Err:       c.Err,
+		})
+	}
+	return out
+}
+
+func convertIndexResultsToPublic(indexes []usecase.IndexResult) []IndexResult {
+	if len(indexes) == 0 {
+		return nil
+	}
+	out := make([]IndexResult, len(indexes))
+	for i, idx := range indexes {
+		out[i] = IndexResult{
+			Index: convertIndexesToPublic([]model.Index{idx.Index})[0],
+			Name:  idx.Name,
+			State: idx.State,
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package fireconf
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/interfaces"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// Operation is a long-running index or field operation of the database, such
+// as an index build or a TTL policy change
+type Operation struct {
+	// ID is the short operation ID accepted by CancelOperation
+	ID string
+	// Name is the resource name of the operation
+	Name string
+	// Kind is "index" for index builds and "field" for single-field index
+	// and TTL changes
+	Kind       string
+	Collection string
+	// Target is the resource name of the index or field
+	Target string
+	// State is INITIALIZING, PROCESSING, CANCELLING, FINALIZING, SUCCESSFUL,
+	// FAILED or CANCELLED
+	State     string
+	Done      bool
+	Progress  OperationProgress
+	StartTime time.Time
+	// EndTime is zero while the operation is in progress
+	EndTime time.Time
+}
+
+// ListOperations lists the index and field operations of the database,
+// in-progress operations first. Finished operations are included only if all
+// is true.
+func (c *Client) ListOperations(ctx context.Context, all bool) ([]Operation, error) {
+	ops, err := usecase.NewOperations(c.client, c.logger).List(ctx, all)
+	if err != nil {
+		return nil, goerr.Wrap(err, "list operations failed")
+	}
+
+	result := make([]Operation, 0, len(ops))
+	for _, op := range ops {
+		result = append(result, convertOperationToPublic(op))
+	}
+	return result, nil
+}
+
+// CancelOperation requests cancellation of an in-progress index or field
+// operation, e.g. an index build started by mistake on a huge collection. id
+// is the ID or the resource name of the operation. Firestore stops the
+// operation on a best-effort basis.
+func (c *Client) CancelOperation(ctx context.Context, id string) (*Operation, error) {
+	op, err := usecase.NewOperations(c.client, c.logger).Cancel(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "cancel operation failed")
+	}
+
+	result := convertOperationToPublic(*op)
+	return &result, nil
+}
+
+func convertOperationToPublic(op interfaces.FirestoreOperation) Operation {
+	return Operation{
+		ID:         usecase.OperationID(op.Name),
+		Name:       op.Name,
+		Kind:       op.Kind,
+		Collection: op.Collection,
+		Target:     op.Target,
+		State:      op.State,
+		Done:       op.Done,
+		Progress: OperationProgress{
+			State:              op.State,
+			DocumentsCompleted: op.DocumentsCompleted,
+			DocumentsEstimated: op.DocumentsEstimated,
+			BytesCompleted:     op.BytesCompleted,
+			BytesEstimated:     op.BytesEstimated,
+		},
+		StartTime: op.StartTime,
+		EndTime:   op.EndTime,
+	}
+}
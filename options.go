@@ -1,6 +1,13 @@
 package fireconf
 
-import "log/slog"
+import (
+	"log/slog"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/firestore"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
 
 // options represents client options
 type options struct {
@@ -12,6 +19,135 @@ type options struct {
 
 	// DryRun if true, shows what would be changed without actually applying
 	DryRun bool
+
+	// CreateBeforeDelete if true, creates new indexes and waits for them to
+	// become READY before deleting stale indexes
+	CreateBeforeDelete bool
+
+	// RepairAttempts is the number of times an unhealthy index is recreated,
+	// 0 disables repair
+	RepairAttempts int
+
+	// NoWait if true, returns once changes are submitted without waiting for
+	// indexes to be built
+	NoWait bool
+
+	// WaitTTL if true, waits for TTL policy changes to be in effect
+	WaitTTL bool
+
+	// Observer receives progress events of Migrate (optional)
+	Observer Observer
+
+	// ContinueOnError if true, processes every collection even if others
+	// fail and reports all failures
+	ContinueOnError bool
+
+	// RetryPolicy configures retries of transient Admin API errors
+	RetryPolicy RetryPolicy
+
+	// Concurrency is the maximum number of Firestore API calls in flight
+	Concurrency int
+
+	// RateLimit is the maximum number of Firestore API calls started per
+	// second, 0 for no limit
+	RateLimit float64
+
+	// Clock provides the time for waiting (optional)
+	Clock Clock
+
+	// WaitStrategy configures polling while waiting for changes
+	WaitStrategy WaitStrategy
+
+	// FieldIndexState records the configurations of fields exempted for
+	// TTL policies (optional)
+	FieldIndexState *FieldIndexState
+}
+
+// RetryPolicy configures retries of Admin API calls failing with UNAVAILABLE,
+// RESOURCE_EXHAUSTED or ABORTED, and of reads such as listing indexes also
+// failing with INTERNAL or DEADLINE_EXCEEDED. Delays grow exponentially, and
+// each is chosen at random up to the current maximum (full jitter) so that
+// concurrent calls do not retry in lockstep. Zero fields take the default
+// values.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls including the first one
+	// (default: 5); 1 disables retries
+	MaxAttempts int
+	// InitialBackoff is the maximum delay before the first retry (default: 1s)
+	InitialBackoff time.Duration
+	// MaxBackoff caps the maximum delay between retries (default: 30s)
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the maximum delay grows per retry
+	// (default: 2)
+	Multiplier float64
+}
+
+// Clock provides the time while Migrate waits for indexes and TTL policies.
+// Tests can replace it to wait without sleeping.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After returns a channel receiving the time once d has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// WaitStrategy configures how Migrate polls while waiting for index builds,
+// index deletions and TTL policy changes, and how Wait and Resume poll. The
+// delay between polls starts at InitialBackoff and doubles up to MaxBackoff.
+// Zero fields take the default values.
+type WaitStrategy struct {
+	// InitialBackoff is the delay before the second poll (default: 1s)
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between polls (default: 10s, 30s for Wait
+	// and Resume, which check every index per poll)
+	MaxBackoff time.Duration
+	// Jitter is the fraction of each delay, between 0 and 1, that is
+	// randomly subtracted so that concurrent waits do not poll in lockstep
+	// (default: 0)
+	Jitter float64
+	// LogInterval is the interval of progress logs and WaitingTick events
+	// (default: 10s, 30s for Wait and Resume)
+	LogInterval time.Duration
+	// IndexTimeout bounds waiting for a single index build or deletion or
+	// TTL policy change, and the whole of Wait and Resume; 0 waits
+	// indefinitely
+	IndexTimeout time.Duration
+	// Deadline bounds the whole migration measured from its start and is
+	// checked while waiting, and bounds the whole of Wait and Resume; 0
+	// waits indefinitely
+	Deadline time.Duration
+}
+
+// toInternal converts the strategy to the one of the usecase package
+func (w WaitStrategy) toInternal() usecase.WaitStrategy {
+	return usecase.WaitStrategy{
+		InitialBackoff: w.InitialBackoff,
+		MaxBackoff:     w.MaxBackoff,
+		Jitter:         w.Jitter,
+		LogInterval:    w.LogInterval,
+		IndexTimeout:   w.IndexTimeout,
+		Deadline:       w.Deadline,
+	}
+}
+
+// validate checks the values of the strategy
+func (w WaitStrategy) validate() error {
+	durations := map[string]time.Duration{
+		"initialBackoff": w.InitialBackoff,
+		"maxBackoff":     w.MaxBackoff,
+		"logInterval":    w.LogInterval,
+		"indexTimeout":   w.IndexTimeout,
+		"deadline":       w.Deadline,
+	}
+	for name, d := range durations {
+		if d < 0 {
+			return goerr.New("wait strategy duration must not be negative", goerr.V("field", name), goerr.V("value", d))
+		}
+	}
+	if w.Jitter < 0 || w.Jitter > 1 {
+		return goerr.New("wait strategy jitter must be between 0 and 1", goerr.V("jitter", w.Jitter))
+	}
+	return nil
 }
 
 // Option is a function that configures options
@@ -38,10 +174,128 @@ func WithDryRun(dryRun bool) Option {
 	}
 }
 
+// WithCreateBeforeDelete enables zero-downtime index replacement: new
+// indexes are created and become READY before stale indexes are deleted, so
+// that changing an index (e.g. its field order) does not leave a window in
+// which queries relying on it fail. The default deletes stale indexes first.
+func WithCreateBeforeDelete(enabled bool) Option {
+	return func(o *options) {
+		o.CreateBeforeDelete = enabled
+	}
+}
+
+// WithRepair enables recreating indexes that match the configuration but are
+// in ERROR or NEEDS_REPAIR state. Such indexes otherwise satisfy the
+// configuration and are left as they are. An index that fails to build
+// again is recreated up to maxAttempts times in total; 0 disables repair.
+func WithRepair(maxAttempts int) Option {
+	return func(o *options) {
+		o.RepairAttempts = maxAttempts
+	}
+}
+
+// WithNoWait makes Migrate return as soon as index and TTL changes are
+// submitted instead of waiting for indexes to be built. Use Client.Wait to
+// wait for them later. Stale indexes are not deleted in this mode when
+// WithCreateBeforeDelete is enabled, since their replacements are not READY.
+func WithNoWait(enabled bool) Option {
+	return func(o *options) {
+		o.NoWait = enabled
+	}
+}
+
+// WithWaitTTL makes Migrate wait until enabled TTL policies are ACTIVE and
+// disabled ones are cleared. By default TTL changes are only submitted, and
+// Firestore applies them in the background. A TTL policy entering the
+// NEEDS_REPAIR state is reported as an error. Ignored with WithNoWait.
+func WithWaitTTL(enabled bool) Option {
+	return func(o *options) {
+		o.WaitTTL = enabled
+	}
+}
+
+// WithObserver sets an observer receiving structured progress events of
+// Migrate, e.g. to render a progress UI or to emit metrics, instead of
+// parsing log output. Events are delivered in addition to logging.
+func WithObserver(observer Observer) Option {
+	return func(o *options) {
+		o.Observer = observer
+	}
+}
+
+// WithContinueOnError makes Migrate process every collection even if others
+// fail, e.g. so that a single index rejected by Firestore does not abort
+// unrelated collections. Failures are returned together as *MigrationErrors.
+// By default the first failure cancels the remaining collections.
+func WithContinueOnError(enabled bool) Option {
+	return func(o *options) {
+		o.ContinueOnError = enabled
+	}
+}
+
+// WithRetryPolicy configures retries of Admin API calls failing with a
+// transient error such as a quota being exhausted. Retries are enabled by
+// default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.RetryPolicy = policy
+	}
+}
+
+// WithConcurrency sets the maximum number of Firestore API calls in flight
+// (default: 10). All calls of the client, e.g. index creations, state polls
+// of every collection and collection listing and creation, share this pool.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.Concurrency = n
+	}
+}
+
+// WithRateLimit limits the Firestore API calls of the client to perSecond
+// calls started per second, e.g. to stay within the Admin API quota of the
+// project. Retries count as calls. The default 0 does not limit the rate.
+func WithRateLimit(perSecond float64) Option {
+	return func(o *options) {
+		o.RateLimit = perSecond
+	}
+}
+
+// WithClock replaces the clock used while waiting for indexes and TTL
+// policies, e.g. with a fake clock in tests so that polling does not sleep.
+// Calls to Firestore are not affected.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.Clock = clock
+	}
+}
+
+// WithWaitStrategy configures how Migrate polls while waiting, e.g. longer
+// backoffs for databases whose indexes take hours to build, or timeouts so
+// that a stuck build fails instead of blocking forever. A timeout returns an
+// error wrapping ErrWaitTimeout.
+func WithWaitStrategy(strategy WaitStrategy) Option {
+	return func(o *options) {
+		o.WaitStrategy = strategy
+	}
+}
+
+// WithFieldIndexState makes Migrate record the single-field index
+// configuration of a TTL field in state before exempting it from indexing,
+// and restore the recorded configuration when the TTL policy is removed or
+// moved, also in later migrations using the same state. Without a state,
+// indexing is restored to the database defaults, and only while the TTL
+// policy still exists. Save the state after Migrate.
+func WithFieldIndexState(state *FieldIndexState) Option {
+	return func(o *options) {
+		o.FieldIndexState = state
+	}
+}
+
 // applyOptions applies option functions to options
 func applyOptions(opts []Option) *options {
 	o := &options{
-		Logger: slog.New(slog.DiscardHandler),
+		Logger:      slog.New(slog.DiscardHandler),
+		Concurrency: firestore.DefaultConcurrency,
 	}
 
 	for _, opt := range opts {
@@ -0,0 +1,139 @@
+package fireconf
+
+import (
+	"os"
+
+	"github.com/goccy/go-yaml"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// QuerySpec represents a set of query descriptions
+type QuerySpec struct {
+	Queries []Query `yaml:"queries"`
+}
+
+// Query describes the shape of a Firestore query. Only the fields and the
+// kind of each clause matter for index selection, not the filter values.
+type Query struct {
+	Name          string       `yaml:"name,omitempty"`
+	Collection    string       `yaml:"collection"`
+	QueryScope    QueryScope   `yaml:"queryScope,omitempty"`
+	Equality      []string     `yaml:"equality,omitempty"`
+	Range         []string     `yaml:"range,omitempty"`
+	ArrayContains string       `yaml:"arrayContains,omitempty"`
+	OrderBy       []QueryOrder `yaml:"orderBy,omitempty"`
+	FindNearest   *FindNearest `yaml:"findNearest,omitempty"`
+}
+
+// QueryOrder represents an orderBy clause of a query
+type QueryOrder struct {
+	Path  string `yaml:"path"`
+	Order Order  `yaml:"order,omitempty"`
+}
+
+// FindNearest represents a vector search clause of a query
+type FindNearest struct {
+	Path      string `yaml:"path"`
+	Dimension int    `yaml:"dimension,omitempty"`
+}
+
+// LoadQueriesFromYAML loads query descriptions from a YAML file
+func LoadQueriesFromYAML(path string) (*QuerySpec, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is provided by user as CLI argument
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read query file")
+	}
+
+	var spec QuerySpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, goerr.Wrap(err, "failed to parse YAML")
+	}
+
+	return &spec, nil
+}
+
+// DeriveIndexes computes the minimal set of composite indexes that serve all
+// of the given queries. Queries that are served by Firestore's automatic
+// single-field indexes do not produce an index.
+func DeriveIndexes(queries []Query) (*Config, error) {
+	return MergeDerivedIndexes(nil, queries)
+}
+
+// MergeDerivedIndexes returns a copy of base extended with the composite
+// indexes required by the queries. Indexes are only added for queries that
+// no index of base already serves. base may be nil.
+func MergeDerivedIndexes(base *Config, queries []Query) (*Config, error) {
+	var internalBase *model.Config
+	if base != nil {
+		internalBase = convertToInternalConfig(base)
+	}
+
+	derived, err := usecase.DeriveIndexes(internalBase, convertQueriesToInternal(queries))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to derive indexes")
+	}
+
+	return convertFromInternalConfig(derived), nil
+}
+
+// convertQueriesToInternal converts public queries to the internal model
+func convertQueriesToInternal(queries []Query) []model.Query {
+	result := make([]model.Query, len(queries))
+	for i, q := range queries {
+		query := model.Query{
+			Name:          q.Name,
+			Collection:    q.Collection,
+			QueryScope:    string(q.QueryScope),
+			Equality:      q.Equality,
+			Range:         q.Range,
+			ArrayContains: q.ArrayContains,
+		}
+
+		for _, order := range q.OrderBy {
+			query.OrderBy = append(query.OrderBy, model.QueryOrder{
+				Field: order.Path,
+				Order: string(order.Order),
+			})
+		}
+
+		if q.FindNearest != nil {
+			query.FindNearest = &model.FindNearest{
+				Field:     q.FindNearest.Path,
+				Dimension: q.FindNearest.Dimension,
+			}
+		}
+
+		result[i] = query
+	}
+	return result
+}
+
+// convertQueryFromInternal converts an internal query to the public API
+func convertQueryFromInternal(q model.Query) Query {
+	query := Query{
+		Name:          q.Name,
+		Collection:    q.Collection,
+		QueryScope:    QueryScope(q.QueryScope),
+		Equality:      q.Equality,
+		Range:         q.Range,
+		ArrayContains: q.ArrayContains,
+	}
+
+	for _, order := range q.OrderBy {
+		query.OrderBy = append(query.OrderBy, QueryOrder{
+			Path:  order.Field,
+			Order: Order(order.Order),
+		})
+	}
+
+	if q.FindNearest != nil {
+		query.FindNearest = &FindNearest{
+			Path:      q.FindNearest.Field,
+			Dimension: q.FindNearest.Dimension,
+		}
+	}
+
+	return query
+}
@@ -0,0 +1,105 @@
+package fireconf_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/gt"
+)
+
+const testQueriesYAML = `queries:
+  - name: ordersByCustomer
+    collection: orders
+    equality: [customerId]
+    orderBy:
+      - path: createdAt
+        order: DESCENDING
+  - name: ordersByStatus
+    collection: orders
+    equality: [status]
+  - name: similarDocuments
+    collection: documents
+    equality: [category]
+    findNearest:
+      path: embedding
+      dimension: 768
+`
+
+func TestDeriveIndexes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queries.yaml")
+	gt.NoError(t, os.WriteFile(path, []byte(testQueriesYAML), 0600))
+
+	spec := gt.R1(fireconf.LoadQueriesFromYAML(path)).NoError(t)
+	gt.Equal(t, len(spec.Queries), 3)
+
+	t.Run("derive from scratch", func(t *testing.T) {
+		config := gt.R1(fireconf.DeriveIndexes(spec.Queries)).NoError(t)
+		gt.NoError(t, config.Validate())
+		gt.Equal(t, len(config.Collections), 2)
+
+		orders := config.Collections[0]
+		gt.Equal(t, orders.Name, "orders")
+		gt.Equal(t, indexKeys(orders.Indexes), []string{"COLLECTION|customerId:ASCENDING|createdAt:DESCENDING"})
+
+		documents := config.Collections[1]
+		gt.Equal(t, documents.Name, "documents")
+		gt.Equal(t, indexKeys(documents.Indexes), []string{"COLLECTION|category:ASCENDING|embedding:VECTOR:768"})
+	})
+
+	t.Run("merge into existing config", func(t *testing.T) {
+		base := &fireconf.Config{
+			Collections: []fireconf.Collection{
+				{
+					Name:    "orders",
+					Indexes: []fireconf.Index{idx("customerId:ASCENDING", "createdAt:DESCENDING")},
+					TTL:     &fireconf.TTL{Field: "expireAt"},
+				},
+			},
+		}
+
+		config := gt.R1(fireconf.MergeDerivedIndexes(base, spec.Queries)).NoError(t)
+		gt.Equal(t, len(config.Collections), 2)
+		gt.Equal(t, len(config.Collections[0].Indexes), 1)
+		gt.Equal(t, config.Collections[0].TTL.Field, "expireAt")
+		gt.Equal(t, config.Collections[1].Name, "documents")
+	})
+}
+
+func TestConfig_CheckQueries(t *testing.T) {
+	config := singleCollection("orders", idx("status:ASCENDING", "createdAt:DESCENDING"))
+
+	queries := []fireconf.Query{
+		{
+			Name:       "byStatus",
+			Collection: "orders",
+			Equality:   []string{"status"},
+			OrderBy:    []fireconf.QueryOrder{{Path: "createdAt", Order: fireconf.OrderDescending}},
+		},
+		{
+			Name:       "byStatusOnly",
+			Collection: "orders",
+			Equality:   []string{"status"},
+		},
+		{
+			Name:       "byCustomer",
+			Collection: "orders",
+			Equality:   []string{"customerId"},
+			OrderBy:    []fireconf.QueryOrder{{Path: "createdAt", Order: fireconf.OrderDescending}},
+		},
+	}
+
+	report := gt.R1(config.CheckQueries(queries)).NoError(t)
+	gt.Equal(t, len(report.Queries), 3)
+	gt.True(t, report.Queries[0].Served)
+	gt.Equal(t, report.Queries[0].Index.String(), "(status ASC, createdAt DESC)")
+	gt.True(t, report.Queries[1].SingleField)
+
+	uncovered := report.Uncovered()
+	gt.Equal(t, len(uncovered), 1)
+	gt.Equal(t, uncovered[0].Query.Name, "byCustomer")
+	gt.Equal(t, uncovered[0].Required.String(), "(customerId ASC, createdAt DESC)")
+	gt.True(t, strings.Contains(uncovered[0].Reason, "customerId"))
+}
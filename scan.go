@@ -0,0 +1,63 @@
+package fireconf
+
+import (
+	"github.com/m-mizutani/fireconf/internal/adapter/scanner"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// SourceQuery is a Firestore query found in application source code
+type SourceQuery struct {
+	Query Query
+	// Position is the file:line of the query expression
+	Position string
+	// Unresolved explains why the query shape could not be fully determined,
+	// e.g. because a field path is not a constant. Empty if fully resolved.
+	Unresolved string
+}
+
+// ScanGoQueries statically analyzes the Go packages matching the patterns
+// (e.g. "./...", relative to dir) and returns the shape of every
+// cloud.google.com/go/firestore query chain built from Collection or
+// CollectionGroup with Where, OrderBy and FindNearest calls.
+//
+// The vector dimension of a FindNearest query is only known if the query
+// vector is a literal; otherwise it is 0, which matches any dimension in
+// coverage checks.
+func ScanGoQueries(dir string, patterns ...string) ([]SourceQuery, error) {
+	found, err := scanner.ScanGo(dir, patterns...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to scan Go source", goerr.V("dir", dir))
+	}
+	return convertSourceQueries(found), nil
+}
+
+// ScanJSQueries extracts the shape of Firestore queries from the JavaScript
+// and TypeScript files under the paths. Both the modular Web SDK
+// (query(collection(db, "orders"), where(...), orderBy(...))) and method
+// chains of the namespaced Web SDK and the Admin SDK
+// (db.collection("orders").where(...).orderBy(...)) are recognized.
+//
+// The scanner works on tokens rather than a full syntax tree, so only
+// queries written with literal or const-declared collection names and field
+// paths are resolved.
+func ScanJSQueries(paths ...string) ([]SourceQuery, error) {
+	found, err := scanner.ScanJS(paths...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to scan JavaScript source", goerr.V("paths", paths))
+	}
+	return convertSourceQueries(found), nil
+}
+
+// convertSourceQueries converts internal source queries to the public API
+func convertSourceQueries(found []model.SourceQuery) []SourceQuery {
+	result := make([]SourceQuery, len(found))
+	for i, f := range found {
+		result[i] = SourceQuery{
+			Query:      convertQueryFromInternal(f.Query),
+			Position:   f.Position,
+			Unresolved: f.Unresolved,
+		}
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package fireconf
+
+import (
+	"context"
+
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// Presence tells whether an index or TTL policy is in the configuration,
+// in Firestore, or both
+type Presence string
+
+const (
+	// PresenceManaged is in the configuration and in Firestore
+	PresenceManaged Presence = "managed"
+	// PresenceMissing is in the configuration but not in Firestore
+	PresenceMissing Presence = "missing"
+	// PresenceUnmanaged is in Firestore but not in the configuration
+	PresenceUnmanaged Presence = "unmanaged"
+)
+
+// IndexStatus is the live state of a composite index
+type IndexStatus struct {
+	Index Index
+	// Name is the resource name, empty if the index is missing
+	Name string
+	// State is CREATING, READY, NEEDS_REPAIR or ERROR, empty if missing
+	State    string
+	Presence Presence
+}
+
+// TTLStatus is the live state of a TTL policy
+type TTLStatus struct {
+	Field string
+	// State is CREATING, ACTIVE or NEEDS_REPAIR, empty if missing
+	State    string
+	Presence Presence
+}
+
+// CollectionStatus is the live state of the indexes and TTL policies of a
+// collection
+type CollectionStatus struct {
+	Name string
+	// Indexes lists configured indexes in configuration order, followed by
+	// unmanaged indexes
+	Indexes []IndexStatus
+	// TTL has two entries when the configured TTL field differs from the
+	// live one
+	TTL []TTLStatus
+}
+
+// Status reports the live state of the indexes and TTL policies of the given
+// collections and whether each is in the configuration passed to New. Without
+// collections, the configured collections and those discovered in Firestore
+// are reported. The configuration may be nil, in which case everything is
+// unmanaged.
+func (c *Client) Status(ctx context.Context, collections ...string) ([]CollectionStatus, error) {
+	var internalConfig *model.Config
+	if c.config != nil {
+		internalConfig = convertToInternalConfig(c.config)
+	}
+
+	status := usecase.NewStatus(c.client, c.logger)
+	result, err := status.Execute(ctx, internalConfig, collections)
+	if err != nil {
+		return nil, goerr.Wrap(err, "status failed")
+	}
+
+	out := make([]CollectionStatus, 0, len(result))
+	for _, col := range result {
+		cs := CollectionStatus{Name: col.Name}
+		for _, idx := range col.Indexes {
+			cs.Indexes = append(cs.Indexes, IndexStatus{
+				Index:    convertIndexesToPublic([]model.Index{idx.Index})[0],
+				Name:     idx.Name,
+				State:    idx.State,
+				Presence: Presence(idx.Presence),
+			})
+		}
+		for _, ttl := range col.TTL {
+			cs.TTL = append(cs.TTL, TTLStatus{
+				Field:    ttl.Field,
+				State:    ttl.State,
+				Presence: Presence(ttl.Presence),
+			})
+		}
+		out = append(out, cs)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,140 @@
+package fireconf
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/scanner"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// timeType is not descended into when flattening nested structs
+var timeType = reflect.TypeOf(time.Time{})
+
+// ConfigFromTypes builds a configuration from document structs annotated
+// with fireconf struct tags. Each value is a struct or a pointer to one, e.g.
+// ConfigFromTypes(Order{}, (*User)(nil)). Field paths follow the firestore
+// struct tag like the Firestore client library, and nested structs are
+// flattened into dotted paths.
+//
+//	type Order struct {
+//	    _          struct{}  `fireconf:"collection=orders"`
+//	    CustomerID string    `firestore:"customerId" fireconf:"index=byCustomer,0"`
+//	    CreatedAt  time.Time `firestore:"createdAt" fireconf:"index=byCustomer,1,desc"`
+//	    ExpireAt   time.Time `firestore:"expireAt" fireconf:"ttl"`
+//	    Embedding  []float32 `firestore:"embedding" fireconf:"vector=768"`
+//	}
+//
+// Directives are separated by semicolons. index=NAME,POS[,OPTS...] places
+// the field at position POS of the composite index NAME, where OPTS are asc
+// (default), desc, contains (array-contains) and group (collection group
+// scope). vector=DIM marks a vector field, which forms a vector index of its
+// own unless it is also part of a named index.
+func ConfigFromTypes(values ...any) (*Config, error) {
+	structs := make([]model.TaggedStruct, 0, len(values))
+	for _, v := range values {
+		t, ok := v.(reflect.Type)
+		if !ok {
+			t = reflect.TypeOf(v)
+		}
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return nil, goerr.New("ConfigFromTypes requires struct types", goerr.V("type", t))
+		}
+
+		s := model.TaggedStruct{Name: t.String()}
+		s.Fields = taggedFields(t, "", t.Name(), map[reflect.Type]bool{t: true})
+		structs = append(structs, s)
+	}
+
+	config, err := usecase.ConfigFromStructs(structs)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to build configuration from types")
+	}
+	return convertFromInternalConfig(config), nil
+}
+
+// taggedFields collects the fields of t carrying a fireconf tag, including
+// those of nested structs. visiting guards against recursive types.
+func taggedFields(t reflect.Type, prefix, source string, visiting map[reflect.Type]bool) []model.TaggedField {
+	var result []model.TaggedField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup("fireconf")
+
+		if sf.Name == "_" {
+			if hasTag {
+				result = append(result, model.TaggedField{Tag: tag, Source: source + "._"})
+			}
+			continue
+		}
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("firestore"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if hasTag {
+			path := name
+			if path == "" {
+				path = sf.Name
+			}
+			result = append(result, model.TaggedField{Path: prefix + path, Tag: tag, Source: source + "." + sf.Name})
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct || ft == timeType || visiting[ft] {
+			continue
+		}
+
+		// Embedded structs without a name are promoted like in the
+		// Firestore client library; other structs become nested maps
+		nested := prefix
+		if !sf.Anonymous || name != "" {
+			if name == "" {
+				name = sf.Name
+			}
+			nested = prefix + name + "."
+		}
+		visiting[ft] = true
+		result = append(result, taggedFields(ft, nested, source+"."+sf.Name, visiting)...)
+		delete(visiting, ft)
+	}
+	return result
+}
+
+// ConfigFromSource builds a configuration from the fireconf struct tags of
+// the Go packages matching the patterns (e.g. "./...", relative to dir),
+// without compiling them into the program. Only struct types with a
+// collection directive are documents; other tagged structs are taken into
+// account where they are nested in a document.
+func ConfigFromSource(dir string, patterns ...string) (*Config, error) {
+	found, err := scanner.ScanGoStructs(dir, patterns...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to scan Go source", goerr.V("dir", dir))
+	}
+
+	var structs []model.TaggedStruct
+	for _, s := range found {
+		if usecase.IsDocumentStruct(s) {
+			structs = append(structs, s)
+		}
+	}
+
+	config, err := usecase.ConfigFromStructs(structs)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to build configuration from source")
+	}
+	return convertFromInternalConfig(config), nil
+}
@@ -0,0 +1,92 @@
+package fireconf_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/gt"
+)
+
+type orderStats struct {
+	Total int `firestore:"total" fireconf:"index=byStatus,1"`
+}
+
+type Order struct {
+	_          struct{}   `fireconf:"collection=orders"`
+	CustomerID string     `firestore:"customerId" fireconf:"index=byCustomer,0"`
+	Status     string     `firestore:"status" fireconf:"index=byStatus,0,group"`
+	CreatedAt  time.Time  `firestore:"createdAt" fireconf:"index=byCustomer,1,desc"`
+	Stats      orderStats `firestore:"stats"`
+	ExpireAt   time.Time  `firestore:"expireAt" fireconf:"ttl"`
+	Embedding  []float32  `fireconf:"vector=768"`
+	Ignored    string     `firestore:"-" fireconf:"index=byCustomer,2"`
+}
+
+type OrderArchive struct {
+	_          struct{}  `fireconf:"collection=orders"`
+	CustomerID string    `firestore:"customerId" fireconf:"index=byCustomer,0"`
+	CreatedAt  time.Time `firestore:"createdAt" fireconf:"index=byCustomer,1,desc;index=byTag,1,desc"`
+	Tags       []string  `firestore:"tags" fireconf:"index=byCustomer,2,contains;index=byTag,0,contains"`
+}
+
+func TestConfigFromTypes(t *testing.T) {
+	config := gt.R1(fireconf.ConfigFromTypes(Order{}, (*OrderArchive)(nil))).NoError(t)
+	gt.NoError(t, config.Validate())
+	gt.Equal(t, len(config.Collections), 1)
+
+	orders := config.Collections[0]
+	gt.Equal(t, orders.Name, "orders")
+	gt.Equal(t, orders.TTL, &fireconf.TTL{Field: "expireAt"})
+
+	var indexes []string
+	for _, index := range orders.Indexes {
+		indexes = append(indexes, index.String())
+	}
+	gt.Equal(t, indexes, []string{
+		"(Embedding VECTOR(768))",
+		"(customerId ASC, createdAt DESC)",
+		"(status ASC, stats.total ASC) COLLECTION_GROUP",
+		"(customerId ASC, createdAt DESC, tags CONTAINS)",
+		"(tags CONTAINS, createdAt DESC)",
+	})
+
+	t.Run("Struct without collection", func(t *testing.T) {
+		_, err := fireconf.ConfigFromTypes(orderStats{})
+		gt.Error(t, err)
+	})
+
+	t.Run("Non-struct type", func(t *testing.T) {
+		_, err := fireconf.ConfigFromTypes("orders")
+		gt.Error(t, err)
+	})
+
+	t.Run("Invalid tags", func(t *testing.T) {
+		type unknownDirective struct {
+			_ struct{} `fireconf:"collection=a;sharded"`
+		}
+		type duplicatePosition struct {
+			_ struct{} `fireconf:"collection=a"`
+			A string   `fireconf:"index=x,0"`
+			B string   `fireconf:"index=x,0"`
+		}
+		type twoTTLs struct {
+			_ struct{}  `fireconf:"collection=a"`
+			A time.Time `fireconf:"ttl"`
+			B time.Time `fireconf:"ttl"`
+		}
+		type singleField struct {
+			_ struct{} `fireconf:"collection=a"`
+			A string   `fireconf:"index=x,0"`
+		}
+		type badVector struct {
+			_ struct{}  `fireconf:"collection=a"`
+			A []float32 `fireconf:"vector=many"`
+		}
+
+		for _, v := range []any{unknownDirective{}, duplicatePosition{}, twoTTLs{}, singleField{}, badVector{}} {
+			_, err := fireconf.ConfigFromTypes(v)
+			gt.Error(t, err)
+		}
+	})
+}
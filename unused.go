@@ -0,0 +1,77 @@
+package fireconf
+
+import (
+	"io"
+
+	"github.com/m-mizutani/fireconf/internal/adapter/querylog"
+	"github.com/m-mizutani/fireconf/internal/model"
+	"github.com/m-mizutani/fireconf/internal/usecase"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// IndexUsage reports how many logged queries a composite index served
+type IndexUsage struct {
+	Collection string
+	Index      Index
+	Count      int
+}
+
+// UsageReport is the result of matching a query log to a configuration
+type UsageReport struct {
+	// Indexes lists every composite index of the configuration
+	Indexes []IndexUsage
+	// SingleField counts queries served by single-field indexes
+	SingleField int
+	// Unmatched counts queries served by an index that is not in the
+	// configuration, or by no index at all
+	Unmatched int
+	// Skipped counts log lines that are neither query explain output nor a
+	// query shape
+	Skipped int
+}
+
+// Unused returns the composite indexes that served no logged query
+func (r *UsageReport) Unused() []IndexUsage {
+	var result []IndexUsage
+	for _, u := range r.Indexes {
+		if u.Count == 0 {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// AnalyzeQueryLog reads executed queries from JSON lines and reports how
+// often each composite index of the configuration served them. A line is
+// either query explain output (explainMetrics.planSummary.indexesUsed,
+// optionally with a "collection" key) or a query shape using the keys of
+// the query description file. Either may be nested in a log envelope.
+//
+// Indexes with a zero count were not used within the time window covered by
+// the log and are candidates for removal.
+func (c *Config) AnalyzeQueryLog(r io.Reader) (*UsageReport, error) {
+	usages, skipped, err := querylog.Parse(r)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to parse query log")
+	}
+
+	result, err := usecase.AnalyzeIndexUsage(convertToInternalConfig(c), usages)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to analyze index usage")
+	}
+
+	report := &UsageReport{
+		SingleField: result.SingleField,
+		Unmatched:   result.Unmatched,
+		Skipped:     skipped,
+	}
+	for _, u := range result.Indexes {
+		report.Indexes = append(report.Indexes, IndexUsage{
+			Collection: u.Collection,
+			Index:      convertIndexesToPublic([]model.Index{u.Index})[0],
+			Count:      u.Count,
+		})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,63 @@
+package fireconf_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/fireconf"
+	"github.com/m-mizutani/gt"
+)
+
+func TestConfig_AnalyzeQueryLog(t *testing.T) {
+	config := &fireconf.Config{
+		Collections: []fireconf.Collection{
+			{
+				Name: "orders",
+				Indexes: []fireconf.Index{
+					idx("customerId:ASCENDING", "createdAt:DESCENDING"),
+					idx("status:ASCENDING", "total:ASCENDING"),
+				},
+			},
+			{
+				Name: "documents",
+				Indexes: []fireconf.Index{
+					vecIdx(fireconf.QueryScopeCollection, "embedding", 768, "category"),
+				},
+			},
+		},
+	}
+
+	log := strings.Join([]string{
+		// Query explain output nested in a Cloud Logging envelope
+		`{"jsonPayload":{"collection":"orders","explainMetrics":{"planSummary":{"indexesUsed":[{"query_scope":"Collection","properties":"(customerId ASC, createdAt DESC, __name__ DESC)"}]}}}}`,
+		// Go client library spelling without collection
+		`{"PlanSummary":{"IndexesUsed":[{"query_scope":"Collection","properties":"(category ASC, embedding VECTOR<768>)"}]}}`,
+		// Single-field index
+		`{"explainMetrics":{"planSummary":{"indexesUsed":[{"query_scope":"Collection","properties":"(status ASC, __name__ ASC)"}]}}}`,
+		// Query shape
+		`{"collection":"orders","equality":["customerId"],"orderBy":[{"path":"createdAt","order":"DESCENDING"}]}`,
+		// Query shape needing an index that is not configured
+		`{"collection":"orders","equality":["status"],"orderBy":[{"path":"createdAt"}]}`,
+		`plain text line`,
+		``,
+	}, "\n")
+
+	report := gt.R1(config.AnalyzeQueryLog(strings.NewReader(log))).NoError(t)
+	gt.Equal(t, len(report.Indexes), 3)
+	gt.Equal(t, report.Indexes[0].Count, 2)
+	gt.Equal(t, report.Indexes[1].Count, 0)
+	gt.Equal(t, report.Indexes[2].Count, 1)
+	gt.Equal(t, report.SingleField, 1)
+	gt.Equal(t, report.Unmatched, 1)
+	gt.Equal(t, report.Skipped, 1)
+
+	unused := report.Unused()
+	gt.Equal(t, len(unused), 1)
+	gt.Equal(t, unused[0].Collection, "orders")
+	gt.Equal(t, unused[0].Index.String(), "(status ASC, total ASC)")
+
+	t.Run("Invalid explain properties", func(t *testing.T) {
+		_, err := config.AnalyzeQueryLog(strings.NewReader(`{"indexesUsed":[{"properties":"(a SIDEWAYS)"}]}`))
+		gt.Error(t, err)
+	})
+}